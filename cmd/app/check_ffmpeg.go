@@ -21,17 +21,17 @@
 package main
 
 import (
+	"errors"
 	"os/exec"
-
-	"github.com/Laky-64/gologging"
 )
 
-func checkFFmpegAndFFprobe() {
+func checkFFmpegAndFFprobe() error {
 	if _, err := exec.LookPath("ffmpeg"); err != nil {
-		gologging.Fatal("❌ ffmpeg not found in PATH. Please install ffmpeg.")
+		return errors.New("ffmpeg not found in PATH. Please install ffmpeg")
 	}
 
 	if _, err := exec.LookPath("ffprobe"); err != nil {
-		gologging.Fatal("❌ ffprobe not found in PATH. Please install ffprobe.")
+		return errors.New("ffprobe not found in PATH. Please install ffprobe")
 	}
+	return nil
 }
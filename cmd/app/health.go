@@ -0,0 +1,91 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/health"
+)
+
+var errDisconnected = errors.New("client is not connected")
+
+const (
+	botPingInterval = 30 * time.Second
+	// A few missed pings are tolerated before /healthz reports a stall.
+	botPingTimeout = 3 * time.Minute
+)
+
+func registerHealthChecks() {
+	// Liveness follows a real round-trip through the bot's client, so a
+	// hung connection or dispatcher shows up in /healthz.
+	botBeat := health.NewHeartbeat("bot", botPingTimeout)
+	go func() {
+		ticker := time.NewTicker(botPingInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := core.Bot.GetMe(); err != nil {
+				logger.WarnF("Health ping failed: %v", err)
+				continue
+			}
+			botBeat.Beat()
+		}
+	}()
+
+	health.Register("mongo", database.Ping)
+	health.Register("ffmpeg", func(context.Context) error {
+		return checkFFmpegAndFFprobe()
+	})
+	health.Register("bot", func(context.Context) error {
+		if core.Bot == nil || !core.Bot.IsConnected() {
+			return errDisconnected
+		}
+		return nil
+	})
+
+	// One check for all assistants, since they can be added and removed
+	// while running.
+	health.Register("assistants", func(context.Context) error {
+		var failing []string
+		core.Assistants.ForEach(func(a *core.Assistant) {
+			switch {
+			case !a.Client.IsConnected():
+				failing = append(failing, fmt.Sprintf("assistant %d: %v", a.Index()+1, errDisconnected))
+			case !a.Healthy():
+				reason, since := a.HealthStatus()
+				failing = append(failing, fmt.Sprintf(
+					"assistant %d: down since %s: %s",
+					a.Index()+1, since.Format(time.RFC3339), reason,
+				))
+			}
+		})
+		if len(failing) > 0 {
+			return errors.New(strings.Join(failing, "; "))
+		}
+		return nil
+	})
+}
@@ -36,26 +36,39 @@ import "C"
 import (
 	"os"
 
-	"github.com/Laky-64/gologging"
-
+	"main/internal/api"
 	"main/internal/config"
 	"main/internal/core"
 	"main/internal/database"
+	"main/internal/health"
+	"main/internal/metrics"
 	"main/internal/modules"
+	"main/internal/radio"
+	"main/internal/server"
 )
 
+var logger = config.GetLogger(config.DefaultLogger)
+
 func main() {
 	initLogger()
 	defer config.CloseLogging()
 
-	checkFFmpegAndFFprobe()
+	if err := checkFFmpegAndFFprobe(); err != nil {
+		logger.Fatal("❌ " + err.Error())
+	}
 	refreshCacheAndDownloads()
 
-	gologging.Debug("🔹 Initializing MongoDB...")
-	dbCleanup := database.Init(config.MongoURI)
+	logger.Debug("🔹 Initializing database...")
+	dbDSN := config.MongoURI
+	if config.DBBackend == database.BackendBolt {
+		dbDSN = config.DBPath
+	}
+	database.DefaultLang = config.DefaultLang
+	database.SessionSecret = config.SessionSecret
+	dbCleanup := database.Init(config.DBBackend, dbDSN)
 	defer dbCleanup()
-	gologging.Info("✅ Database connected successfully")
-	gologging.Debug("🔹 Initializing clients...")
+	logger.Info("✅ Database connected successfully")
+	logger.Debug("🔹 Initializing clients...")
 	cleanup := core.Init(
 		config.ApiID,
 		config.ApiHash,
@@ -67,29 +80,59 @@ func main() {
 	defer cleanup()
 
 	core.AssistantIndexFunc = database.GetAssistantIndex
+	core.AssistantReassignFunc = database.ReassignAssistant
+	core.AssistantRemoveFunc = database.RemoveAssistantIndex
+	database.AssistantLoadFunc = core.Assistants.LoadScore
 	core.GetChatLanguage = database.GetChatLanguage
+	core.CleanNowPlaying = database.CleanNowPlaying
+
+	startStoredAssistants()
 
 	if err := database.RebalanceAssistantIndexes(core.Assistants.Count()); err != nil {
-		gologging.Fatal("Failed to rebalance Assistants: " + err.Error())
+		logger.Fatal("Failed to rebalance Assistants: " + err.Error())
 	}
 
 	modules.Init(core.Bot, core.Assistants)
-	core.Bot.Idle()
-}
 
-func initLogger() {
-	gologging.SetLevel(gologging.DebugLevel)
-	gologging.SetOutput(config.LogWriter)
+	if config.RadioEnabled {
+		radio.Init()
+	}
+	if config.APIEnabled {
+		api.Init()
+	}
+	if config.MetricsEnabled {
+		metrics.Init()
+	}
+	if config.HealthEnabled {
+		registerHealthChecks()
+		health.Init()
+	}
+	stopServer := server.Start(config.HTTPPort)
+	defer stopServer()
 
-	l := gologging.GetLogger("ntgcalls")
-	l.SetLevel(gologging.ErrorLevel)
-	l.SetOutput(config.LogWriter)
+	core.Bot.Idle()
+}
 
-	l = gologging.GetLogger("webrtc")
-	l.SetLevel(gologging.FatalLevel)
-	l.SetOutput(config.LogWriter)
+// startStoredAssistants starts the assistants added with /addassistant. A
+// session that no longer logs in is skipped, not fatal.
+func startStoredAssistants() {
+	sessions, err := database.GetAssistantSessions()
+	if err != nil {
+		logger.Error("Failed to load stored assistants: " + err.Error())
+		return
+	}
+	for _, s := range sessions {
+		a, err := core.Assistants.Add(s.Session, s.Type)
+		if err != nil {
+			logger.ErrorF("Failed to start stored assistant %d: %v", s.UserID, err)
+			continue
+		}
+		logger.InfoF("Stored assistant %d started as assistant=%d", s.UserID, a.Index()+1)
+	}
+}
 
-	gologging.GetLogger("Database").SetOutput(config.LogWriter)
+func initLogger() {
+	config.SetupLoggers()
 }
 
 func refreshCacheAndDownloads() error {
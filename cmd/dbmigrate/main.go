@@ -0,0 +1,88 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Command dbmigrate copies all bot data from one storage backend to another,
+// e.g. from MongoDB to an embedded bolt file:
+//
+//	go run ./cmd/dbmigrate -from mongo -from-dsn "$MONGO_DB_URI" \
+//		-to bolt -to-dsn data/yukki.db
+//
+// Stop the bot first; documents that already exist in the target are
+// overwritten.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"main/internal/database"
+)
+
+func main() {
+	from := flag.String("from", database.BackendMongo, "source backend (mongo or bolt)")
+	fromDSN := flag.String("from-dsn", "", "source MongoDB URI or bolt file path")
+	to := flag.String("to", database.BackendBolt, "target backend (mongo or bolt)")
+	toDSN := flag.String("to-dsn", "", "target MongoDB URI or bolt file path")
+	flag.Parse()
+
+	if *fromDSN == "" || *toDSN == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *from == *to && *fromDSN == *toDSN {
+		fail("source and target are the same database")
+	}
+
+	src, err := database.Open(*from, *fromDSN)
+	if err != nil {
+		fail("open source: %v", err)
+	}
+	dst, err := database.Open(*to, *toDSN)
+	if err != nil {
+		fail("open target: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	stats, err := database.Copy(ctx, src, dst)
+	src.Close(ctx)
+	if cerr := dst.Close(ctx); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fail("copy: %v", err)
+	}
+
+	fmt.Printf(
+		"copied %d chats, %d api keys, %d locale packs, bot state: %v, schema v%d\n",
+		stats.Chats, stats.APIKeys, stats.LocalePacks, stats.BotState,
+		stats.SchemaVersion,
+	)
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "dbmigrate: "+format+"\n", args...)
+	os.Exit(1)
+}
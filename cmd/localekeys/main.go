@@ -0,0 +1,222 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Command localekeys checks the default locale against the source code. It
+// reports keys the code asks for that the locale lacks, and keys of the
+// locale that appear nowhere in the code:
+//
+//	go run ./cmd/localekeys
+//
+// A key counts as used when any string literal equals it or starts a
+// concatenation it matches ("filter_rule_" + kind), so keys picked at
+// runtime are not reported as unused. The exit status is 1 when anything
+// is reported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keyFuncs take the locale key as their second argument.
+var keyFuncs = map[string]bool{"F": true, "FWithLang": true, "Get": true}
+
+type scan struct {
+	literals map[string]bool
+	prefixes []string
+	// asked maps keys passed literally to keyFuncs to their first position.
+	asked map[string]string
+}
+
+func main() {
+	src := flag.String("src", ".", "root of the Go sources to scan")
+	dir := flag.String("locales", "internal/locales", "directory of the locale files")
+	lang := flag.String("lang", "en", "default language")
+	flag.Parse()
+
+	keys, err := loadKeys(*dir, *lang)
+	if err != nil {
+		fail("load locale: %v", err)
+	}
+	s, err := scanSources(*src)
+	if err != nil {
+		fail("scan sources: %v", err)
+	}
+
+	var missing, unused []string
+	for key, pos := range s.asked {
+		if !keys[key] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", key, pos))
+		}
+	}
+	for key := range keys {
+		if !s.uses(key) {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unused)
+
+	report("missing", missing)
+	report("unused", unused)
+	if len(missing) > 0 || len(unused) > 0 {
+		os.Exit(1)
+	}
+	fmt.Printf("%d keys, all referenced and present.\n", len(keys))
+}
+
+// loadKeys returns the keys of <lang>.yml and its <lang>.<part>.yml files.
+func loadKeys(dir, lang string) (map[string]bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, lang+".*yml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s locale files in %s", lang, dir)
+	}
+
+	keys := make(map[string]bool)
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var raw map[string]yaml.Node
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for key := range raw {
+			keys[key] = true
+		}
+	}
+	return keys, nil
+}
+
+func scanSources(root string) (*scan, error) {
+	s := &scan{literals: make(map[string]bool), asked: make(map[string]string)}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BasicLit:
+				if v, ok := stringLit(n); ok {
+					s.literals[v] = true
+				}
+			case *ast.BinaryExpr:
+				if n.Op != token.ADD {
+					break
+				}
+				if lit, ok := n.X.(*ast.BasicLit); ok {
+					if v, ok := stringLit(lit); ok && v != "" {
+						s.prefixes = append(s.prefixes, v)
+					}
+				}
+			case *ast.CallExpr:
+				if !keyFuncs[funcName(n.Fun)] || len(n.Args) < 2 {
+					break
+				}
+				if lit, ok := n.Args[1].(*ast.BasicLit); ok {
+					if v, ok := stringLit(lit); ok {
+						if _, seen := s.asked[v]; !seen {
+							s.asked[v] = fset.Position(lit.Pos()).String()
+						}
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	return s, err
+}
+
+func (s *scan) uses(key string) bool {
+	if s.literals[key] {
+		return true
+	}
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func funcName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+func stringLit(lit *ast.BasicLit) (string, bool) {
+	if lit.Kind != token.STRING {
+		return "", false
+	}
+	v, err := strconv.Unquote(lit.Value)
+	return v, err == nil
+}
+
+func report(kind string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Printf("%d %s keys:\n", len(keys), kind)
+	for _, k := range keys {
+		fmt.Println("  " + k)
+	}
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "localekeys: "+format+"\n", args...)
+	os.Exit(1)
+}
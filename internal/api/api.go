@@ -0,0 +1,168 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"main/internal/config"
+	"main/internal/database"
+	"main/internal/server"
+	"main/internal/utils"
+)
+
+type ctxKey struct{}
+
+var (
+	logger = config.GetLogger("api")
+
+	errNoVoiceChat     = errors.New("no active voice chat")
+	errAssistantBanned = errors.New("assistant is banned in this chat")
+
+	// authFailures throttles clients that keep sending unknown keys, so
+	// guessing keys or flooding the database with lookups is slow.
+	authFailures     = utils.NewRateLimiter(10 * time.Minute)
+	authFailureLimit = utils.Limit{Burst: 10, Per: time.Minute}
+)
+
+// Init registers the control API routes on the shared HTTP server.
+func Init() {
+	route("GET /api/rooms", listRooms)
+	route("GET /api/rooms/{chatID}", getRoom)
+	route("POST /api/rooms/{chatID}/pause", pauseRoom)
+	route("POST /api/rooms/{chatID}/resume", resumeRoom)
+	route("POST /api/rooms/{chatID}/skip", skipRoom)
+	route("POST /api/rooms/{chatID}/seek", seekRoom)
+	route("POST /api/rooms/{chatID}/queue", enqueue)
+	route("DELETE /api/rooms/{chatID}/queue/{index}", removeFromQueue)
+	route("POST /api/rooms/{chatID}/queue/move", moveInQueue)
+	route("GET /api/ws", feedHandler)
+
+	go watchRooms()
+	logger.Info("Control API enabled at /api")
+}
+
+// NewKey creates an API key and returns it. Only its hash is stored, so
+// the key cannot be shown again later.
+func NewKey(name string, createdBy int64) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	key := "ym_" + hex.EncodeToString(buf)
+	err := database.AddAPIKey(&database.APIKey{
+		Hash:      hashKey(key),
+		Name:      name,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func route(pattern string, handler http.HandlerFunc) {
+	server.HandleFunc(pattern, authorized(handler))
+}
+
+// authorized accepts the key from the X-API-Key header, a bearer token or,
+// for WebSocket clients that cannot set headers, the key query parameter.
+func authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			key, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if key == "" {
+			key = r.URL.Query().Get("key")
+		}
+		if key == "" {
+			writeError(w, http.StatusUnauthorized, "missing API key")
+			return
+		}
+
+		failKey := utils.RateKey{Key: "api_auth:" + remoteHost(r), Limit: authFailureLimit}
+		if wait := authFailures.Wait(failKey); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			writeError(w, http.StatusTooManyRequests, "too many invalid API keys")
+			return
+		}
+
+		k, err := database.GetAPIKey(hashKey(key))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to verify API key")
+			return
+		}
+		if k == nil {
+			authFailures.Allow(failKey)
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, k)))
+	}
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func keyFromContext(ctx context.Context) *database.APIKey {
+	k, _ := ctx.Value(ctxKey{}).(*database.APIKey)
+	return k
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.DebugF("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(v)
+}
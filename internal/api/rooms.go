@@ -0,0 +1,386 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package api
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+
+	"main/internal/config"
+	"main/internal/core"
+	state "main/internal/core/models"
+	"main/internal/database"
+	"main/internal/platforms"
+)
+
+type trackView struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Duration  int    `json:"duration"`
+	URL       string `json:"url"`
+	Artwork   string `json:"artwork,omitempty"`
+	Requester string `json:"requester,omitempty"`
+	Video     bool   `json:"video"`
+	Source    string `json:"source"`
+}
+
+type roomView struct {
+	ChatID   int64       `json:"chat_id"`
+	Active   bool        `json:"active"`
+	Track    *trackView  `json:"track"`
+	Position int         `json:"position"`
+	Queue    []trackView `json:"queue"`
+	Loop     int         `json:"loop"`
+	Speed    float64     `json:"speed"`
+	Shuffle  bool        `json:"shuffle"`
+	Paused   bool        `json:"paused"`
+	Muted    bool        `json:"muted"`
+}
+
+func newTrackView(t *state.Track) trackView {
+	return trackView{
+		ID:        t.ID,
+		Title:     t.Title,
+		Duration:  t.Duration,
+		URL:       t.URL,
+		Artwork:   t.Artwork,
+		Requester: t.Requester,
+		Video:     t.Video,
+		Source:    string(t.Source),
+	}
+}
+
+func newRoomView(r *core.RoomState) roomView {
+	v := roomView{
+		ChatID:   r.ChatID(),
+		Active:   r.IsActiveChat(),
+		Position: r.Position(),
+		Queue:    []trackView{},
+		Loop:     r.Loop(),
+		Speed:    r.Speed(),
+		Shuffle:  r.Shuffle(),
+		Paused:   r.IsPaused(),
+		Muted:    r.IsMuted(),
+	}
+	if t := r.Track(); t != nil {
+		tv := newTrackView(t)
+		v.Track = &tv
+	}
+	for _, t := range r.Queue() {
+		v.Queue = append(v.Queue, newTrackView(t))
+	}
+	return v
+}
+
+// roomFromRequest resolves the {chatID} path value to an existing room,
+// writing the error response itself when there is none.
+func roomFromRequest(w http.ResponseWriter, req *http.Request) (*core.RoomState, bool) {
+	chatID, err := strconv.ParseInt(req.PathValue("chatID"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid chat id")
+		return nil, false
+	}
+
+	r, ok := core.GetRoom(chatID, nil)
+	if !ok {
+		writeError(w, http.StatusNotFound, "room not found")
+		return nil, false
+	}
+	return r, true
+}
+
+func activeRoomFromRequest(w http.ResponseWriter, req *http.Request) (*core.RoomState, bool) {
+	r, ok := roomFromRequest(w, req)
+	if !ok {
+		return nil, false
+	}
+	if !r.IsActiveChat() {
+		writeError(w, http.StatusConflict, "nothing is playing")
+		return nil, false
+	}
+	return r, true
+}
+
+func listRooms(w http.ResponseWriter, req *http.Request) {
+	rooms := []roomView{}
+	for _, chatID := range core.GetAllRoomIDs() {
+		if r, ok := core.GetRoom(chatID, nil); ok {
+			rooms = append(rooms, newRoomView(r))
+		}
+	}
+	writeJSON(w, http.StatusOK, rooms)
+}
+
+func getRoom(w http.ResponseWriter, req *http.Request) {
+	r, ok := roomFromRequest(w, req)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+func pauseRoom(w http.ResponseWriter, req *http.Request) {
+	r, ok := activeRoomFromRequest(w, req)
+	if !ok {
+		return
+	}
+	if _, err := r.Pause(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+func resumeRoom(w http.ResponseWriter, req *http.Request) {
+	r, ok := activeRoomFromRequest(w, req)
+	if !ok {
+		return
+	}
+	if _, err := r.Resume(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+// skipRoom follows the /skip command: stop when there is nothing left,
+// otherwise download and play the next track.
+func skipRoom(w http.ResponseWriter, req *http.Request) {
+	r, ok := activeRoomFromRequest(w, req)
+	if !ok {
+		return
+	}
+
+	if len(r.Queue()) == 0 && r.Loop() == 0 {
+		r.Destroy()
+		writeJSON(w, http.StatusOK, map[string]bool{"stopped": true})
+		return
+	}
+
+	t := r.NextTrack()
+	path, err := platforms.Download(context.Background(), t, nil)
+	if err != nil {
+		r.Destroy()
+		writeError(w, http.StatusBadGateway, "download failed: "+err.Error())
+		return
+	}
+
+	if err := r.Play(t, path); err != nil {
+		r.Destroy()
+		writeError(w, http.StatusInternalServerError, "playback failed: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+func seekRoom(w http.ResponseWriter, req *http.Request) {
+	r, ok := activeRoomFromRequest(w, req)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Position *int `json:"position"`
+	}
+	if err := readJSON(w, req, &body); err != nil || body.Position == nil {
+		writeError(w, http.StatusBadRequest, "position (seconds) is required")
+		return
+	}
+
+	// RoomState.Seek is relative to the current position.
+	if err := r.Seek(*body.Position - r.Position()); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+// enqueue resolves a query like /play does and adds the tracks to the room,
+// starting playback when the room is idle.
+func enqueue(w http.ResponseWriter, req *http.Request) {
+	r, ok := roomFromRequest(w, req)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Query string `json:"query"`
+		Video bool   `json:"video"`
+	}
+	if err := readJSON(w, req, &body); err != nil || body.Query == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	// Same per-chat limits as /play, see /settings.
+	prefs, _ := database.GetChatPrefs(r.ChatID())
+	if body.Video && prefs.VideoDisabled {
+		writeError(w, http.StatusForbidden, "video is disabled in this chat")
+		return
+	}
+
+	slots := prefs.MaxQueue(config.QueueLimit) - len(r.Queue())
+	if slots <= 0 {
+		writeError(w, http.StatusConflict, "queue limit reached")
+		return
+	}
+
+	found, err := platforms.GetTracksByQuery(body.Query, body.Video)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	requester := "API"
+	if k := keyFromContext(req.Context()); k != nil {
+		requester = html.EscapeString(k.Name)
+	}
+
+	// The chat's /filter rules apply to the API as well.
+	allowed, blocked := database.FilterTracks(r.ChatID(), prefs, found)
+	if len(allowed) == 0 && len(blocked) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf(
+			"blocked by the chat's %s filter %q", blocked[0].Rule, blocked[0].Value,
+		))
+		return
+	}
+
+	var tracks []*state.Track
+	for _, t := range allowed {
+		if t.Duration > prefs.MaxDuration(config.DurationLimit) {
+			continue
+		}
+		t.Requester = requester
+		tracks = append(tracks, t)
+	}
+	if len(tracks) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "all tracks exceed the duration limit")
+		return
+	}
+	if len(tracks) > slots {
+		tracks = tracks[:slots]
+	}
+
+	if !r.IsActiveChat() {
+		if err := prepareVoiceChat(r.ChatID()); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		path, err := platforms.Download(context.Background(), tracks[0], nil)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "download failed: "+err.Error())
+			return
+		}
+		if err := r.Play(tracks[0], path); err != nil {
+			writeError(w, http.StatusInternalServerError, "playback failed: "+err.Error())
+			return
+		}
+		tracks = tracks[1:]
+	}
+
+	for _, t := range tracks {
+		if err := r.Play(t, ""); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+// prepareVoiceChat makes sure the voice chat is running and the assistant
+// is in the chat, joining it when needed.
+func prepareVoiceChat(chatID int64) error {
+	cs, err := core.GetChatState(chatID)
+	if err != nil {
+		return err
+	}
+
+	activeVC, err := cs.IsActiveVC()
+	if err != nil {
+		return err
+	}
+	if !activeVC {
+		return errNoVoiceChat
+	}
+
+	banned, err := cs.IsAssistantBanned()
+	if err != nil {
+		return err
+	}
+	if banned {
+		return errAssistantBanned
+	}
+
+	present, err := cs.IsAssistantPresent()
+	if err != nil {
+		return err
+	}
+	if !present {
+		return cs.TryJoin()
+	}
+	return nil
+}
+
+func removeFromQueue(w http.ResponseWriter, req *http.Request) {
+	r, ok := roomFromRequest(w, req)
+	if !ok {
+		return
+	}
+
+	// Queue positions are 1-based, as shown by /queue.
+	index, err := strconv.Atoi(req.PathValue("index"))
+	if err != nil || index < 1 || index > len(r.Queue()) {
+		writeError(w, http.StatusBadRequest, "invalid queue position")
+		return
+	}
+
+	r.RemoveFromQueue(index - 1)
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
+
+func moveInQueue(w http.ResponseWriter, req *http.Request) {
+	r, ok := roomFromRequest(w, req)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		From int `json:"from"`
+		To   int `json:"to"`
+	}
+	if err := readJSON(w, req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "from and to are required")
+		return
+	}
+
+	n := len(r.Queue())
+	if body.From < 1 || body.From > n || body.To < 1 || body.To > n {
+		writeError(w, http.StatusBadRequest, "invalid queue position")
+		return
+	}
+
+	r.MoveInQueue(body.From-1, body.To-1)
+	writeJSON(w, http.StatusOK, newRoomView(r))
+}
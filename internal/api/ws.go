@@ -0,0 +1,213 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"main/internal/core"
+)
+
+const (
+	watchInterval = time.Second
+	feedQueue     = 32
+	// Seeks show up as position jumps larger than this, plain playback
+	// progress is not pushed.
+	positionJump = 3
+)
+
+type event struct {
+	Type   string    `json:"type"`
+	ChatID int64     `json:"chat_id"`
+	Room   *roomView `json:"room,omitempty"`
+}
+
+type feed struct {
+	chatID int64 // 0 subscribes to every room
+	ch     chan []byte
+}
+
+var (
+	feeds   = make(map[*feed]struct{})
+	feedsMu sync.Mutex
+
+	lastViews = make(map[int64]roomView)
+)
+
+// feedHandler streams room state changes over a WebSocket. Clients may
+// pass chat_id to follow a single room; they get the current state of the
+// followed rooms right after connecting.
+func feedHandler(w http.ResponseWriter, req *http.Request) {
+	var chatID int64
+	if v := req.URL.Query().Get("chat_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid chat id")
+			return
+		}
+		chatID = id
+	}
+
+	// The key was already checked, so no Origin check is needed.
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		serveFeed(ws, chatID)
+	}}.ServeHTTP(w, req)
+}
+
+func serveFeed(ws *websocket.Conn, chatID int64) {
+	defer ws.Close()
+
+	f := &feed{chatID: chatID, ch: make(chan []byte, feedQueue)}
+	for _, id := range core.GetAllRoomIDs() {
+		if chatID != 0 && id != chatID {
+			continue
+		}
+		if r, ok := core.GetRoom(id, nil); ok {
+			v := newRoomView(r)
+			if data, err := json.Marshal(event{Type: "room", ChatID: id, Room: &v}); err == nil {
+				f.ch <- data
+			}
+		}
+		if len(f.ch) == feedQueue {
+			break
+		}
+	}
+
+	feedsMu.Lock()
+	feeds[f] = struct{}{}
+	feedsMu.Unlock()
+
+	// Incoming messages are ignored, reading only detects the disconnect.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var msg string
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+	}()
+
+	defer func() {
+		feedsMu.Lock()
+		delete(feeds, f)
+		feedsMu.Unlock()
+	}()
+
+	for {
+		select {
+		case data, ok := <-f.ch:
+			if !ok {
+				return
+			}
+			if err := websocket.Message.Send(ws, string(data)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func publish(e event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	feedsMu.Lock()
+	defer feedsMu.Unlock()
+	for f := range feeds {
+		if f.chatID != 0 && f.chatID != e.ChatID {
+			continue
+		}
+		select {
+		case f.ch <- data:
+		default:
+			// Slow client, drop it; it can reconnect for a fresh state.
+			delete(feeds, f)
+			close(f.ch)
+		}
+	}
+}
+
+func hasFeeds() bool {
+	feedsMu.Lock()
+	defer feedsMu.Unlock()
+	return len(feeds) > 0
+}
+
+// watchRooms polls the rooms and publishes whatever changed since the last
+// poll. Rooms have no change hooks, and polling keeps core untouched.
+func watchRooms() {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !hasFeeds() {
+			clear(lastViews)
+			continue
+		}
+
+		seen := make(map[int64]struct{})
+		for _, chatID := range core.GetAllRoomIDs() {
+			r, ok := core.GetRoom(chatID, nil)
+			if !ok {
+				continue
+			}
+			seen[chatID] = struct{}{}
+
+			v := newRoomView(r)
+			if prev, ok := lastViews[chatID]; ok && !changed(prev, v) {
+				lastViews[chatID] = v
+				continue
+			}
+			lastViews[chatID] = v
+			publish(event{Type: "room", ChatID: chatID, Room: &v})
+		}
+
+		for chatID := range lastViews {
+			if _, ok := seen[chatID]; !ok {
+				delete(lastViews, chatID)
+				publish(event{Type: "room_closed", ChatID: chatID})
+			}
+		}
+	}
+}
+
+func changed(prev, cur roomView) bool {
+	expected := prev.Position
+	if prev.Active && !prev.Paused {
+		expected += int(watchInterval.Seconds() * prev.Speed)
+	}
+	if d := cur.Position - expected; d > positionJump || d < -positionJump {
+		return true
+	}
+
+	prev.Position, cur.Position = 0, 0
+	a, _ := json.Marshal(prev)
+	b, _ := json.Marshal(cur)
+	return string(a) != string(b)
+}
@@ -27,14 +27,13 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	_ "github.com/joho/godotenv/autoload"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
 var (
-	logger = gologging.GetLogger("config")
+	logger = GetLogger("config")
 
 	// To learn more about what each variable does, see README.md
 	// Required Vars
@@ -47,6 +46,10 @@ var (
 		"SESSION_TYPE",
 		"pyrogram",
 	) // pyrogram, telethon, gogram
+	// Key for the assistant sessions added with /addassistant; changing it
+	// makes the stored sessions unreadable. /addassistant refuses to store
+	// sessions without it.
+	SessionSecret = getString("SESSION_SECRET")
 	// Optional Vars
 	OwnerID  = getInt64("OWNER_ID")
 	LoggerID = getInt64("LOGGER_ID")
@@ -77,6 +80,25 @@ var (
 	SetCmds        = getBool("SET_CMDS", false)
 	MaxAuthUsers   = int(getInt64("MAX_AUTH_USERS", 25))
 
+	// /record limits, a recording stops on whichever is hit first
+	RecordMaxDuration = int(getInt64("RECORD_MAX_DURATION", 3600)) // in seconds
+	RecordMaxSizeMB   = getInt64("RECORD_MAX_SIZE_MB", 2000)
+
+	// How long a room paused for an empty voice chat waits for listeners
+	// before it leaves; chats can turn this off in /settings.
+	IdleLeaveTimeout = int(getInt64("IDLE_LEAVE_TIMEOUT", 300)) // in seconds
+
+	// How long plays stay in the history behind /top, 0 keeps them forever
+	PlayHistoryDays = int(getInt64("PLAY_HISTORY_DAYS", 90))
+
+	// Built-in HTTP server, disabled when HTTP_PORT is 0
+	HTTPPort       = int(getInt64("HTTP_PORT"))
+	PublicURL      = strings.TrimRight(getString("PUBLIC_URL"), "/")
+	RadioEnabled   = getBool("RADIO_ENABLED", false)
+	APIEnabled     = getBool("API_ENABLED", false)
+	MetricsEnabled = getBool("METRICS_ENABLED", false)
+	HealthEnabled  = getBool("HEALTH_ENABLED", true)
+
 	StartImage = getString(
 		"START_IMG_URL",
 		"https://raw.githubusercontent.com/Vivekkumar-IN/assets/master/images.png",
@@ -86,9 +108,19 @@ var (
 		"https://telegra.ph/file/91533956c91d0fd7c9f20.jpg",
 	)
 
-	LogFileName = "logs.txt"
-	LogWriter   io.Writer
-	logFile     *os.File
+	// Storage backend: mongo (MONGO_DB_URI) or bolt, an embedded file at DB_PATH
+	DBBackend = strings.ToLower(getString("DB_BACKEND", "mongo"))
+	DBPath    = getString("DB_PATH", "data/yukki.db")
+
+	// Logging, see logging.go
+	LogFileName   = "logs.txt"
+	LogFormat     = strings.ToLower(getString("LOG_FORMAT", "text"))
+	LogLevel      = getString("LOG_LEVEL", "debug")
+	LogLevels     = getStringSlice("LOG_LEVELS", []string{"ntgcalls=error", "webrtc=fatal"})
+	LogMaxSizeMB  = getInt64("LOG_MAX_SIZE_MB", 10)
+	LogMaxAgeDays = getInt64("LOG_MAX_AGE_DAYS", 7)
+	LogMaxBackups = int(getInt64("LOG_MAX_BACKUPS", 5))
+	LogWriter     io.Writer
 )
 
 func init() {
@@ -99,21 +131,6 @@ func init() {
 	validateSpotify()
 }
 
-func initLogging() {
-	os.Remove(LogFileName)
-	file, err := os.OpenFile(
-		LogFileName,
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
-		0o644,
-	)
-	if err != nil {
-		panic(err)
-	}
-
-	logFile = file
-	LogWriter = io.MultiWriter(file, os.Stderr)
-}
-
 func validateRequired() {
 	if ApiID == 0 {
 		logger.Fatal("API_ID is required but missing!")
@@ -123,8 +140,17 @@ func validateRequired() {
 		logger.Fatal("API_HASH is required but missing!")
 	}
 
-	if MongoURI == "" {
-		logger.Fatal("MONGO_DB_URI is required but missing!")
+	switch DBBackend {
+	case "mongo":
+		if MongoURI == "" {
+			logger.Fatal("MONGO_DB_URI is required but missing!")
+		}
+	case "bolt":
+		if DBPath == "" {
+			logger.Fatal("DB_PATH is required when DB_BACKEND=bolt!")
+		}
+	default:
+		logger.FatalF("DB_BACKEND must be mongo or bolt, got %q", DBBackend)
 	}
 }
 
@@ -246,9 +272,3 @@ func variants(base string) []string {
 			String(strings.ReplaceAll(base, "_", " ")),
 	}
 }
-
-func CloseLogging() {
-	if logFile != nil {
-		logFile.Close()
-	}
-}
@@ -0,0 +1,170 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Laky-64/gologging"
+)
+
+// DefaultLogger is the name of the unnamed logger packages without their
+// own logger use.
+const DefaultLogger = "default"
+
+// LoggerNames lists the named loggers whose output and level are managed
+// here. Add new GetLogger names to this list.
+var LoggerNames = []string{
+	DefaultLogger,
+	"modules",
+	"Database",
+	"ntgcalls",
+	"webrtc",
+	"config",
+	"server",
+	"radio",
+	"api",
+}
+
+var (
+	logFile *rotatingFile
+
+	logLevelsMu sync.Mutex
+)
+
+var levelNames = map[string]gologging.Level{
+	"debug": gologging.DebugLevel,
+	"info":  gologging.InfoLevel,
+	"warn":  gologging.WarnLevel,
+	"error": gologging.ErrorLevel,
+	"fatal": gologging.FatalLevel,
+}
+
+func initLogging() {
+	file, err := openRotatingFile(
+		LogFileName,
+		LogMaxSizeMB*1024*1024,
+		LogMaxAgeDays,
+		LogMaxBackups,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	logFile = file
+	LogWriter = io.MultiWriter(file, os.Stderr)
+}
+
+// SetupLoggers points every known logger at LogWriter and applies LOG_LEVEL
+// and the per-logger overrides from LOG_LEVELS (name=level pairs).
+func SetupLoggers() {
+	def, err := ParseLogLevel(LogLevel)
+	if err != nil {
+		logger.WarnF("Invalid LOG_LEVEL %q, using debug", LogLevel)
+		def = gologging.DebugLevel
+	}
+
+	overrides := make(map[string]gologging.Level)
+	for _, pair := range LogLevels {
+		name, lvl, ok := strings.Cut(pair, "=")
+		if !ok {
+			logger.WarnF("Invalid LOG_LEVELS entry %q, expected name=level", pair)
+			continue
+		}
+		level, err := ParseLogLevel(lvl)
+		if err != nil {
+			logger.WarnF("Invalid level in LOG_LEVELS entry %q", pair)
+			continue
+		}
+		overrides[name] = level
+	}
+
+	for _, name := range LoggerNames {
+		level := def
+		if l, ok := overrides[name]; ok {
+			level = l
+		}
+		if name == DefaultLogger {
+			// Libraries logging through the gologging package functions.
+			gologging.SetOutput(LogWriter)
+			gologging.SetLevel(level)
+		}
+		l := gologger(name)
+		l.SetOutput(LogWriter)
+		l.SetLevel(level)
+	}
+}
+
+// SetLogLevel changes the level of a known logger at runtime.
+func SetLogLevel(name, level string) error {
+	lvl, err := ParseLogLevel(level)
+	if err != nil {
+		return err
+	}
+
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+
+	i := slices.IndexFunc(LoggerNames, func(n string) bool {
+		return strings.EqualFold(n, name)
+	})
+	if i < 0 {
+		return fmt.Errorf("unknown logger %q", name)
+	}
+
+	if LoggerNames[i] == DefaultLogger {
+		gologging.SetLevel(lvl)
+	}
+	gologger(LoggerNames[i]).SetLevel(lvl)
+	return nil
+}
+
+// LogLevelOf returns the current level name of a known logger.
+func LogLevelOf(name string) string {
+	return levelName(gologger(name).GetLevel())
+}
+
+func ParseLogLevel(s string) (gologging.Level, error) {
+	if lvl, ok := levelNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return lvl, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+func levelName(lvl gologging.Level) string {
+	for name, l := range levelNames {
+		if l == lvl {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func CloseLogging() {
+	if logFile != nil {
+		logFile.Close()
+	}
+}
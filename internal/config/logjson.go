@@ -0,0 +1,186 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Laky-64/gologging"
+)
+
+// Logger is what the packages log through. In the text format it is the
+// gologging logger itself; in the JSON format every record is encoded
+// directly, so messages spanning several lines stay one object.
+type Logger interface {
+	Debug(message ...any)
+	DebugF(message string, args ...any)
+	Info(message ...any)
+	InfoF(message string, args ...any)
+	Warn(message ...any)
+	WarnF(message string, args ...any)
+	Error(message ...any)
+	ErrorF(message string, args ...any)
+	Fatal(message ...any)
+	FatalF(message string, args ...any)
+}
+
+var (
+	// Well-known fields are written as key=value inside log messages and
+	// lifted into their own JSON keys.
+	logFieldRgx = regexp.MustCompile(
+		`\b(chat_id|assistant|platform|track_id)=("[^"]*"|\S+)`,
+	)
+
+	logLevelNames = map[gologging.Level]string{
+		gologging.DebugLevel: "debug",
+		gologging.InfoLevel:  "info",
+		gologging.WarnLevel:  "warn",
+		gologging.ErrorLevel: "error",
+		gologging.FatalLevel: "fatal",
+	}
+
+	jsonMu sync.Mutex
+)
+
+// GetLogger returns the logger registered under name; DefaultLogger is
+// the unnamed one. Its level is managed by SetupLoggers and SetLogLevel.
+func GetLogger(name string) Logger {
+	if LogFormat == "json" {
+		return &jsonLogger{name: name, gl: gologger(name)}
+	}
+	return gologger(name)
+}
+
+// gologger returns the gologging logger holding the output and level of
+// name. The default logger is registered without a name so its text lines
+// carry no [name] prefix.
+func gologger(name string) *gologging.Logger {
+	if name == DefaultLogger {
+		return gologging.GetLogger("")
+	}
+	return gologging.GetLogger(name)
+}
+
+type jsonLogger struct {
+	name string
+	gl   *gologging.Logger
+}
+
+func (l *jsonLogger) Debug(message ...any) { l.log(gologging.DebugLevel, join(message)) }
+func (l *jsonLogger) Info(message ...any)  { l.log(gologging.InfoLevel, join(message)) }
+func (l *jsonLogger) Warn(message ...any)  { l.log(gologging.WarnLevel, join(message)) }
+func (l *jsonLogger) Error(message ...any) { l.log(gologging.ErrorLevel, join(message)) }
+func (l *jsonLogger) Fatal(message ...any) { l.log(gologging.FatalLevel, join(message)) }
+
+func (l *jsonLogger) DebugF(message string, args ...any) {
+	l.log(gologging.DebugLevel, fmt.Sprintf(message, args...))
+}
+
+func (l *jsonLogger) InfoF(message string, args ...any) {
+	l.log(gologging.InfoLevel, fmt.Sprintf(message, args...))
+}
+
+func (l *jsonLogger) WarnF(message string, args ...any) {
+	l.log(gologging.WarnLevel, fmt.Sprintf(message, args...))
+}
+
+func (l *jsonLogger) ErrorF(message string, args ...any) {
+	l.log(gologging.ErrorLevel, fmt.Sprintf(message, args...))
+}
+
+func (l *jsonLogger) FatalF(message string, args ...any) {
+	l.log(gologging.FatalLevel, fmt.Sprintf(message, args...))
+}
+
+func (l *jsonLogger) log(level gologging.Level, msg string) {
+	if l.gl.GetLevel() > level {
+		return
+	}
+	if msg != "" {
+		// Skip callerPackage, log and Info (or InfoF, ...).
+		l.write(level, msg, callerPackage(3))
+	}
+	if level == gologging.FatalLevel {
+		os.Exit(1)
+	}
+}
+
+func (l *jsonLogger) write(level gologging.Level, msg, pkg string) {
+	entry := map[string]any{
+		"time":    time.Now().Format(time.RFC3339),
+		"level":   logLevelNames[level],
+		"logger":  l.name,
+		"package": pkg,
+		"msg":     msg,
+	}
+	for _, f := range logFieldRgx.FindAllStringSubmatch(msg, -1) {
+		val := strings.Trim(f[2], `",.;:)`)
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			entry[f[1]] = n
+		} else {
+			entry[f[1]] = val
+		}
+	}
+	data, _ := json.Marshal(entry)
+
+	var out io.Writer = os.Stderr
+	if LogWriter != nil {
+		out = LogWriter
+	}
+	jsonMu.Lock()
+	defer jsonMu.Unlock()
+	_, _ = out.Write(append(data, '\n'))
+}
+
+// join formats the arguments like gologging does for Info and friends.
+func join(message []any) string {
+	parts := make([]string, 0, len(message))
+	for _, m := range message {
+		if s := fmt.Sprintf("%v", m); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// callerPackage returns the import path of the function skip frames up,
+// for example "main/internal/modules".
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
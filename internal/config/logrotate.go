@@ -0,0 +1,155 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingFile is the log file writer. It moves the file aside once it
+// grows past maxSize (and on startup, so each run starts fresh without
+// losing the previous one) and prunes backups by age and count.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(
+	path string,
+	maxSize, maxAgeDays int64,
+	maxBackups int,
+) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+		maxBackups: maxBackups,
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		if err := r.backup(); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	r.prune()
+	return r, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size+int64(len(p)) > r.maxSize {
+		// The file may have been truncated behind our back (/logs clear).
+		if info, err := r.file.Stat(); err == nil {
+			r.size = info.Size()
+		}
+		if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+			if err := r.rotate(); err != nil {
+				fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+			}
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(
+		r.path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0o644,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.file = file
+	r.size = 0
+	if info, err := file.Stat(); err == nil {
+		r.size = info.Size()
+	}
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	backupErr := r.backup()
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.prune()
+	return backupErr
+}
+
+// backup renames the current file to name-<timestamp>.ext.
+func (r *rotatingFile) backup() error {
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext)
+	name := base + "-" + time.Now().Format("20060102-150405.000") + ext
+	return os.Rename(r.path, name)
+}
+
+func (r *rotatingFile) prune() {
+	ext := filepath.Ext(r.path)
+	pattern := strings.TrimSuffix(r.path, ext) + "-*" + ext
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return
+	}
+
+	// Timestamps sort lexically, newest first.
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	for i, name := range backups {
+		expired := false
+		if r.maxAge > 0 {
+			if info, err := os.Stat(name); err == nil {
+				expired = time.Since(info.ModTime()) > r.maxAge
+			}
+		}
+		if expired || (r.maxBackups > 0 && i >= r.maxBackups) {
+			os.Remove(name)
+		}
+	}
+}
@@ -29,12 +29,13 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/Laky-64/gologging"
 	"resty.dev/v3"
 
 	"main/internal/config"
 )
 
+var logger = config.GetLogger(config.DefaultLogger)
+
 var (
 	cachedFiles []string
 	cacheOnce   sync.Once
@@ -44,16 +45,16 @@ var (
 var embeddedCookies embed.FS
 
 func init() {
-	gologging.Debug("🔹 Initializing cookies...")
+	logger.Debug("🔹 Initializing cookies...")
 
 	if err := copyEmbeddedCookies(); err != nil {
-		gologging.Fatal("Failed to copy embedded cookies:", err)
+		logger.Fatal("Failed to copy embedded cookies:", err)
 	}
 
 	urls := strings.Fields(config.CookiesLink)
 	for _, url := range urls {
 		if err := downloadCookieFile(url); err != nil {
-			gologging.WarnF(
+			logger.WarnF(
 				"Failed to download cookie file from %s: %v",
 				url,
 				err,
@@ -137,13 +138,13 @@ func GetRandomCookieFile() (string, error) {
 	})
 
 	if err != nil {
-		gologging.WarnF("Failed to load cookie cache: %v", err)
+		logger.WarnF("Failed to load cookie cache: %v", err)
 		cacheOnce = sync.Once{}
 		return "", err
 	}
 
 	if len(cachedFiles) == 0 {
-		gologging.Warn("No cookie files available")
+		logger.Warn("No cookie files available")
 		return "", nil
 	}
 
@@ -22,31 +22,61 @@ package core
 
 import (
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/ubot"
 )
 
 type Assistant struct {
-	Index  int
+	// index is the 0-based position in the manager's list; it shifts when
+	// an assistant before it is removed, so read it through Index.
+	index  atomic.Int32
 	Client *telegram.Client
 	User   *telegram.UserObj
 	Ntg    *ubot.Context
+
+	health assistantHealth
+	load   assistantLoad
 }
 
 type AssistantManager struct {
+	// listMu guards list. The index of its assistants changes when an
+	// assistant is removed at runtime and is atomic on its own.
+	listMu     sync.RWMutex
 	list       []*Assistant
 	cacheMu    sync.RWMutex
 	indexCache map[int64]int // chatID -> assistantIndex (1-based)
+
+	// moveMu serializes failovers so a chat is only moved once when
+	// several callers notice the same dead assistant.
+	moveMu sync.Mutex
+	// bannedIn remembers the assistants banned in a chat so a failover
+	// never moves the chat back to one of them.
+	bannedMu sync.Mutex
+	bannedIn map[int64]map[int]bool
+
+	// onAdd runs for every assistant added after startup.
+	onAdd []func(*Assistant)
+	// sessionSeq numbers the session files of added assistants so they
+	// never reuse the file of a running one.
+	sessionSeq int
+}
+
+// Index is the 0-based position of the assistant.
+func (a *Assistant) Index() int {
+	return int(a.index.Load())
 }
 
 func (m *AssistantManager) Count() int {
 	if m == nil {
 		return 0
 	}
+	m.listMu.RLock()
+	defer m.listMu.RUnlock()
 	return len(m.list)
 }
 
@@ -54,6 +84,8 @@ func (m *AssistantManager) Get(idx int) (*Assistant, error) {
 	if m == nil {
 		return nil, fmt.Errorf("assistant manager not initialized")
 	}
+	m.listMu.RLock()
+	defer m.listMu.RUnlock()
 	if idx < 1 || idx > len(m.list) {
 		return nil, fmt.Errorf("assistant index out of range: %d", idx)
 	}
@@ -68,11 +100,26 @@ func (m *AssistantManager) ForEach(fn func(*Assistant)) {
 	if m == nil {
 		return
 	}
-	for _, a := range m.list {
+	m.listMu.RLock()
+	list := slices.Clone(m.list)
+	m.listMu.RUnlock()
+
+	for _, a := range list {
 		fn(a)
 	}
 }
 
+// IsAssistant reports whether userID is one of the assistant accounts.
+func (m *AssistantManager) IsAssistant(userID int64) bool {
+	found := false
+	m.ForEach(func(a *Assistant) {
+		if a.User != nil && a.User.ID == userID {
+			found = true
+		}
+	})
+	return found
+}
+
 func (m *AssistantManager) WithAssistant(chatID int64, fn func(*Assistant)) {
 	if m == nil {
 		return
@@ -80,8 +127,8 @@ func (m *AssistantManager) WithAssistant(chatID int64, fn func(*Assistant)) {
 
 	ass, err := m.ForChat(chatID)
 	if err != nil {
-		gologging.ErrorF(
-			"Failed to get assistant for chat %d, Error: %v",
+		logger.ErrorF(
+			"Failed to get assistant chat_id=%d: %v",
 			chatID,
 			err,
 		)
@@ -91,32 +138,54 @@ func (m *AssistantManager) WithAssistant(chatID int64, fn func(*Assistant)) {
 	fn(ass)
 }
 
+// cachedIndex returns the assistant index of a chat without hitting the
+// database, for callers that must stay cheap.
+func (m *AssistantManager) cachedIndex(chatID int64) (int, bool) {
+	if m == nil {
+		return 0, false
+	}
+	m.cacheMu.RLock()
+	defer m.cacheMu.RUnlock()
+	idx, ok := m.indexCache[chatID]
+	return idx, ok
+}
+
 func (m *AssistantManager) ForChat(chatID int64) (*Assistant, error) {
-	if m == nil || len(m.list) == 0 {
+	count := m.Count()
+	if count == 0 {
 		return nil, fmt.Errorf("no assistants available")
 	}
 	if AssistantIndexFunc == nil {
 		return nil, fmt.Errorf("AssistantIndexFunc is not set")
 	}
 
-	m.cacheMu.RLock()
-	if idx, ok := m.indexCache[chatID]; ok {
-		m.cacheMu.RUnlock()
-		return m.Get(idx)
+	idx1, ok := m.cachedIndex(chatID)
+	if !ok {
+		var err error
+		idx1, err = AssistantIndexFunc(chatID, count)
+		if err != nil {
+			return nil, err
+		}
+
+		m.cacheMu.Lock()
+		if m.indexCache == nil {
+			m.indexCache = make(map[int64]int)
+		}
+		m.indexCache[chatID] = idx1
+		m.cacheMu.Unlock()
 	}
-	m.cacheMu.RUnlock()
 
-	idx1, err := AssistantIndexFunc(chatID, len(m.list))
+	ass, err := m.Get(idx1)
 	if err != nil {
 		return nil, err
 	}
 
-	m.cacheMu.Lock()
-	if m.indexCache == nil {
-		m.indexCache = make(map[int64]int)
+	// Chats of a dead assistant move on first use; if nothing healthy is
+	// left keep the old one so callers still get a usable error later.
+	if !ass.Healthy() {
+		if to, err := m.moveChat(chatID, ass, ass.healthReason()); err == nil {
+			return to, nil
+		}
 	}
-	m.indexCache[chatID] = idx1
-	m.cacheMu.Unlock()
-
-	return m.Get(idx1)
+	return ass, nil
 }
@@ -0,0 +1,497 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/locales"
+	"main/internal/metrics"
+)
+
+// AssistantReassignFunc stores a new assistant for a chat, picked among the
+// 1-based candidate indexes, and returns it.
+var AssistantReassignFunc func(chatID int64, candidates []int) (int, error) // AssistantReassignFunc = database.ReassignAssistant
+
+var ErrNoHealthyAssistant = errors.New("no healthy assistant available")
+
+var assistantFailovers = metrics.NewCounter(
+	"yukki_assistant_failovers_total",
+	"Chats moved to another assistant because theirs was down or banned.",
+)
+
+// disconnectedChecks is how many health checks in a row must find a client
+// disconnected before it is considered down; gogram reconnects by itself
+// after short network drops.
+const disconnectedChecks = 2
+
+// sessionErrors are RPC errors after which a session never recovers.
+var sessionErrors = []string{
+	"AUTH_KEY_UNREGISTERED",
+	"AUTH_KEY_DUPLICATED",
+	"SESSION_REVOKED",
+	"SESSION_EXPIRED",
+	"USER_DEACTIVATED",
+	"USER_DEACTIVATED_BAN",
+}
+
+type assistantHealth struct {
+	mu     sync.RWMutex
+	down   bool
+	reason string
+	since  time.Time
+	misses int
+	// retired is set while the assistant is being removed; it keeps the
+	// health checks from bringing it back up.
+	retired bool
+}
+
+// Healthy reports whether the assistant session is usable.
+func (a *Assistant) Healthy() bool {
+	a.health.mu.RLock()
+	defer a.health.mu.RUnlock()
+	return !a.health.down
+}
+
+// HealthStatus returns why the assistant is down and since when.
+func (a *Assistant) HealthStatus() (reason string, since time.Time) {
+	a.health.mu.RLock()
+	defer a.health.mu.RUnlock()
+	return a.health.reason, a.health.since
+}
+
+func (a *Assistant) healthReason() string {
+	reason, _ := a.HealthStatus()
+	return reason
+}
+
+// setDown marks the assistant down and reports whether it was up before.
+func (a *Assistant) setDown(reason string) bool {
+	a.health.mu.Lock()
+	defer a.health.mu.Unlock()
+	if a.health.down {
+		return false
+	}
+	a.health.down = true
+	a.health.reason = reason
+	a.health.since = time.Now()
+	return true
+}
+
+// setUp marks the assistant healthy and reports whether it was down before.
+func (a *Assistant) setUp() bool {
+	a.health.mu.Lock()
+	defer a.health.mu.Unlock()
+	a.health.misses = 0
+	if !a.health.down || a.health.retired {
+		return false
+	}
+	a.health.down = false
+	a.health.reason = ""
+	a.health.since = time.Now()
+	return true
+}
+
+// setRetired marks the assistant down for removal, or clears the mark when
+// the removal failed.
+func (a *Assistant) setRetired(retired bool) {
+	a.health.mu.Lock()
+	a.health.retired = retired
+	if retired {
+		a.health.down = true
+		a.health.reason = "removed"
+		a.health.since = time.Now()
+	}
+	a.health.mu.Unlock()
+
+	if !retired {
+		a.setUp()
+	}
+}
+
+func isSessionError(err error) bool {
+	for _, e := range sessionErrors {
+		if telegram.MatchError(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// MonitorHealth checks every assistant session each interval, samples its
+// CPU usage and moves the active chats of an assistant that went down to
+// the healthy ones.
+func (m *AssistantManager) MonitorHealth(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.ForEach(m.checkHealth)
+	}
+}
+
+func (m *AssistantManager) checkHealth(a *Assistant) {
+	reason := ""
+	if !a.Client.IsConnected() {
+		a.health.mu.Lock()
+		a.health.misses++
+		misses := a.health.misses
+		a.health.mu.Unlock()
+		if misses < disconnectedChecks {
+			return
+		}
+		reason = "disconnected"
+	} else if _, err := a.Client.GetMe(); err != nil && isSessionError(err) {
+		reason = err.Error()
+	}
+
+	if reason == "" {
+		a.refreshCPU()
+		if a.setUp() {
+			logger.InfoF("assistant=%d is healthy again", a.Index()+1)
+			notifyOwner(func(target int64) string {
+				return F(target, "assistant_up_notice", locales.Arg{
+					"index": a.Index() + 1,
+					"name":  a.User.FirstName,
+				})
+			})
+		}
+		return
+	}
+
+	if !a.setDown(reason) {
+		return
+	}
+	logger.WarnF("assistant=%d is down: %s", a.Index()+1, reason)
+
+	moved := m.evacuate(a, reason)
+	notifyOwner(func(target int64) string {
+		return F(target, "assistant_down_notice", locales.Arg{
+			"index":  a.Index() + 1,
+			"name":   a.User.FirstName,
+			"reason": reason,
+			"count":  moved,
+		})
+	})
+}
+
+// evacuate moves the chats with an active room off a, and returns how many
+// moved. Idle chats move lazily the next time ForChat is called for them.
+func (m *AssistantManager) evacuate(a *Assistant, reason string) int {
+	moved := 0
+	for _, chatID := range m.chatsOf(a.Index() + 1) {
+		r, ok := GetRoom(chatID, nil)
+		if !ok || !r.IsActiveChat() {
+			continue
+		}
+		if _, err := m.moveChat(chatID, a, reason); err != nil {
+			logger.WarnF("Failed to move chat_id=%d off assistant=%d: %v",
+				chatID, a.Index()+1, err)
+			continue
+		}
+		moved++
+	}
+	return moved
+}
+
+// chatsOf returns the cached chats assigned to the 1-based index idx.
+func (m *AssistantManager) chatsOf(idx int) []int64 {
+	m.cacheMu.RLock()
+	defer m.cacheMu.RUnlock()
+
+	var ids []int64
+	for chatID, i := range m.indexCache {
+		if i == idx {
+			ids = append(ids, chatID)
+		}
+	}
+	return ids
+}
+
+// Reassign moves chatID off its current assistant, for example because it
+// was banned there. An active room follows to the new assistant.
+func (m *AssistantManager) Reassign(chatID int64, reason string) (*Assistant, error) {
+	// Prefer the cached index: ForChat could fail a dead assistant over on
+	// its own, and the ban below must be recorded for the assistant the
+	// caller actually saw.
+	var from *Assistant
+	var err error
+	if idx, ok := m.cachedIndex(chatID); ok {
+		from, err = m.Get(idx)
+	} else {
+		from, err = m.ForChat(chatID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.bannedMu.Lock()
+	if m.bannedIn == nil {
+		m.bannedIn = make(map[int64]map[int]bool)
+	}
+	if m.bannedIn[chatID] == nil {
+		m.bannedIn[chatID] = make(map[int]bool)
+	}
+	m.bannedIn[chatID][from.Index()+1] = true
+	m.bannedMu.Unlock()
+
+	return m.moveChat(chatID, from, reason)
+}
+
+// ClearBan forgets that the assistant with userID was banned in chatID, once
+// the ban is lifted.
+func (m *AssistantManager) ClearBan(chatID, userID int64) {
+	idx := 0
+	m.ForEach(func(a *Assistant) {
+		if a.User.ID == userID {
+			idx = a.Index() + 1
+		}
+	})
+	if idx == 0 {
+		return
+	}
+
+	m.bannedMu.Lock()
+	defer m.bannedMu.Unlock()
+	delete(m.bannedIn[chatID], idx)
+	if len(m.bannedIn[chatID]) == 0 {
+		delete(m.bannedIn, chatID)
+	}
+}
+
+// forgetBans drops every ban recorded for the 1-based index idx.
+func (m *AssistantManager) forgetBans(idx int) {
+	m.bannedMu.Lock()
+	defer m.bannedMu.Unlock()
+	for chatID, banned := range m.bannedIn {
+		delete(banned, idx)
+		if len(banned) == 0 {
+			delete(m.bannedIn, chatID)
+		}
+	}
+}
+
+// candidates returns the healthy assistants chatID may move to.
+func (m *AssistantManager) candidates(chatID int64, exclude int) []int {
+	m.bannedMu.Lock()
+	banned := m.bannedIn[chatID]
+	m.bannedMu.Unlock()
+
+	var out []int
+	m.ForEach(func(a *Assistant) {
+		idx := a.Index() + 1
+		if idx == exclude || banned[idx] || !a.Healthy() {
+			return
+		}
+		out = append(out, idx)
+	})
+	return out
+}
+
+func (m *AssistantManager) moveChat(
+	chatID int64,
+	from *Assistant,
+	reason string,
+) (*Assistant, error) {
+	to, moved, err := m.repoint(chatID, from)
+	if err != nil || !moved {
+		return to, err
+	}
+
+	assistantFailovers.Inc()
+	m.finishMove(chatID, from, to, reason)
+	return to, nil
+}
+
+// repoint stores a healthy assistant other than from for chatID, without
+// touching its room. It reports false if someone else already moved the
+// chat off from.
+func (m *AssistantManager) repoint(chatID int64, from *Assistant) (*Assistant, bool, error) {
+	// Only the decision is made under moveMu; moving the room and telling
+	// the owner are network calls and must not block ForChat elsewhere.
+	m.moveMu.Lock()
+	defer m.moveMu.Unlock()
+
+	// Someone else already moved it while we waited.
+	if idx, ok := m.cachedIndex(chatID); ok && idx != from.Index()+1 {
+		to, err := m.Get(idx)
+		return to, false, err
+	}
+
+	candidates := m.candidates(chatID, from.Index()+1)
+	if len(candidates) == 0 {
+		return nil, false, ErrNoHealthyAssistant
+	}
+	to, err := m.reassign(chatID, candidates)
+	if err != nil {
+		return nil, false, err
+	}
+	m.pointChat(chatID, to)
+	return to, true, nil
+}
+
+func (m *AssistantManager) moveChatTo(
+	chatID int64,
+	from, to *Assistant,
+	reason string,
+) (*Assistant, error) {
+	m.moveMu.Lock()
+	if _, err := m.reassign(chatID, []int{to.Index() + 1}); err != nil {
+		m.moveMu.Unlock()
+		return nil, err
+	}
+	m.pointChat(chatID, to)
+	m.moveMu.Unlock()
+
+	m.finishMove(chatID, from, to, reason)
+	return to, nil
+}
+
+// reassign stores the new assistant of chatID, picked among candidates.
+func (m *AssistantManager) reassign(chatID int64, candidates []int) (*Assistant, error) {
+	if AssistantReassignFunc == nil {
+		return nil, fmt.Errorf("AssistantReassignFunc is not set")
+	}
+	idx, err := AssistantReassignFunc(chatID, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return m.Get(idx)
+}
+
+// finishMove moves the room of chatID, already pointed at to, and tells the
+// owner. It does network I/O, so call it without moveMu.
+func (m *AssistantManager) finishMove(
+	chatID int64,
+	from, to *Assistant,
+	reason string,
+) {
+	logger.WarnF(
+		"Moved chat_id=%d from assistant %d to assistant=%d: %s",
+		chatID, from.Index()+1, to.Index()+1, reason,
+	)
+
+	roomErr := moveRoom(chatID, to)
+	notifyOwner(func(target int64) string {
+		text := F(target, "assistant_failover_notice", locales.Arg{
+			"chat_id": chatID,
+			"from":    from.Index() + 1,
+			"to":      to.Index() + 1,
+			"reason":  reason,
+		})
+		if roomErr != nil {
+			text += "\n" + F(target, "assistant_room_dropped", locales.Arg{
+				"error": roomErr.Error(),
+			})
+		}
+		return text
+	})
+}
+
+// pointChat updates the cached assignment and chat state of chatID. Call it
+// under moveMu.
+func (m *AssistantManager) pointChat(chatID int64, to *Assistant) {
+	m.cacheMu.Lock()
+	if m.indexCache == nil {
+		m.indexCache = make(map[int64]int)
+	}
+	m.indexCache[chatID] = to.Index() + 1
+	m.cacheMu.Unlock()
+
+	chMutex.Lock()
+	cs := ChatStates[chatID]
+	chMutex.Unlock()
+	if cs != nil {
+		cs.setAssistant(to)
+	}
+}
+
+// moveRoom hands an active room of chatID over to to, logging a failure.
+func moveRoom(chatID int64, to *Assistant) error {
+	err := migrateRoom(chatID, to)
+	if err != nil {
+		logger.WarnF("Failed to move room chat_id=%d: %v", chatID, err)
+	}
+	return err
+}
+
+// migrateRoom hands an active room over to ass. The new assistant joins the
+// chat first; if that fails the room is dropped.
+func migrateRoom(chatID int64, ass *Assistant) error {
+	r, ok := GetRoom(chatID, ass)
+	if !ok {
+		return nil
+	}
+	if !r.IsActiveChat() {
+		r.setPlayer(&NtgPlayer{Ntg: ass.Ntg, Assistant: ass})
+		return nil
+	}
+
+	err := ensureAssistantJoined(chatID)
+	if err == nil {
+		err = r.MoveTo(ass)
+	}
+	if err != nil {
+		r.Destroy()
+	}
+	return err
+}
+
+func ensureAssistantJoined(chatID int64) error {
+	cs, err := GetChatState(chatID)
+	if err != nil {
+		return err
+	}
+	present, err := cs.IsAssistantPresent(true)
+	if err != nil {
+		return err
+	}
+	if present {
+		return nil
+	}
+	if err := cs.TryJoin(); err != nil {
+		return err
+	}
+	time.Sleep(time.Second)
+	return nil
+}
+
+// notifyOwner sends a notice to the logger chat, or to the owner when no
+// logger chat is set. text builds the message in the target's language.
+func notifyOwner(text func(target int64) string) {
+	target := config.LoggerID
+	if target == 0 {
+		target = config.OwnerID
+	}
+	if target == 0 || Bot == nil {
+		return
+	}
+
+	if _, err := Bot.SendMessage(target, text(target)); err != nil {
+		logger.WarnF("Failed to send assistant notice: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Weights of the load score; lower scores get new chats first.
+const (
+	weightCall      = 1.0  // per live call
+	weightCPU       = 0.05 // per percent of CPU used by the ntgcalls client
+	weightFloodWait = 2.0  // per FloodWait in the last floodWindow
+	weightJoinFail  = 4.0  // times the share of failed joins
+
+	floodWindow = 10 * time.Minute
+	// joinHistory is how many recent join attempts the success rate is
+	// computed over.
+	joinHistory = 20
+)
+
+type assistantLoad struct {
+	mu         sync.Mutex
+	cpu        float64
+	floodWaits []time.Time
+	joins      []bool
+}
+
+// AssistantLoad is a snapshot of how busy an assistant is.
+type AssistantLoad struct {
+	Index      int // 1-based
+	Healthy    bool
+	Calls      int
+	CPU        float64
+	FloodWaits int
+	// JoinRate is the share of successful joins, 1 when nothing was tried.
+	JoinRate float64
+	Score    float64
+}
+
+// RecordFloodWait notes a FloodWait the assistant got from Telegram.
+func (a *Assistant) RecordFloodWait() {
+	if a == nil {
+		return
+	}
+	a.load.mu.Lock()
+	defer a.load.mu.Unlock()
+	a.load.floodWaits = append(pruneFloodWaits(a.load.floodWaits), time.Now())
+}
+
+// RecordJoin notes the outcome of the assistant joining a chat.
+func (a *Assistant) RecordJoin(ok bool) {
+	if a == nil {
+		return
+	}
+	a.load.mu.Lock()
+	defer a.load.mu.Unlock()
+	a.load.joins = append(a.load.joins, ok)
+	if len(a.load.joins) > joinHistory {
+		a.load.joins = a.load.joins[len(a.load.joins)-joinHistory:]
+	}
+}
+
+func (a *Assistant) refreshCPU() {
+	cpu, err := a.Ntg.CpuUsage()
+	if err != nil {
+		logger.DebugF("CpuUsage of assistant=%d failed: %v", a.Index()+1, err)
+		return
+	}
+	a.load.mu.Lock()
+	a.load.cpu = cpu
+	a.load.mu.Unlock()
+}
+
+// Load returns the current load of the assistant. CPU is the value of the
+// last health check.
+func (a *Assistant) Load() AssistantLoad {
+	l := AssistantLoad{
+		Index:    a.Index() + 1,
+		Healthy:  a.Healthy(),
+		Calls:    len(a.Ntg.Calls()),
+		JoinRate: 1,
+	}
+
+	a.load.mu.Lock()
+	a.load.floodWaits = pruneFloodWaits(a.load.floodWaits)
+	l.CPU = a.load.cpu
+	l.FloodWaits = len(a.load.floodWaits)
+	if n := len(a.load.joins); n > 0 {
+		ok := 0
+		for _, j := range a.load.joins {
+			if j {
+				ok++
+			}
+		}
+		l.JoinRate = float64(ok) / float64(n)
+	}
+	a.load.mu.Unlock()
+
+	l.Score = float64(l.Calls)*weightCall +
+		l.CPU*weightCPU +
+		float64(l.FloodWaits)*weightFloodWait +
+		(1-l.JoinRate)*weightJoinFail
+	return l
+}
+
+func pruneFloodWaits(list []time.Time) []time.Time {
+	cutoff := time.Now().Add(-floodWindow)
+	i := 0
+	for i < len(list) && list[i].Before(cutoff) {
+		i++
+	}
+	return list[i:]
+}
+
+// Loads returns the load of every assistant, in index order.
+func (m *AssistantManager) Loads() []AssistantLoad {
+	var loads []AssistantLoad
+	m.ForEach(func(a *Assistant) {
+		loads = append(loads, a.Load())
+	})
+	return loads
+}
+
+// LoadScore is the load score of the 1-based assistant idx; ok is false
+// when the assistant is down or does not exist.
+func (m *AssistantManager) LoadScore(idx int) (float64, bool) {
+	a, err := m.Get(idx)
+	if err != nil || !a.Healthy() {
+		return 0, false
+	}
+	return a.Load().Score, true
+}
+
+// Move hands chatID over to the 1-based assistant idx by hand.
+func (m *AssistantManager) Move(chatID int64, idx int) (*Assistant, error) {
+	to, err := m.Get(idx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := m.ForChat(chatID)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return to, nil
+	}
+	if !to.Healthy() {
+		return nil, fmt.Errorf("assistant %d is down: %s", idx, to.healthReason())
+	}
+	return m.moveChatTo(chatID, from, to, "moved by hand")
+}
+
+// Resync reloads the assistant of every cached chat after the assignments
+// were changed in the database, moving active rooms along. It returns how
+// many chats changed assistant.
+func (m *AssistantManager) Resync() int {
+	if m == nil || AssistantIndexFunc == nil {
+		return 0
+	}
+
+	m.cacheMu.RLock()
+	cached := make(map[int64]int, len(m.indexCache))
+	for chatID, idx := range m.indexCache {
+		cached[chatID] = idx
+	}
+	m.cacheMu.RUnlock()
+
+	moved := 0
+	for chatID, old := range cached {
+		idx, err := AssistantIndexFunc(chatID, m.Count())
+		if err != nil || idx == old {
+			continue
+		}
+		to, err := m.Get(idx)
+		if err != nil {
+			continue
+		}
+		m.moveMu.Lock()
+		m.pointChat(chatID, to)
+		m.moveMu.Unlock()
+		moveRoom(chatID, to)
+		moved++
+	}
+	return moved
+}
@@ -0,0 +1,225 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"main/internal/locales"
+)
+
+// AssistantRemoveFunc drops a 1-based assistant index from the stored chat
+// assignments, shifting the ones after it down.
+var AssistantRemoveFunc func(idx int) error // AssistantRemoveFunc = database.RemoveAssistantIndex
+
+var (
+	ErrAssistantExists = errors.New("this account is already an assistant")
+	ErrLastAssistant   = errors.New("the last assistant cannot be removed")
+)
+
+// OnAdd registers fn to run for every assistant added with Add, after it
+// joined the manager.
+func (m *AssistantManager) OnAdd(fn func(*Assistant)) {
+	m.listMu.Lock()
+	m.onAdd = append(m.onAdd, fn)
+	m.listMu.Unlock()
+}
+
+// Add logs a session in and appends it as a new assistant. New chats can
+// be assigned to it right away; rebalance to move existing chats over.
+func (m *AssistantManager) Add(session, sessionType string) (*Assistant, error) {
+	m.listMu.Lock()
+	seq := m.sessionSeq
+	m.sessionSeq++
+	m.listMu.Unlock()
+
+	client, err := initAssistantClient(
+		appID, appHash, session, sessionType,
+		fmt.Sprintf("ass%d.session", seq),
+	)
+	if err != nil {
+		return nil, err
+	}
+	user, err := client.GetMe()
+	if err != nil {
+		client.Stop()
+		return nil, fmt.Errorf("failed to log in: %w", err)
+	}
+	if user.Bot {
+		client.Stop()
+		return nil, fmt.Errorf("%s is a bot account", user.FirstName)
+	}
+
+	m.listMu.Lock()
+	for _, a := range m.list {
+		if a.User.ID == user.ID {
+			m.listMu.Unlock()
+			client.Stop()
+			return nil, ErrAssistantExists
+		}
+	}
+	a := newAssistant(len(m.list), client, user)
+	m.list = append(m.list, a)
+	hooks := slices.Clone(m.onAdd)
+	m.listMu.Unlock()
+
+	// The index may have belonged to a removed assistant
+	m.forgetBans(a.Index() + 1)
+
+	for _, fn := range hooks {
+		fn(a)
+	}
+
+	logger.InfoF("assistant=%d added: %s", a.Index()+1, user.FirstName)
+	return a, nil
+}
+
+// Remove drains the 1-based assistant idx and shuts it down. Its chats
+// are pointed at the other assistants first; only active rooms are handed
+// over, idle chats just get the new assignment. The stored assignments are
+// updated, the assistants after it shift down by one and the owner gets one
+// notice with the totals. It returns the removed assistant and how many
+// active rooms were moved.
+func (m *AssistantManager) Remove(idx int) (*Assistant, int, error) {
+	a, err := m.Get(idx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if m.Count() == 1 {
+		return nil, 0, ErrLastAssistant
+	}
+	if len(m.candidates(0, idx)) == 0 {
+		return nil, 0, ErrNoHealthyAssistant
+	}
+	if AssistantRemoveFunc == nil {
+		return nil, 0, fmt.Errorf("AssistantRemoveFunc is not set")
+	}
+
+	a.setRetired(true)
+
+	moved, dropped := 0, 0
+	for _, chatID := range m.chatsOf(idx) {
+		r, ok := GetRoom(chatID, nil)
+		active := ok && r.IsActiveChat()
+
+		to, repointed, err := m.repoint(chatID, a)
+		if err != nil {
+			logger.WarnF("Failed to move chat_id=%d off assistant=%d: %v", chatID, idx, err)
+			if ok {
+				r.Destroy()
+				if active {
+					dropped++
+				}
+			}
+			continue
+		}
+		if !repointed {
+			continue
+		}
+
+		if !active {
+			if ok {
+				r.setPlayer(&NtgPlayer{Ntg: to.Ntg, Assistant: to})
+			}
+			continue
+		}
+		assistantFailovers.Inc()
+		if err := moveRoom(chatID, to); err != nil {
+			dropped++
+			continue
+		}
+		moved++
+	}
+
+	m.moveMu.Lock()
+	if err := AssistantRemoveFunc(idx); err != nil {
+		m.moveMu.Unlock()
+		a.setRetired(false)
+		return nil, moved, err
+	}
+	m.compact(idx)
+	m.moveMu.Unlock()
+
+	a.Ntg.Close()
+	a.Client.Stop()
+
+	logger.InfoF("assistant=%d removed: %s, %d rooms moved, %d dropped",
+		idx, a.User.FirstName, moved, dropped)
+	notifyOwner(func(target int64) string {
+		return F(target, "assistant_removed_notice", locales.Arg{
+			"index":   idx,
+			"name":    a.User.FirstName,
+			"moved":   moved,
+			"dropped": dropped,
+		})
+	})
+	return a, moved, nil
+}
+
+// compact drops the 1-based assistant idx from the list and shifts the
+// indexes after it down, in the list as well as in the caches.
+func (m *AssistantManager) compact(idx int) {
+	m.listMu.Lock()
+	m.list = slices.Delete(m.list, idx-1, idx)
+	for i, a := range m.list {
+		a.index.Store(int32(i))
+	}
+	m.listMu.Unlock()
+
+	shift := func(i int) (int, bool) {
+		switch {
+		case i == idx:
+			return 0, false
+		case i > idx:
+			return i - 1, true
+		default:
+			return i, true
+		}
+	}
+
+	m.cacheMu.Lock()
+	for chatID, i := range m.indexCache {
+		if n, ok := shift(i); ok {
+			m.indexCache[chatID] = n
+		} else {
+			delete(m.indexCache, chatID)
+		}
+	}
+	m.cacheMu.Unlock()
+
+	m.bannedMu.Lock()
+	for chatID, banned := range m.bannedIn {
+		shifted := make(map[int]bool, len(banned))
+		for i := range banned {
+			if n, ok := shift(i); ok {
+				shifted[n] = true
+			}
+		}
+		if len(shifted) == 0 {
+			delete(m.bannedIn, chatID)
+			continue
+		}
+		m.bannedIn[chatID] = shifted
+	}
+	m.bannedMu.Unlock()
+}
@@ -23,7 +23,6 @@ package core
 import (
 	"fmt"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -33,6 +32,10 @@ import (
 
 var GetChatLanguage func(chatID int64) (string, error) // overwritten from main.go
 
+// CleanNowPlaying reports whether a chat wants old "now playing" messages
+// deleted; overwritten from main.go.
+var CleanNowPlaying = func(chatID int64) bool { return true }
+
 func AddMeMarkup(chatID int64) tg.ReplyMarkup {
 	return tg.NewKeyboard().
 		AddRow(
@@ -204,7 +207,7 @@ func F(chatID int64, key string, values ...locales.Arg) string {
 	if GetChatLanguage != nil {
 		l, err := GetChatLanguage(chatID)
 		if err != nil {
-			gologging.Error(
+			logger.Error(
 				"Failed to get language for " + utils.IntToStr(
 					chatID,
 				) + " Got error " + err.Error(),
@@ -27,7 +27,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -106,6 +105,16 @@ func (cs *ChatState) SetAssistantBanned(v bool) {
 	cs.AssistantBanned = &v
 }
 
+// setAssistant switches the chat to another assistant; its membership is
+// looked up again on next use.
+func (cs *ChatState) setAssistant(a *Assistant) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.Assistant = a
+	cs.AssistantPresent = nil
+	cs.AssistantBanned = nil
+}
+
 func (cs *ChatState) SetVoiceChatActive(v bool) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
@@ -182,10 +191,22 @@ func (cs *ChatState) TryJoin() error {
 
 		_, err := cs.Assistant.Client.JoinChannel(link)
 		if err == nil || telegram.MatchError(err, "USER_ALREADY_PARTICIPANT") {
+			cs.Assistant.RecordJoin(true)
 			cs.SetAssistantPresent(true)
 			cs.SetAssistantBanned(false)
 			return nil
 		}
+
+		// Expired links and pending join requests say nothing about the
+		// assistant itself, so they do not count against its join rate.
+		switch {
+		case telegram.GetFloodWait(err) > 0:
+			cs.Assistant.RecordFloodWait()
+			cs.Assistant.RecordJoin(false)
+		case !telegram.MatchError(err, "INVITE_HASH_EXPIRED") &&
+			!telegram.MatchError(err, "INVITE_REQUEST_SENT"):
+			cs.Assistant.RecordJoin(false)
+		}
 		return err
 	}
 
@@ -272,7 +293,7 @@ func (cs *ChatState) ensureAssistantState(force bool) error {
 
 	member, err := Bot.GetChatMember(cs.ChatID, cs.Assistant.User.ID)
 	if err != nil {
-		gologging.Error(
+		logger.Error(
 			"raw error of GetChatMember in core.ChatState" + err.Error(),
 		)
 		if strings.Contains(err.Error(), "there is no peer with id") {
@@ -27,7 +27,6 @@ import (
 	"net"
 	"strings"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/ubot"
@@ -39,6 +38,10 @@ var (
 
 	Assistants         *AssistantManager
 	AssistantIndexFunc func(chatID int64, assistantCount int) (int, error) // AssistantIndexFunc = database.GetAssistantIndex
+
+	// Kept for assistants added after startup.
+	appID   int32
+	appHash string
 )
 
 func Init(
@@ -49,32 +52,30 @@ func Init(
 	loggerID int64,
 ) func() {
 	if len(sessions) == 0 {
-		gologging.Fatal("No STRING_SESSIONS provided for assistant client.")
+		logger.Fatal("No STRING_SESSIONS provided for assistant client.")
 	}
 
-	gologging.Info("Starting bot client...")
+	logger.Info("Starting bot client...")
 	Bot = initBotClient(apiID, apiHash, token)
 	BUser = getSelfOrFatal(Bot, "bot")
 
-	gologging.Info("Starting assistant clients...")
+	logger.Info("Starting assistant clients...")
 
 	assistants := make([]*Assistant, 0, len(sessions))
 
 	for i, sess := range sessions {
-		gologging.InfoF("Initializing assistant[%d]...", i)
+		logger.InfoF("Initializing assistant=%d...", i+1)
 
-		client := initAssistantClient(apiID, apiHash, sess, sessionType, i)
+		client, err := initAssistantClient(
+			apiID, apiHash, sess, sessionType,
+			fmt.Sprintf("ass%d.session", i),
+		)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
 		user := getSelfOrFatal(client, fmt.Sprintf("assistant[%d]", i))
-		ctx := ubot.NewContext(client)
 
-		client.SetCommandPrefixes(".")
-
-		assistants = append(assistants, &Assistant{
-			Index:  i,
-			Client: client,
-			User:   user,
-			Ntg:    ctx,
-		})
+		assistants = append(assistants, newAssistant(i, client, user))
 
 		if loggerID != 0 {
 			_, _ = client.SendMessage(
@@ -83,7 +84,7 @@ func Init(
 			)
 		}
 
-		gologging.InfoF("assistant[%d] ready: %s", i, user.FirstName)
+		logger.InfoF("assistant=%d ready: %s", i+1, user.FirstName)
 	}
 
 	Bot.SetCommandPrefixes("/")
@@ -91,24 +92,26 @@ func Init(
 	Assistants = &AssistantManager{
 		list:       assistants,
 		indexCache: make(map[int64]int),
+		sessionSeq: len(assistants),
 	}
-	gologging.Info("All assistants initialized successfully.")
+	appID, appHash = apiID, apiHash
+	logger.Info("All assistants initialized successfully.")
 
 	return func() {
-		gologging.Info("Shutting down assistant contexts...")
-		for _, a := range Assistants.list {
+		logger.Info("Shutting down assistant contexts...")
+		Assistants.ForEach(func(a *Assistant) {
 			a.Ntg.Close()
-		}
+		})
 
-		gologging.Info("Stopping bot...")
+		logger.Info("Stopping bot...")
 		Bot.Stop()
 
-		gologging.Info("Stopping assistants...")
-		for _, a := range Assistants.list {
+		logger.Info("Stopping assistants...")
+		Assistants.ForEach(func(a *Assistant) {
 			a.Client.Stop()
-		}
+		})
 
-		gologging.Info("Shutdown complete.")
+		logger.Info("Shutdown complete.")
 	}
 }
 
@@ -124,14 +127,14 @@ func initBotClient(apiID int32, apiHash, token string) *telegram.Client {
 		Session:   "bot.session",
 	})
 	if err != nil {
-		gologging.Fatal("❌ Failed to create bot: " + err.Error())
+		logger.Fatal("❌ Failed to create bot: " + err.Error())
 	}
 
 	if err := client.LoginBot(token); err != nil {
 		if strings.Contains(err.Error(), "ACCESS_TOKEN_EXPIRED") {
-			gologging.Fatal("❌ Bot token has been revoked or expired.")
+			logger.Fatal("❌ Bot token has been revoked or expired.")
 		} else {
-			gologging.Fatal("❌ Failed to start the bot: " + err.Error())
+			logger.Fatal("❌ Failed to start the bot: " + err.Error())
 		}
 	}
 	return client
@@ -139,23 +142,22 @@ func initBotClient(apiID int32, apiHash, token string) *telegram.Client {
 
 func initAssistantClient(
 	apiID int32,
-	apiHash, session, sessionType string,
-	idx int,
-) *telegram.Client {
+	apiHash, session, sessionType, sessionFile string,
+) (*telegram.Client, error) {
 	var stringSession string
 
 	switch strings.ToLower(sessionType) {
 	case "pyrogram", "pyro":
 		sess, err := decodePyrogramSessionString(session)
 		if err != nil {
-			gologging.Fatal("Failed to decode Pyrogram session: " + err.Error())
+			return nil, fmt.Errorf("failed to decode Pyrogram session: %w", err)
 		}
 		stringSession = sess.Encode()
 
 	case "telethon":
 		sess, err := decodeTelethonSessionString(session)
 		if err != nil {
-			gologging.Fatal("Failed to decode Telethon session: " + err.Error())
+			return nil, fmt.Errorf("failed to decode Telethon session: %w", err)
 		}
 		stringSession = sess.Encode()
 
@@ -163,7 +165,7 @@ func initAssistantClient(
 		stringSession = session
 
 	default:
-		gologging.Fatal("Invalid SESSION_TYPE: " + sessionType)
+		return nil, fmt.Errorf("invalid SESSION_TYPE: %s", sessionType)
 	}
 
 	client, err := telegram.NewClient(telegram.ClientConfig{
@@ -172,21 +174,34 @@ func initAssistantClient(
 		LogLevel:      telegram.LogError,
 		ParseMode:     "HTML",
 		StringSession: stringSession,
-		Session:       fmt.Sprintf("ass%d.session", idx),
+		Session:       sessionFile,
 	})
 	if err != nil {
-		gologging.Fatal("Failed to create assistant: " + err.Error())
+		return nil, fmt.Errorf("failed to create assistant: %w", err)
 	}
 
-	return client
+	return client, nil
+}
+
+func newAssistant(idx int, client *telegram.Client, user *telegram.UserObj) *Assistant {
+	client.SetCommandPrefixes(".")
+	ntg := ubot.NewContext(client)
+	ntg.OnCallDiscarded(onCallDiscarded)
+	a := &Assistant{
+		Client: client,
+		User:   user,
+		Ntg:    ntg,
+	}
+	a.index.Store(int32(idx))
+	return a
 }
 
 func getSelfOrFatal(c *telegram.Client, label string) *telegram.UserObj {
 	me, err := c.GetMe()
 	if err != nil {
-		gologging.Fatal("❌ Failed to GetMe for " + label + ": " + err.Error())
+		logger.Fatal("❌ Failed to GetMe for " + label + ": " + err.Error())
 	}
-	gologging.Info("Logged in as " + label + ": " + me.FirstName)
+	logger.Info("Logged in as " + label + ": " + me.FirstName)
 	return me
 }
 
@@ -45,7 +45,15 @@ func buildAudioFilter(speed float64) string {
 	return strings.Join(filters, ",")
 }
 
-func normalizeVideo(path string, speed float64) (int, int, int, string) {
+// normalizeVideo fits the video into 1280x720, or 1920x1080 for a screen
+// share, or into maxHeight when it is a lower positive cap, keeping the
+// aspect ratio.
+func normalizeVideo(
+	path string,
+	speed float64,
+	maxHeight int,
+	screen bool,
+) (int, int, int, string) {
 	if speed <= 0 {
 		speed = 1.0
 	}
@@ -56,6 +64,13 @@ func normalizeVideo(path string, speed float64) (int, int, int, string) {
 	}
 	maxW := 1280
 	maxH := 720
+	if screen {
+		maxW, maxH = 1920, 1080
+	}
+	if maxHeight > 0 && maxHeight < maxH {
+		maxW = maxW * maxHeight / maxH
+		maxH = maxHeight
+	}
 	if w > maxW {
 		h = h * maxW / w
 		w = maxW
@@ -29,14 +29,18 @@ import (
 	"main/internal/config"
 )
 
+var logger = config.GetLogger(config.DefaultLogger)
+
 type TgLogger struct {
 	gl  *gologging.Logger
+	log config.Logger
 	lvl telegram.LogLevel
 }
 
 func GetTgLogger(name string, lvl telegram.LogLevel) *TgLogger {
 	l := &TgLogger{
 		gl:  gologging.GetLogger(name),
+		log: config.GetLogger(name),
 		lvl: lvl,
 	}
 	l.SetLevel(lvl)
@@ -46,25 +50,25 @@ func GetTgLogger(name string, lvl telegram.LogLevel) *TgLogger {
 
 func (l *TgLogger) Debug(msg any, a ...any) {
 	if l.lvl <= telegram.DebugLevel {
-		l.gl.DebugF("%v %v", msg, a)
+		l.log.DebugF("%v %v", msg, a)
 	}
 }
 
 func (l *TgLogger) Info(msg any, a ...any) {
 	if l.lvl <= telegram.InfoLevel {
-		l.gl.InfoF("%v %v", msg, a)
+		l.log.InfoF("%v %v", msg, a)
 	}
 }
 
 func (l *TgLogger) Warn(msg any, a ...any) {
 	if l.lvl <= telegram.WarnLevel {
-		l.gl.WarnF("%v %v", msg, a)
+		l.log.WarnF("%v %v", msg, a)
 	}
 }
 
 func (l *TgLogger) Error(msg any, a ...any) {
 	if l.lvl <= telegram.ErrorLevel {
-		l.gl.ErrorF("%v %v", msg, a)
+		l.log.ErrorF("%v %v", msg, a)
 	}
 }
 
@@ -0,0 +1,90 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"strconv"
+
+	"main/internal/metrics"
+)
+
+var playFailures = metrics.NewCounter(
+	"yukki_play_failures_total",
+	"Tracks that failed to start playing.",
+)
+
+func init() {
+	metrics.NewGaugeFunc(
+		"yukki_active_rooms",
+		"Rooms currently playing a track.",
+		func() float64 {
+			n := 0
+			for _, r := range snapshotRooms() {
+				if r.IsActiveChat() {
+					n++
+				}
+			}
+			return float64(n)
+		},
+	)
+	metrics.NewGaugeFunc(
+		"yukki_queued_tracks",
+		"Tracks waiting in all room queues.",
+		func() float64 {
+			n := 0
+			for _, r := range snapshotRooms() {
+				n += len(r.Queue())
+			}
+			return float64(n)
+		},
+	)
+	metrics.NewGaugeVecFunc(
+		"yukki_assistant_calls",
+		"Active calls handled by each assistant.",
+		"assistant",
+		func() map[string]float64 {
+			calls := make(map[string]float64)
+			for i := 1; i <= Assistants.Count(); i++ {
+				calls[strconv.Itoa(i)] = 0
+			}
+			for _, r := range snapshotRooms() {
+				if !r.IsActiveChat() {
+					continue
+				}
+				if idx, ok := Assistants.cachedIndex(r.ChatID()); ok {
+					calls[strconv.Itoa(idx)]++
+				}
+			}
+			return calls
+		},
+	)
+}
+
+func snapshotRooms() []*RoomState {
+	roomsMu.RLock()
+	defer roomsMu.RUnlock()
+
+	list := make([]*RoomState, 0, len(rooms))
+	for _, r := range rooms {
+		list = append(list, r)
+	}
+	return list
+}
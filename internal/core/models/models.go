@@ -26,18 +26,35 @@ import (
 	"github.com/amarnathcjd/gogram/telegram"
 )
 
+// Video modes, chosen per chat in /settings.
+const (
+	VideoCamera VideoMode = ""       // video as the camera
+	VideoScreen VideoMode = "screen" // video as a screen share
+	VideoDual   VideoMode = "dual"   // video as a screen share, artwork or a visualizer as the camera
+)
+
+// VideoModes lists the video modes in the order /settings cycles them.
+var VideoModes = []VideoMode{VideoCamera, VideoScreen, VideoDual}
+
 type (
 	Track struct {
-		ID        string       // track unique id
-		Title     string       // title
-		Duration  int          // track duration in seconds
-		Artwork   string       // thumbnail url of the track
-		URL       string       // track url
-		Requester string       // html mention or @username who requested this track
-		Video     bool         // whether this track will be played as video
-		Source    PlatformName // unique PlatformName
+		ID          string       // track unique id
+		Title       string       // title
+		Duration    int          // track duration in seconds
+		Artwork     string       // thumbnail url of the track
+		URL         string       // track url
+		Requester   string       // html mention or @username who requested this track
+		RequesterID int64        // user id of the requester, 0 if not a user
+		Video       bool         // whether this track will be played as video
+		Source      PlatformName // unique PlatformName
+		Artists     []string     // performers, if known (Spotify)
+		Channel     string       // uploader name, if known (YouTube)
+		ChannelID   string       // uploader channel id, if known (YouTube)
+		MaxHeight   int          // video height cap set by the chat filter, 0 for default
+		VideoMode   VideoMode    // how a video track is sent into the call
 	}
 	PlatformName string
+	VideoMode    string
 
 	Platform interface {
 		Name() PlatformName
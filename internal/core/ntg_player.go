@@ -21,19 +21,42 @@
 package core
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/amarnathcjd/gogram/telegram"
+
+	state "main/internal/core/models"
+	"main/internal/utils"
 	"main/ntgcalls"
 	"main/ubot"
 )
 
 type NtgPlayer struct {
-	Ntg *ubot.Context
+	Ntg       *ubot.Context
+	Assistant *Assistant
 }
 
 func (p *NtgPlayer) Play(r *RoomState) error {
-	desc := getMediaDescription(r.fpath, r.position, r.speed, r.track.Video)
-	return p.Ntg.Play(r.chatID, desc)
+	screen := r.track.VideoMode == state.VideoScreen ||
+		r.track.VideoMode == state.VideoDual
+	desc := getMediaDescription(
+		r.fpath,
+		r.position,
+		r.speed,
+		r.track.Video,
+		r.track.MaxHeight,
+		screen,
+	)
+	if r.track.Video && screen {
+		applyVideoMode(&desc, r.track, r.fpath, r.position, r.speed)
+	}
+	err := p.Ntg.Play(r.chatID, desc)
+	if telegram.GetFloodWait(err) > 0 {
+		p.Assistant.RecordFloodWait()
+	}
+	return err
 }
 
 func (p *NtgPlayer) Pause(r *RoomState) (bool, error) {
@@ -61,6 +84,8 @@ func getMediaDescription(
 	pos int,
 	speed float64,
 	isVideo bool,
+	maxHeight int,
+	screen bool,
 ) ntgcalls.MediaDescription {
 	if speed < 0.5 {
 		speed = 0.5
@@ -84,7 +109,7 @@ func getMediaDescription(
 		baseCmd += "-ss " + strconv.Itoa(pos) + " "
 	}
 
-	baseCmd += "-v warning -i \"" + url + "\" "
+	baseCmd += "-v warning -i " + shellQuote(url) + " "
 
 	// Audio pipeline
 	audioCmd := baseCmd
@@ -105,7 +130,7 @@ func getMediaDescription(
 		}
 	}
 
-	w, h, fps, filter := normalizeVideo(url, speed)
+	w, h, fps, filter := normalizeVideo(url, speed, maxHeight, screen)
 
 	video := &ntgcalls.VideoDescription{
 		MediaSource: ntgcalls.MediaSourceShell,
@@ -126,3 +151,82 @@ func getMediaDescription(
 		Camera:     video,
 	}
 }
+
+// applyVideoMode moves the video of desc, already sized for a screen share
+// by getMediaDescription, to the screen share for the screen and dual
+// modes. Dual puts the artwork, or a visualizer of the audio when there is
+// none, on the camera.
+func applyVideoMode(
+	desc *ntgcalls.MediaDescription,
+	t *state.Track,
+	url string,
+	pos int,
+	speed float64,
+) {
+	if t.VideoMode != state.VideoScreen && t.VideoMode != state.VideoDual {
+		return
+	}
+
+	desc.Screen = desc.Camera
+	desc.Camera = nil
+
+	if t.VideoMode == state.VideoDual {
+		// The artwork comes from third-party metadata, so only plain
+		// http(s) URLs are used.
+		artwork := utils.CleanURL(t.Artwork)
+		if !strings.HasPrefix(artwork, "https://") && !strings.HasPrefix(artwork, "http://") {
+			artwork = ""
+		}
+		desc.Camera = companionVideo(artwork, url, pos, speed)
+	}
+}
+
+// companionVideo is the camera of the dual mode: the artwork as a still,
+// or a waveform of the audio.
+func companionVideo(artwork, url string, pos int, speed float64) *ntgcalls.VideoDescription {
+	const w, h = 640, 360
+	video := &ntgcalls.VideoDescription{
+		MediaSource: ntgcalls.MediaSourceShell,
+		Width:       w,
+		Height:      h,
+	}
+
+	if artwork != "" {
+		video.Fps = 5
+		video.Input = "ffmpeg -v warning -loop 1 -framerate 5 -i " + shellQuote(artwork) + " " +
+			fmt.Sprintf(
+				"-filter:v \"scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2\" ",
+				w, h, w, h,
+			) +
+			"-f rawvideo -r 5 -pix_fmt yuv420p pipe:1"
+		return video
+	}
+
+	video.Fps = 30
+	filter := "showwaves=s=" + strconv.Itoa(w) + "x" + strconv.Itoa(h) + ":mode=cline:rate=30"
+	if atempo := buildAudioFilter(speed); atempo != "" {
+		filter = atempo + "," + filter
+	}
+	video.Input = videoInput(url, pos, "-filter_complex \"[0:a]"+filter+"[v]\" -map \"[v]\" ", 30)
+	return video
+}
+
+// videoInput is an ffmpeg command writing raw frames of url to stdout.
+func videoInput(url string, pos int, filter string, fps int) string {
+	cmd := "ffmpeg "
+	if isStreamURL(url) {
+		cmd += "-reconnect 1 -reconnect_streamed 1 -reconnect_delay_max 5 "
+	}
+	if pos > 0 {
+		cmd += "-ss " + strconv.Itoa(pos) + " "
+	}
+	cmd += "-v warning -i " + shellQuote(url) + " " + filter
+	cmd += "-f rawvideo -r " + strconv.Itoa(fps) + " -pix_fmt yuv420p pipe:1"
+	return cmd
+}
+
+// shellQuote quotes s as a single word for the shell that runs the ffmpeg
+// commands, so nothing in it is expanded.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,112 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"sync"
+	"time"
+
+	state "main/internal/core/models"
+)
+
+// finishSlack is how close to its end a track has to get, in seconds, to
+// count as played to the end.
+const finishSlack = 5
+
+// PlayRecord is one play of a track in a room, from the moment it started
+// until it ended, was skipped or the room stopped.
+type PlayRecord struct {
+	ChatID    int64
+	Track     *state.Track
+	StartedAt time.Time
+	EndedAt   time.Time
+	Finished  bool // played to the end rather than skipped or stopped
+
+	pausedAt  time.Time
+	pausedFor time.Duration
+}
+
+// Played is how long the track was actually playing, pauses excluded.
+func (p *PlayRecord) Played() time.Duration {
+	return p.EndedAt.Sub(p.StartedAt) - p.pausedFor
+}
+
+var (
+	playEndedMu sync.RWMutex
+	playEnded   []func(*PlayRecord)
+)
+
+// OnPlayEnded registers fn to receive every track play once it is over.
+// Hooks run in the background.
+func OnPlayEnded(fn func(*PlayRecord)) {
+	playEndedMu.Lock()
+	playEnded = append(playEnded, fn)
+	playEndedMu.Unlock()
+}
+
+// The methods below expect r to be locked.
+
+func (r *RoomState) startPlay() {
+	r.endPlay()
+	r.play = &PlayRecord{
+		ChatID:    r.chatID,
+		Track:     r.track,
+		StartedAt: time.Now(),
+	}
+}
+
+func (r *RoomState) pausePlay() {
+	if r.play != nil && r.play.pausedAt.IsZero() {
+		r.play.pausedAt = time.Now()
+	}
+}
+
+func (r *RoomState) resumePlay() {
+	if r.play != nil && !r.play.pausedAt.IsZero() {
+		r.play.pausedFor += time.Since(r.play.pausedAt)
+		r.play.pausedAt = time.Time{}
+	}
+}
+
+// endPlay closes the play of the current track, if any, and hands it to
+// the OnPlayEnded hooks. It must run before r.track is replaced.
+func (r *RoomState) endPlay() {
+	p := r.play
+	if p == nil {
+		return
+	}
+	r.play = nil
+	r.parse()
+
+	p.EndedAt = time.Now()
+	if !p.pausedAt.IsZero() {
+		p.pausedFor += p.EndedAt.Sub(p.pausedAt)
+	}
+	p.Finished = p.Track.Duration > 0 &&
+		r.track == p.Track &&
+		r.position >= p.Track.Duration-finishSlack
+
+	playEndedMu.RLock()
+	defer playEndedMu.RUnlock()
+	for _, fn := range playEnded {
+		go fn(p)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"errors"
+
+	"github.com/amarnathcjd/gogram/telegram"
+
+	"main/ubot"
+)
+
+// Private calls (/callme) use the user id as room id, so the room and
+// every control command work like they do for a group voice chat.
+
+var (
+	// ErrCallDeclined is returned by Play when the user declined, missed
+	// or hung up a private call before it was set up.
+	ErrCallDeclined = ubot.ErrCallDiscarded
+	// ErrUserUnreachable means the assistant does not know the user well
+	// enough to call them.
+	ErrUserUnreachable = errors.New("assistant cannot reach the user")
+)
+
+// ReachUser makes sure ass can call user. Calling needs the access hash of
+// the user, which the assistant only has after it saw them, for example
+// in a message they sent it or by resolving their username.
+func ReachUser(ass *Assistant, user *telegram.UserObj) error {
+	if _, err := ass.Client.ResolvePeer(user.ID); err == nil {
+		return nil
+	}
+	if user.Username == "" {
+		return ErrUserUnreachable
+	}
+	if _, err := ass.Client.ResolveUsername(user.Username); err != nil {
+		return ErrUserUnreachable
+	}
+	return nil
+}
+
+// onCallDiscarded ends the room of a private call the user hung up.
+func onCallDiscarded(_ *ubot.Context, chatID int64) {
+	r, ok := GetRoom(chatID, nil)
+	if !ok {
+		return
+	}
+	// A call declined while ringing already failed its Play, which
+	// reports that on its own.
+	active := r.IsActiveChat()
+	r.Destroy()
+	if !active {
+		return
+	}
+
+	logger.InfoF("Private call with %d was hung up", chatID)
+	if _, err := Bot.SendMessage(chatID, F(chatID, "callme_hung_up")); err != nil {
+		logger.ErrorF("Failed to send hang up notice to %d: %v", chatID, err)
+	}
+}
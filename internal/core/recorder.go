@@ -0,0 +1,380 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"main/internal/config"
+	"main/ntgcalls"
+)
+
+// Reasons a recording stopped, passed on to the OnRecordingDone hooks.
+const (
+	RecordStopped   = "stopped"
+	RecordTimeLimit = "time_limit"
+	RecordSizeLimit = "size_limit"
+	RecordCallEnded = "call_ended"
+)
+
+const (
+	recordDir = "downloads/recordings"
+	// Telegram takes files up to 2 GB from bots, leave some headroom.
+	recordPartSize   = 1900 << 20
+	recordCheckEvery = 5 * time.Second
+)
+
+var (
+	ErrAlreadyRecording = errors.New("this chat is already being recorded")
+	ErrNotRecording     = errors.New("this chat is not being recorded")
+)
+
+// Recording is a capture of what the assistant hears in a call. Audio goes
+// to an ogg file; with Video the incoming camera is written next to it and
+// both are merged once the recording stops.
+type Recording struct {
+	ChatID    int64
+	By        int64
+	ToDM      bool
+	Video     bool
+	StartedAt time.Time
+	// Set once the recording is finalized.
+	StoppedAt time.Time
+	Reason    string
+	Parts     []string
+	Err       error
+
+	ass       *Assistant
+	joined    bool
+	audioPath string
+	videoPath string
+	outPath   string
+	timer     *time.Timer
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+var (
+	recordings   = make(map[int64]*Recording)
+	recordingsMu sync.Mutex
+
+	recordingDoneMu sync.RWMutex
+	recordingDone   []func(*Recording)
+)
+
+// OnRecordingDone registers fn to receive every finished recording. The
+// hook owns the files in Parts and should call Cleanup when done.
+func OnRecordingDone(fn func(*Recording)) {
+	recordingDoneMu.Lock()
+	recordingDone = append(recordingDone, fn)
+	recordingDoneMu.Unlock()
+}
+
+// GetRecording returns the running recording of chatID.
+func GetRecording(chatID int64) (*Recording, bool) {
+	recordingsMu.Lock()
+	defer recordingsMu.Unlock()
+	rec, ok := recordings[chatID]
+	return rec, ok
+}
+
+// StartRecording starts capturing the call of chatID through ass, joining
+// it silently when nothing is playing. The recording stops on its own at
+// RECORD_MAX_DURATION, RECORD_MAX_SIZE_MB or when the call ends.
+func StartRecording(chatID int64, ass *Assistant, by int64, video, toDM bool) (*Recording, error) {
+	recordingsMu.Lock()
+	defer recordingsMu.Unlock()
+
+	if _, ok := recordings[chatID]; ok {
+		return nil, ErrAlreadyRecording
+	}
+	if err := os.MkdirAll(recordDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	base := filepath.Join(recordDir, fmt.Sprintf("rec_%d_%d", chatID, now.Unix()))
+	rec := &Recording{
+		ChatID:    chatID,
+		By:        by,
+		ToDM:      toDM,
+		Video:     video,
+		StartedAt: now,
+		ass:       ass,
+		joined:    ass.Ntg.Calls()[chatID] == nil,
+		audioPath: base + ".ogg",
+		stop:      make(chan struct{}),
+	}
+
+	desc := ntgcalls.MediaDescription{
+		Speaker: &ntgcalls.AudioDescription{
+			MediaSource:  ntgcalls.MediaSourceShell,
+			SampleRate:   48000,
+			ChannelCount: 2,
+			Input: "ffmpeg -v error -f s16le -ar 48000 -ac 2 -i pipe:0 " +
+				"-c:a libopus -b:a 96k -y " + rec.audioPath,
+		},
+	}
+	if video {
+		rec.videoPath = base + ".video.mkv"
+		desc.Camera = &ntgcalls.VideoDescription{
+			MediaSource: ntgcalls.MediaSourceShell,
+			Width:       1280,
+			Height:      720,
+			Fps:         30,
+			Input: "ffmpeg -v error -f rawvideo -pix_fmt yuv420p -s 1280x720 -r 30 -i pipe:0 " +
+				"-c:v libx264 -preset veryfast -crf 28 -y " + rec.videoPath,
+		}
+	}
+
+	if err := ass.Ntg.Record(chatID, desc); err != nil {
+		if rec.joined {
+			_ = ass.Ntg.Stop(chatID)
+		}
+		return nil, err
+	}
+
+	recordings[chatID] = rec
+	rec.timer = time.AfterFunc(
+		time.Duration(config.RecordMaxDuration)*time.Second,
+		func() { rec.finish(RecordTimeLimit) },
+	)
+	go rec.watch()
+
+	logger.InfoF("Recording started chat_id=%d video=%v", chatID, video)
+	return rec, nil
+}
+
+// StopRecording stops the recording of chatID. Finalizing and the
+// OnRecordingDone hooks run in the background.
+func StopRecording(chatID int64) error {
+	rec, ok := GetRecording(chatID)
+	if !ok {
+		return ErrNotRecording
+	}
+	rec.finish(RecordStopped)
+	return nil
+}
+
+// stopRecordingForRoom ends the recording of a room that is going away,
+// before its call is left.
+func stopRecordingForRoom(chatID int64) {
+	if rec, ok := GetRecording(chatID); ok {
+		rec.finish(RecordCallEnded)
+	}
+}
+
+// Duration is how long the recording ran, or runs so far.
+func (rec *Recording) Duration() time.Duration {
+	if rec.StoppedAt.IsZero() {
+		return time.Since(rec.StartedAt)
+	}
+	return rec.StoppedAt.Sub(rec.StartedAt)
+}
+
+// Size is the size of the recorded files so far.
+func (rec *Recording) Size() int64 {
+	var size int64
+	for _, p := range []string{rec.audioPath, rec.videoPath} {
+		if p == "" {
+			continue
+		}
+		if fi, err := os.Stat(p); err == nil {
+			size += fi.Size()
+		}
+	}
+	return size
+}
+
+// Cleanup removes every file of the recording.
+func (rec *Recording) Cleanup() {
+	files := append([]string{rec.audioPath, rec.videoPath, rec.outPath}, rec.Parts...)
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			logger.ErrorF("failed to remove file %s: %v", f, err)
+		}
+	}
+}
+
+// watch enforces the size limit and notices calls that ended without the
+// room being destroyed, like a closed voice chat.
+func (rec *Recording) watch() {
+	ticker := time.NewTicker(recordCheckEvery)
+	defer ticker.Stop()
+
+	maxSize := config.RecordMaxSizeMB << 20
+	for {
+		select {
+		case <-rec.stop:
+			return
+		case <-ticker.C:
+			if rec.ass.Ntg.Calls()[rec.ChatID] == nil {
+				rec.finish(RecordCallEnded)
+				return
+			}
+			if rec.Size() >= maxSize {
+				rec.finish(RecordSizeLimit)
+				return
+			}
+		}
+	}
+}
+
+func (rec *Recording) finish(reason string) {
+	rec.stopOnce.Do(func() {
+		rec.timer.Stop()
+		close(rec.stop)
+		rec.Reason = reason
+		rec.StoppedAt = time.Now()
+
+		recordingsMu.Lock()
+		delete(recordings, rec.ChatID)
+		recordingsMu.Unlock()
+
+		if err := rec.ass.Ntg.StopRecord(rec.ChatID); err != nil {
+			logger.ErrorF("Failed to stop recording chat_id=%d: %v", rec.ChatID, err)
+		}
+		// A call joined only to record has nothing left to do.
+		if rec.joined {
+			if _, ok := GetRoom(rec.ChatID, nil); !ok {
+				_ = rec.ass.Ntg.Stop(rec.ChatID)
+			}
+		}
+
+		go rec.finalize()
+	})
+}
+
+func (rec *Recording) finalize() {
+	logger.InfoF(
+		"Recording stopped chat_id=%d reason=%s duration=%s",
+		rec.ChatID, rec.Reason, rec.Duration().Round(time.Second),
+	)
+
+	// ffmpeg is still flushing after its input was closed.
+	waitFileSettled(rec.audioPath)
+	path := rec.audioPath
+	if rec.Video {
+		waitFileSettled(rec.videoPath)
+		path, rec.Err = mergeRecording(rec.audioPath, rec.videoPath)
+	}
+	rec.outPath = path
+	if rec.Err == nil {
+		rec.Parts, rec.Err = splitRecording(path, rec.Duration())
+	}
+
+	recordingDoneMu.RLock()
+	hooks := recordingDone
+	recordingDoneMu.RUnlock()
+
+	if len(hooks) == 0 {
+		rec.Cleanup()
+		return
+	}
+	for _, fn := range hooks {
+		fn(rec)
+	}
+}
+
+// waitFileSettled waits until path stopped growing, for up to 15 seconds.
+func waitFileSettled(path string) {
+	var last int64 = -1
+	for i := 0; i < 30; i++ {
+		fi, err := os.Stat(path)
+		if err == nil && fi.Size() > 0 && fi.Size() == last {
+			return
+		}
+		if err == nil {
+			last = fi.Size()
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func mergeRecording(audioPath, videoPath string) (string, error) {
+	if _, err := os.Stat(videoPath); err != nil {
+		// No camera was received, keep the audio alone.
+		return audioPath, nil
+	}
+
+	out := videoPath[:len(videoPath)-len(".video.mkv")] + ".mp4"
+	cmd := exec.Command(
+		"ffmpeg", "-v", "error",
+		"-i", videoPath, "-i", audioPath,
+		"-map", "0:v", "-map", "1:a",
+		"-c:v", "copy", "-c:a", "aac",
+		"-shortest", "-movflags", "+faststart",
+		"-y", out,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("merging recording: %w: %s", err, output)
+	}
+	return out, nil
+}
+
+// splitRecording cuts path into parts Telegram accepts. Files that already
+// fit are returned as they are.
+func splitRecording(path string, duration time.Duration) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() <= recordPartSize {
+		return []string{path}, nil
+	}
+
+	parts := int(fi.Size()/recordPartSize) + 1
+	segment := int(duration.Seconds())/parts + 1
+
+	ext := filepath.Ext(path)
+	pattern := path[:len(path)-len(ext)] + ".part%03d" + ext
+	cmd := exec.Command(
+		"ffmpeg", "-v", "error", "-i", path,
+		"-c", "copy", "-map", "0",
+		"-f", "segment", "-segment_time", strconv.Itoa(segment),
+		"-reset_timestamps", "1",
+		"-y", pattern,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("splitting recording: %w: %s", err, output)
+	}
+
+	files, err := filepath.Glob(path[:len(path)-len(ext)] + ".part*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("splitting recording: no parts written")
+	}
+	return files, nil
+}
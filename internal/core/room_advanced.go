@@ -70,6 +70,7 @@ func (r *RoomState) executeSeek(seconds int) error {
 		r.restorePlaybackSnapshot(snapshot)
 		return err
 	}
+	r.resumePlay()
 
 	if snapshot.muted {
 		r.p.Unmute(r)
@@ -263,3 +264,44 @@ func (r *RoomState) Unmute() (bool, error) {
 
 	return unmuted, nil
 }
+
+// MoveTo hands the room over to another assistant and continues the
+// current track where it was. The old assistant may already be gone, so
+// failing to stop it there is ignored.
+func (r *RoomState) MoveTo(ass *Assistant) error {
+	r.Lock()
+	defer r.Unlock()
+
+	old := r.p
+	r.p = &NtgPlayer{Ntg: ass.Ntg, Assistant: ass}
+
+	if r.track == nil || r.fpath == "" || !r.playing {
+		return nil
+	}
+
+	r.parse()
+	_ = old.Stop(r)
+
+	if err := r.p.Play(r); err != nil {
+		return err
+	}
+	r.updatedAt = time.Now().Unix()
+
+	if r.paused {
+		if _, err := r.p.Pause(r); err != nil {
+			r.paused = false
+		}
+	}
+	if r.muted {
+		if _, err := r.p.Mute(r); err != nil {
+			r.muted = false
+		}
+	}
+	return nil
+}
+
+func (r *RoomState) setPlayer(p Player) {
+	r.Lock()
+	defer r.Unlock()
+	r.p = p
+}
@@ -24,8 +24,6 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/Laky-64/gologging"
-
 	state "main/internal/core/models"
 )
 
@@ -77,12 +75,12 @@ func (r *RoomState) releaseFile() {
 
 	if !used {
 		if err := os.Remove(r.fpath); err != nil && !os.IsNotExist(err) {
-			gologging.ErrorF("failed to remove file %s: %v", r.fpath, err)
+			logger.ErrorF("failed to remove file %s: %v", r.fpath, err)
 		} else {
-			gologging.DebugF("removed unused file: %s", r.fpath)
+			logger.DebugF("removed unused file: %s", r.fpath)
 		}
 	} else {
-		gologging.DebugF("file still in use, skipped remove: %s", r.fpath)
+		logger.DebugF("file still in use, skipped remove: %s", r.fpath)
 	}
 }
 
@@ -104,22 +102,22 @@ func (r *RoomState) cleanupFile() {
 	roomsMu.RLock()
 	for _, t := range tracks {
 		if t == nil || t.ID == "" || isTrackUsed(t.ID, r.chatID) {
-			gologging.DebugF("track %s still in use, skip delete", t.ID)
+			logger.DebugF("track_id=%s still in use, skip delete", t.ID)
 			continue
 		}
 
 		pattern := filepath.Join("downloads", t.ID+".*")
 		matches, err := filepath.Glob(pattern)
 		if err != nil {
-			gologging.ErrorF("glob failed for %s: %v", pattern, err)
+			logger.ErrorF("glob failed for %s: %v", pattern, err)
 			continue
 		}
 
 		for _, f := range matches {
 			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
-				gologging.ErrorF("failed to remove file %s: %v", f, err)
+				logger.ErrorF("failed to remove file %s: %v", f, err)
 			} else {
-				gologging.DebugF("removed unused file: %s", f)
+				logger.DebugF("removed unused file: %s", f)
 			}
 		}
 	}
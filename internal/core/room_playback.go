@@ -25,8 +25,6 @@ import (
 	"runtime"
 	"time"
 
-	"github.com/Laky-64/gologging"
-
 	state "main/internal/core/models"
 )
 
@@ -46,16 +44,23 @@ func (r *RoomState) Play(t *state.Track, path string, force ...bool) error {
 }
 
 func (r *RoomState) startPlayback(t *state.Track, path string) error {
+	r.endPlay()
 	r.track = t
 	r.playing = true
 	r.fpath = path
 
 	if err := r.p.Play(r); err != nil {
+		playFailures.Inc()
+		logger.ErrorF(
+			"Playback failed chat_id=%d track_id=%s: %v",
+			r.chatID, t.ID, err,
+		)
 		r.cleanupFailedPlayback()
 		return err
 	}
 
 	r.resetPlaybackState()
+	r.startPlay()
 	return nil
 }
 
@@ -100,6 +105,7 @@ func (r *RoomState) updatePauseState() {
 	r.parse()
 	r.paused = true
 	r.muted = false
+	r.pausePlay()
 }
 
 func (r *RoomState) scheduleAutoResume(autoResumeAfter []time.Duration) {
@@ -145,6 +151,7 @@ func (r *RoomState) updateResumeState() {
 	r.muted = false
 	r.playing = true
 	r.updatedAt = time.Now().Unix()
+	r.resumePlay()
 }
 
 // Replay restarts the current track
@@ -168,7 +175,10 @@ func (r *RoomState) executeReplay() error {
 		return err
 	}
 
+	r.position = old
+	r.endPlay()
 	r.resetPlaybackState()
+	r.startPlay()
 	r.playing = true
 	r.scheduledTimers.cancelScheduledResume()
 	r.scheduledTimers.cancelScheduledUnmute()
@@ -182,7 +192,7 @@ func (r *RoomState) Stop() error {
 	defer r.Unlock()
 
 	_, file, line, _ := runtime.Caller(1)
-	gologging.DebugF("Stop Called from %s:%d", file, line)
+	logger.DebugF("Stop Called from %s:%d", file, line)
 
 	err := r.p.Stop(r)
 	r.clearPlaybackState()
@@ -191,6 +201,7 @@ func (r *RoomState) Stop() error {
 }
 
 func (r *RoomState) clearPlaybackState() {
+	r.endPlay()
 	r.track = nil
 	r.position = 0
 	r.playing = false
@@ -50,6 +50,7 @@ func (r *RoomState) shouldLoopCurrentTrack() bool {
 }
 
 func (r *RoomState) loopCurrentTrack() *state.Track {
+	r.endPlay()
 	r.position = 0
 	r.playing = true
 	r.paused = false
@@ -79,6 +80,7 @@ func (r *RoomState) removeTrackAtIndex(index int) {
 }
 
 func (r *RoomState) prepareNextTrack(track *state.Track) {
+	r.endPlay()
 	r.track = track
 	r.position = 0
 	r.playing = false
@@ -25,7 +25,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	state "main/internal/core/models"
@@ -64,7 +63,8 @@ type RoomState struct {
 	cplay  bool
 	mystic *telegram.NewMessage
 
-	p Player
+	p    Player
+	play *PlayRecord
 	*scheduledTimers
 }
 
@@ -72,7 +72,7 @@ type RoomState struct {
 
 func DeleteRoom(chatID int64) {
 	_, file, line, _ := runtime.Caller(1)
-	gologging.DebugF("DeleteRoom Called from %s:%d", file, line)
+	logger.DebugF("DeleteRoom Called from %s:%d", file, line)
 
 	roomsMu.RLock()
 	room, exists := rooms[chatID]
@@ -110,7 +110,8 @@ func createNewRoom(chatID int64, ass *Assistant) (*RoomState, bool) {
 			queue:  []*state.Track{},
 			speed:  1.0,
 			p: &NtgPlayer{
-				Ntg: ass.Ntg,
+				Ntg:       ass.Ntg,
+				Assistant: ass,
 			},
 		}
 		rooms[chatID] = room
@@ -218,7 +219,7 @@ func (r *RoomState) SetShuffle(enabled bool) {
 func (r *RoomState) SetMystic(m *telegram.NewMessage) {
 	r.Lock()
 	defer r.Unlock()
-	if r.mystic != nil {
+	if r.mystic != nil && CleanNowPlaying(r.mystic.ChannelID()) {
 		r.mystic.Delete()
 	}
 	r.mystic = m
@@ -279,8 +280,9 @@ func (r *RoomState) parse() {
 
 func (r *RoomState) Destroy() {
 	_, file, line, _ := runtime.Caller(1)
-	gologging.DebugF("Destroy Called from %s:%d", file, line)
+	logger.DebugF("Destroy Called from %s:%d", file, line)
 
+	stopRecordingForRoom(r.chatID)
 	r.Stop()
 	r.cleanupFile()
 	roomsMu.Lock()
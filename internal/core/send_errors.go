@@ -0,0 +1,80 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package core
+
+import "github.com/amarnathcjd/gogram/telegram"
+
+// SendFailure says why sending to a chat or user failed.
+type SendFailure string
+
+const (
+	SendBlocked   SendFailure = "blocked"
+	SendKicked    SendFailure = "kicked"
+	SendDeleted   SendFailure = "deleted"
+	SendFlood     SendFailure = "flood"
+	SendTemporary SendFailure = "temporary"
+)
+
+// sendFailureErrors are the RPC errors after which a peer never accepts
+// messages from the bot again. CHAT_WRITE_FORBIDDEN (muted or restricted
+// for a while) and PEER_ID_INVALID (peer missing from the session cache)
+// are left out on purpose: the chat may still be there, so they count as
+// temporary.
+var sendFailureErrors = map[SendFailure][]string{
+	SendBlocked: {
+		"USER_IS_BLOCKED",
+	},
+	SendKicked: {
+		"CHANNEL_PRIVATE",
+		"CHAT_FORBIDDEN",
+		"USER_NOT_PARTICIPANT",
+	},
+	SendDeleted: {
+		"CHAT_ID_INVALID",
+		"CHANNEL_INVALID",
+		"USER_IS_DEACTIVATED",
+		"USER_DEACTIVATED",
+	},
+}
+
+// ClassifySendError sorts a send error into a SendFailure. Anything not
+// known to be permanent or a flood wait counts as temporary.
+func ClassifySendError(err error) SendFailure {
+	if telegram.GetFloodWait(err) > 0 ||
+		telegram.MatchError(err, "FLOOD") ||
+		telegram.MatchError(err, "SLOWMODE_WAIT") {
+		return SendFlood
+	}
+	for _, kind := range []SendFailure{SendBlocked, SendKicked, SendDeleted} {
+		for _, e := range sendFailureErrors[kind] {
+			if telegram.MatchError(err, e) {
+				return kind
+			}
+		}
+	}
+	return SendTemporary
+}
+
+// Permanent reports whether the peer is gone for good and can be dropped
+// from the served lists.
+func (f SendFailure) Permanent() bool {
+	return f == SendBlocked || f == SendKicked || f == SendDeleted
+}
@@ -0,0 +1,105 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import "time"
+
+// APIKey is an access key of the HTTP control API. Only the SHA-256 hash of
+// the key is stored, the key itself is shown once when it is created.
+type APIKey struct {
+	Hash      string    `bson:"_id"`
+	Name      string    `bson:"name"`
+	CreatedBy int64     `bson:"created_by"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func AddAPIKey(key *APIKey) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	if err := store.SaveAPIKey(ctx, key); err != nil {
+		logger.ErrorF("Failed to add API key %s: %v", key.Name, err)
+		return err
+	}
+
+	dbCache.Set("api_key_"+key.Hash, key)
+	return nil
+}
+
+// GetAPIKey returns the key with the given hash, or nil if there is none.
+func GetAPIKey(hash string) (*APIKey, error) {
+	cacheKey := "api_key_" + hash
+	if cached, found := dbCache.Get(cacheKey); found {
+		if key, ok := cached.(*APIKey); ok {
+			return key, nil
+		}
+	}
+
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	key, err := store.GetAPIKey(ctx, hash)
+	if err != nil {
+		logger.ErrorF("Failed to get API key: %v", err)
+		return nil, err
+	}
+	// Misses are not cached: the hash comes from the caller, so caching
+	// them would let anyone grow the cache with random keys.
+	if key == nil {
+		return nil, nil
+	}
+
+	dbCache.Set(cacheKey, key)
+	return key, nil
+}
+
+func GetAPIKeys() ([]APIKey, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	keys, err := store.AllAPIKeys(ctx)
+	if err != nil {
+		logger.ErrorF("Failed to list API keys: %v", err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey revokes every key with the given name and reports whether
+// any was found.
+func DeleteAPIKey(name string) (bool, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	keys, err := store.DeleteAPIKeys(ctx, name)
+	if err != nil {
+		logger.ErrorF("Failed to delete API key %s: %v", name, err)
+		return false, err
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	for _, key := range keys {
+		dbCache.Delete("api_key_" + key.Hash)
+	}
+	return true, nil
+}
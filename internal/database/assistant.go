@@ -23,11 +23,11 @@ package database
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
-
-	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 var (
@@ -35,6 +35,15 @@ var (
 	usageMu        sync.RWMutex
 )
 
+// AssistantLoadFunc reports the live load score of a 1-based assistant
+// index, and false when it is down. When unset, chats are balanced on the
+// number of assigned chats only.
+var AssistantLoadFunc func(idx int) (float64, bool) // AssistantLoadFunc = core.Assistants.LoadScore
+
+// chatWeight is the score of one assigned chat. It is small so assigned
+// chats only break ties between assistants with the same live load.
+const chatWeight = 0.01
+
 // IMPORTANT: RebalanceAssistantIndexes must be called first before calling GetAssistantIndex
 
 func GetAssistantIndex(chatID int64, assistantCount int) (int, error) {
@@ -45,12 +54,7 @@ func GetAssistantIndex(chatID int64, assistantCount int) (int, error) {
 
 	settings, err := getChatSettings(chatID)
 	if err != nil {
-		logger.Error(
-			"Failed to get chat settings for chat " + strconv.FormatInt(
-				chatID,
-				10,
-			) + ": " + err.Error(),
-		)
+		logger.ErrorF("Failed to get chat settings chat_id=%d: %v", chatID, err)
 		return 0, err
 	}
 
@@ -64,19 +68,13 @@ func GetAssistantIndex(chatID int64, assistantCount int) (int, error) {
 	copy(countsCopy, assistantUsage)
 	usageMu.RUnlock()
 
-	newIndex := pickLeastUsedAssistant(countsCopy)
+	newIndex := pickAssistant(countsCopy, allAssistants(assistantCount))
 
-	logger.Debug(
-		"Assigning assistant index " + strconv.Itoa(newIndex) +
-			" to chat " + strconv.FormatInt(chatID, 10),
-	)
+	logger.DebugF("Assigning assistant=%d to chat_id=%d", newIndex, chatID)
 
 	settings.AssistantIndex = newIndex
 	if err := updateChatSettings(settings); err != nil {
-		logger.Error(
-			"Failed to update assistant index for chat " +
-				strconv.FormatInt(chatID, 10) + ": " + err.Error(),
-		)
+		logger.ErrorF("Failed to update assistant index chat_id=%d: %v", chatID, err)
 		return 0, err
 	}
 
@@ -98,35 +96,19 @@ func RebalanceAssistantIndexes(assistantCount int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	cursor, err := chatSettingsColl.Find(ctx, bson.M{})
+	all, err := store.AllChatSettings(ctx)
 	if err != nil {
 		logger.Error(
 			"Failed to fetch chat settings for rebalance: " + err.Error(),
 		)
 		return err
 	}
-	defer cursor.Close(ctx)
-
-	var all []*ChatSettings
-	original := make(map[int64]int)
 
-	for cursor.Next(ctx) {
-		var s ChatSettings
-		if err := cursor.Decode(&s); err != nil {
-			logger.Error(
-				"Failed to decode chat setting during rebalance: " + err.Error(),
-			)
-			return err
-		}
-		all = append(all, &s)
+	original := make(map[int64]int, len(all))
+	for _, s := range all {
 		original[s.ChatID] = s.AssistantIndex
 	}
 
-	if err := cursor.Err(); err != nil {
-		logger.Error("Rebalance cursor error: " + err.Error())
-		return err
-	}
-
 	total := len(all)
 	if total == 0 {
 		logger.Debug("Rebalance: no chats found")
@@ -138,14 +120,31 @@ func RebalanceAssistantIndexes(assistantCount int) error {
 		return nil
 	}
 
-	base := total / assistantCount
-	rem := total % assistantCount
+	// Assistants that are down get no chats; the least loaded ones get the
+	// remainder.
+	var up []int
+	scores := make(map[int]float64, assistantCount)
+	for _, idx := range allAssistants(assistantCount) {
+		if score, ok := assistantScore(nil, idx); ok {
+			up = append(up, idx)
+			scores[idx] = score
+		}
+	}
+	if len(up) == 0 {
+		up = allAssistants(assistantCount)
+	}
+	sort.SliceStable(up, func(i, j int) bool {
+		return scores[up[i]] < scores[up[j]]
+	})
+
+	base := total / len(up)
+	rem := total % len(up)
 
 	desired := make([]int, assistantCount+1)
-	for i := 1; i <= assistantCount; i++ {
-		desired[i] = base
-		if i <= rem {
-			desired[i]++
+	for i, idx := range up {
+		desired[idx] = base
+		if i < rem {
+			desired[idx]++
 		}
 	}
 
@@ -202,17 +201,15 @@ func RebalanceAssistantIndexes(assistantCount int) error {
 			continue
 		}
 
-		logger.Debug(
-			"Rebalance: updating chat " + strconv.FormatInt(s.ChatID, 10) +
-				" from index " + strconv.Itoa(oldIdx) +
-				" to " + strconv.Itoa(s.AssistantIndex),
+		logger.DebugF(
+			"Rebalance: moving chat_id=%d from assistant %d to assistant=%d",
+			s.ChatID,
+			oldIdx,
+			s.AssistantIndex,
 		)
 
 		if err := updateChatSettings(s); err != nil {
-			logger.Error(
-				"Rebalance: failed updating chat " +
-					strconv.FormatInt(s.ChatID, 10) + ": " + err.Error(),
-			)
+			logger.ErrorF("Rebalance: failed updating chat_id=%d: %v", s.ChatID, err)
 			return err
 		}
 		updated++
@@ -238,16 +235,136 @@ func RebalanceAssistantIndexes(assistantCount int) error {
 	return nil
 }
 
-func pickLeastUsedAssistant(counts []int64) int {
-	newIndex := 1
-	minCount := counts[1]
+// ReassignAssistant moves chatID to the least used assistant among
+// candidates (1-based indexes) and returns the new index. It is used when
+// the current assistant of the chat is down or banned there.
+func ReassignAssistant(chatID int64, candidates []int) (int, error) {
+	if len(candidates) == 0 {
+		return 0, fmt.Errorf("no candidate assistants")
+	}
+
+	settings, err := getChatSettings(chatID)
+	if err != nil {
+		logger.ErrorF("Failed to get chat settings chat_id=%d: %v", chatID, err)
+		return 0, err
+	}
+
+	usageMu.RLock()
+	newIndex := pickAssistant(assistantUsage, candidates)
+	usageMu.RUnlock()
+
+	oldIndex := settings.AssistantIndex
+	settings.AssistantIndex = newIndex
+	if err := updateChatSettings(settings); err != nil {
+		logger.ErrorF(
+			"Failed to update assistant index chat_id=%d: %v",
+			chatID,
+			err,
+		)
+		return 0, err
+	}
+
+	usageMu.Lock()
+	if oldIndex >= 1 && oldIndex < len(assistantUsage) &&
+		assistantUsage[oldIndex] > 0 {
+		assistantUsage[oldIndex]--
+	}
+	if newIndex < len(assistantUsage) {
+		assistantUsage[newIndex]++
+	}
+	usageMu.Unlock()
+
+	logger.InfoF(
+		"Reassigned chat_id=%d from assistant %d to assistant=%d",
+		chatID,
+		oldIndex,
+		newIndex,
+	)
+	return newIndex, nil
+}
+
+// RemoveAssistantIndex drops the 1-based assistant idx from the chat
+// assignments: its chats become unassigned and the chats of the assistants
+// after it shift down by one, matching the compacted assistant list. Call
+// RebalanceAssistantIndexes afterwards to hand the unassigned chats out.
+func RemoveAssistantIndex(idx int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	all, err := store.AllChatSettings(ctx)
+	if err != nil {
+		logger.ErrorF("Failed to fetch chat settings to remove assistant=%d: %v", idx, err)
+		return err
+	}
+
+	for _, s := range all {
+		switch {
+		case s.AssistantIndex == idx:
+			s.AssistantIndex = 0
+		case s.AssistantIndex > idx:
+			s.AssistantIndex--
+		default:
+			continue
+		}
+		if err := updateChatSettings(s); err != nil {
+			logger.ErrorF("Failed to update assistant index chat_id=%d: %v", s.ChatID, err)
+			return err
+		}
+	}
+
+	usageMu.Lock()
+	if idx >= 1 && idx < len(assistantUsage) {
+		assistantUsage = slices.Delete(assistantUsage, idx, idx+1)
+	}
+	usageMu.Unlock()
+
+	logger.InfoF("Removed assistant=%d from the chat assignments", idx)
+	return nil
+}
+
+// AssistantChatCounts returns how many chats are assigned to each
+// assistant; index 0 is unused.
+func AssistantChatCounts() []int64 {
+	usageMu.RLock()
+	defer usageMu.RUnlock()
+	return slices.Clone(assistantUsage)
+}
+
+func allAssistants(count int) []int {
+	all := make([]int, count)
+	for i := range all {
+		all[i] = i + 1
+	}
+	return all
+}
 
-	for i := 2; i < len(counts); i++ {
-		if counts[i] < minCount {
-			minCount = counts[i]
-			newIndex = i
+// pickAssistant returns the candidate with the lowest load score, skipping
+// assistants that are down unless all of them are.
+func pickAssistant(counts []int64, candidates []int) int {
+	best, bestScore := 0, 0.0
+	for _, idx := range candidates {
+		score, ok := assistantScore(counts, idx)
+		if !ok {
+			continue
 		}
+		if best == 0 || score < bestScore {
+			best, bestScore = idx, score
+		}
+	}
+	if best == 0 {
+		return candidates[0]
 	}
+	return best
+}
 
-	return newIndex
+func assistantScore(counts []int64, idx int) (float64, bool) {
+	var chats float64
+	if idx < len(counts) {
+		chats = float64(counts[idx])
+	}
+	if AssistantLoadFunc == nil {
+		return chats, true
+	}
+	load, ok := AssistantLoadFunc(idx)
+	return load + chats*chatWeight, ok
 }
@@ -0,0 +1,259 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// SessionSecret is the passphrase the stored assistant sessions are
+// encrypted with.
+var SessionSecret string // SessionSecret = config.SessionSecret
+
+// StoredAssistant is an assistant added with /addassistant. Session holds
+// the session string sealed with AES-GCM under a key derived from
+// SessionSecret and a random salt with argon2id, so a leaked database or
+// backup does not leak the account.
+type StoredAssistant struct {
+	UserID  int64     `bson:"user_id"`
+	Type    string    `bson:"type"`
+	Session string    `bson:"session"`
+	AddedBy int64     `bson:"added_by"`
+	AddedAt time.Time `bson:"added_at"`
+}
+
+// AssistantSession is a decrypted StoredAssistant.
+type AssistantSession struct {
+	UserID  int64
+	Type    string
+	Session string
+}
+
+// GetAssistantSessions returns the stored assistants with their sessions
+// decrypted. Sessions that cannot be decrypted, for example because
+// SESSION_SECRET changed, are logged and skipped. Sessions in the old
+// format are resealed.
+func GetAssistantSessions() ([]AssistantSession, error) {
+	state, err := getBotState()
+	if err != nil {
+		logger.ErrorF("Failed to get assistant sessions: %v", err)
+		return nil, err
+	}
+
+	out := make([]AssistantSession, 0, len(state.Assistants))
+	list := slices.Clone(state.Assistants)
+	resealed := false
+	for i, a := range list {
+		session, legacy, err := openSession(a.Session)
+		if err != nil {
+			logger.ErrorF("Failed to decrypt session of assistant %d: %v", a.UserID, err)
+			continue
+		}
+		if legacy {
+			if sealed, err := sealSession(session); err != nil {
+				logger.ErrorF("Failed to reseal session of assistant %d: %v", a.UserID, err)
+			} else {
+				list[i].Session = sealed
+				resealed = true
+			}
+		}
+		out = append(out, AssistantSession{
+			UserID:  a.UserID,
+			Type:    a.Type,
+			Session: session,
+		})
+	}
+
+	if resealed {
+		newState := *state
+		newState.Assistants = list
+		if err := updateBotState(&newState); err != nil {
+			logger.ErrorF("Failed to save resealed assistant sessions: %v", err)
+		}
+	}
+	return out, nil
+}
+
+// SaveAssistantSession encrypts and stores the session of userID,
+// replacing an earlier session of the same account.
+func SaveAssistantSession(userID int64, session, sessionType string, addedBy int64) error {
+	sealed, err := sealSession(session)
+	if err != nil {
+		logger.ErrorF("Failed to encrypt session of assistant %d: %v", userID, err)
+		return err
+	}
+
+	state, err := getBotState()
+	if err != nil {
+		logger.ErrorF("Failed to get assistant sessions: %v", err)
+		return err
+	}
+
+	list := slices.DeleteFunc(slices.Clone(state.Assistants), func(a StoredAssistant) bool {
+		return a.UserID == userID
+	})
+	list = append(list, StoredAssistant{
+		UserID:  userID,
+		Type:    sessionType,
+		Session: sealed,
+		AddedBy: addedBy,
+		AddedAt: time.Now(),
+	})
+
+	newState := *state
+	newState.Assistants = list
+	if err := updateBotState(&newState); err != nil {
+		logger.ErrorF("Failed to save session of assistant %d: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteAssistantSession removes the stored session of userID and reports
+// whether there was one. Assistants from STRING_SESSIONS have none.
+func DeleteAssistantSession(userID int64) (bool, error) {
+	state, err := getBotState()
+	if err != nil {
+		logger.ErrorF("Failed to get assistant sessions: %v", err)
+		return false, err
+	}
+
+	list := slices.DeleteFunc(slices.Clone(state.Assistants), func(a StoredAssistant) bool {
+		return a.UserID == userID
+	})
+	if len(list) == len(state.Assistants) {
+		return false, nil
+	}
+
+	newState := *state
+	newState.Assistants = list
+	if err := updateBotState(&newState); err != nil {
+		logger.ErrorF("Failed to delete session of assistant %d: %v", userID, err)
+		return false, err
+	}
+	return true, nil
+}
+
+// Sealed sessions are "v2:" followed by base64 of salt||nonce||ciphertext,
+// with the key derived from SessionSecret and the per-record salt by
+// argon2id. Sessions without a prefix were sealed under an unsalted
+// SHA-256 of the secret; they can still be opened and are resealed when
+// the sessions are loaded.
+const (
+	sealVersion = "v2:"
+	sealSaltLen = 16
+
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+func sessionCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sessionKey(salt []byte) ([]byte, error) {
+	if SessionSecret == "" {
+		return nil, errors.New("SESSION_SECRET is not set")
+	}
+	return argon2.IDKey([]byte(SessionSecret), salt, argonTime, argonMemory, argonThreads, 32), nil
+}
+
+// sealSession encrypts session under a fresh salt and nonce.
+func sealSession(session string) (string, error) {
+	salt := make([]byte, sealSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := sessionKey(salt)
+	if err != nil {
+		return "", err
+	}
+	aead, err := sessionCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	out := append(salt, nonce...)
+	out = aead.Seal(out, nonce, []byte(session), nil)
+	return sealVersion + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// openSession decrypts a sealed session and reports whether it uses the
+// old unsalted format.
+func openSession(sealed string) (string, bool, error) {
+	if SessionSecret == "" {
+		return "", false, errors.New("SESSION_SECRET is not set")
+	}
+
+	encoded, current := strings.CutPrefix(sealed, sealVersion)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false, err
+	}
+
+	var key []byte
+	if current {
+		if len(data) < sealSaltLen {
+			return "", false, fmt.Errorf("sealed session too short")
+		}
+		if key, err = sessionKey(data[:sealSaltLen]); err != nil {
+			return "", false, err
+		}
+		data = data[sealSaltLen:]
+	} else {
+		sum := sha256.Sum256([]byte(SessionSecret))
+		key = sum[:]
+	}
+
+	aead, err := sessionCipher(key)
+	if err != nil {
+		return "", false, err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", false, fmt.Errorf("sealed session too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", false, err
+	}
+	return string(plain), !current, nil
+}
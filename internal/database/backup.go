@@ -0,0 +1,402 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// backupFormat is bumped when the archive layout itself changes; document
+// layout changes are covered by SchemaVersion and the migrations.
+const (
+	backupFormat = 1
+
+	// maxBackupSize caps the decompressed archive read by ReadBackup.
+	maxBackupSize = 256 << 20
+)
+
+type RestoreMode int
+
+const (
+	// RestoreMerge upserts the backup on top of the current data: chats in
+	// the backup overwrite the same chats, everything else is kept, and the
+	// sudoers and served lists are unioned.
+	RestoreMerge RestoreMode = iota
+	// RestoreReplace makes the database an exact copy of the backup.
+	RestoreReplace
+)
+
+// Backup is a full export of the bot's data.
+type Backup struct {
+	SchemaVersion int
+	CreatedAt     time.Time
+	BotState      *BotState
+	Chats         []*ChatSettings
+	APIKeys       []APIKey
+	LocalePacks   []LocalePack
+}
+
+type BackupStats struct {
+	Chats       int
+	Sudoers     int
+	ServedUsers int
+	ServedChats int
+	AuthUsers   int
+	RTMPConfigs int
+	APIKeys     int
+	LocalePacks int
+}
+
+// backupFile is the JSON layout of an archive. Documents are stored as
+// relaxed extended JSON so their field names match the database.
+type backupFile struct {
+	Format        int               `json:"format"`
+	SchemaVersion int               `json:"schema_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	BotState      json.RawMessage   `json:"bot_state,omitempty"`
+	Chats         []json.RawMessage `json:"chat_settings"`
+	APIKeys       []json.RawMessage `json:"api_keys"`
+	LocalePacks   []json.RawMessage `json:"locale_packs,omitempty"`
+}
+
+// CreateBackup reads everything from the store.
+func CreateBackup(ctx context.Context) (*Backup, error) {
+	b := &Backup{CreatedAt: time.Now().UTC()}
+	var err error
+
+	if b.SchemaVersion, err = store.SchemaVersion(ctx); err != nil {
+		return nil, fmt.Errorf("read schema version: %w", err)
+	}
+	if b.BotState, err = store.GetBotState(ctx); err != nil {
+		return nil, fmt.Errorf("read bot state: %w", err)
+	}
+	if b.Chats, err = store.AllChatSettings(ctx); err != nil {
+		return nil, fmt.Errorf("read chat settings: %w", err)
+	}
+	if b.APIKeys, err = store.AllAPIKeys(ctx); err != nil {
+		return nil, fmt.Errorf("read api keys: %w", err)
+	}
+	if b.LocalePacks, err = store.AllLocalePacks(ctx); err != nil {
+		return nil, fmt.Errorf("read locale packs: %w", err)
+	}
+	return b, nil
+}
+
+// WriteBackup writes b as gzip compressed JSON.
+func WriteBackup(w io.Writer, b *Backup) error {
+	f := backupFile{
+		Format:        backupFormat,
+		SchemaVersion: b.SchemaVersion,
+		CreatedAt:     b.CreatedAt,
+		Chats:         make([]json.RawMessage, 0, len(b.Chats)),
+		APIKeys:       make([]json.RawMessage, 0, len(b.APIKeys)),
+	}
+
+	var err error
+	if b.BotState != nil {
+		if f.BotState, err = bson.MarshalExtJSON(b.BotState, false, false); err != nil {
+			return err
+		}
+	}
+	for _, cs := range b.Chats {
+		doc, err := bson.MarshalExtJSON(cs, false, false)
+		if err != nil {
+			return fmt.Errorf("encode chat %d: %w", cs.ChatID, err)
+		}
+		f.Chats = append(f.Chats, doc)
+	}
+	for i := range b.APIKeys {
+		doc, err := bson.MarshalExtJSON(&b.APIKeys[i], false, false)
+		if err != nil {
+			return err
+		}
+		f.APIKeys = append(f.APIKeys, doc)
+	}
+	for i := range b.LocalePacks {
+		doc, err := bson.MarshalExtJSON(&b.LocalePacks[i], false, false)
+		if err != nil {
+			return err
+		}
+		f.LocalePacks = append(f.LocalePacks, doc)
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(&f); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadBackup decodes and validates an archive written by WriteBackup.
+func ReadBackup(r io.Reader) (*Backup, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip archive: %w", err)
+	}
+	defer zr.Close()
+
+	var f backupFile
+	lr := &io.LimitedReader{R: zr, N: maxBackupSize + 1}
+	if err := json.NewDecoder(lr).Decode(&f); err != nil {
+		if lr.N <= 0 {
+			return nil, errors.New("archive is too large")
+		}
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	if f.Format != backupFormat {
+		return nil, fmt.Errorf("unsupported archive format %d", f.Format)
+	}
+	if f.SchemaVersion > SchemaVersion() {
+		return nil, fmt.Errorf(
+			"archive has schema v%d, this build supports up to v%d",
+			f.SchemaVersion,
+			SchemaVersion(),
+		)
+	}
+
+	b := &Backup{
+		SchemaVersion: f.SchemaVersion,
+		CreatedAt:     f.CreatedAt,
+		Chats:         make([]*ChatSettings, 0, len(f.Chats)),
+	}
+
+	if len(f.BotState) > 0 {
+		b.BotState = &BotState{}
+		if err := bson.UnmarshalExtJSON(f.BotState, false, b.BotState); err != nil {
+			return nil, fmt.Errorf("invalid bot state: %w", err)
+		}
+	}
+
+	seen := make(map[int64]bool, len(f.Chats))
+	for i, doc := range f.Chats {
+		var cs ChatSettings
+		if err := bson.UnmarshalExtJSON(doc, false, &cs); err != nil {
+			return nil, fmt.Errorf("invalid chat settings #%d: %w", i+1, err)
+		}
+		if cs.ChatID == 0 {
+			return nil, fmt.Errorf("chat settings #%d has no chat id", i+1)
+		}
+		if seen[cs.ChatID] {
+			return nil, fmt.Errorf("chat %d appears twice", cs.ChatID)
+		}
+		seen[cs.ChatID] = true
+		b.Chats = append(b.Chats, &cs)
+	}
+
+	for i, doc := range f.APIKeys {
+		var key APIKey
+		if err := bson.UnmarshalExtJSON(doc, false, &key); err != nil {
+			return nil, fmt.Errorf("invalid api key #%d: %w", i+1, err)
+		}
+		if key.Hash == "" {
+			return nil, fmt.Errorf("api key #%d has no hash", i+1)
+		}
+		b.APIKeys = append(b.APIKeys, key)
+	}
+
+	for i, doc := range f.LocalePacks {
+		var pack LocalePack
+		if err := bson.UnmarshalExtJSON(doc, false, &pack); err != nil {
+			return nil, fmt.Errorf("invalid locale pack #%d: %w", i+1, err)
+		}
+		if pack.Lang == "" {
+			return nil, fmt.Errorf("locale pack #%d has no language", i+1)
+		}
+		b.LocalePacks = append(b.LocalePacks, pack)
+	}
+
+	return b, nil
+}
+
+func (b *Backup) Stats() BackupStats {
+	s := BackupStats{
+		Chats:       len(b.Chats),
+		APIKeys:     len(b.APIKeys),
+		LocalePacks: len(b.LocalePacks),
+	}
+	if b.BotState != nil {
+		s.Sudoers = len(b.BotState.Sudoers)
+		s.ServedUsers = len(b.BotState.Served.Users)
+		s.ServedChats = len(b.BotState.Served.Chats)
+	}
+	for _, cs := range b.Chats {
+		s.AuthUsers += len(cs.AuthUsers)
+		if cs.RTMPConfig.RtmpURL != "" || cs.RTMPConfig.RtmpKey != "" {
+			s.RTMPConfigs++
+		}
+	}
+	return s
+}
+
+// RestoreBackup writes b into the store. Saves replace whole documents, so
+// in replace mode the chats and bot state match the archive exactly, zero
+// and empty fields included. Restoring an archive from an older schema
+// re-runs the migrations it is missing.
+func RestoreBackup(ctx context.Context, b *Backup, mode RestoreMode) error {
+	defer dbCache.Clear()
+
+	if mode == RestoreReplace {
+		if err := clearForRestore(ctx, b); err != nil {
+			return err
+		}
+	}
+
+	state := b.BotState
+	if mode == RestoreMerge {
+		current, err := store.GetBotState(ctx)
+		if err != nil {
+			return fmt.Errorf("read bot state: %w", err)
+		}
+		state = mergeBotState(current, b.BotState)
+	}
+	if state == nil {
+		s := *defaultBotState
+		state = &s
+	}
+	state.ID = "global"
+	if err := store.SaveBotState(ctx, state); err != nil {
+		return fmt.Errorf("write bot state: %w", err)
+	}
+
+	for _, cs := range b.Chats {
+		if err := store.SaveChatSettings(ctx, cs); err != nil {
+			return fmt.Errorf("write chat %d: %w", cs.ChatID, err)
+		}
+	}
+	for i := range b.APIKeys {
+		if err := store.SaveAPIKey(ctx, &b.APIKeys[i]); err != nil {
+			return fmt.Errorf("write api key %s: %w", b.APIKeys[i].Name, err)
+		}
+	}
+	for i := range b.LocalePacks {
+		if err := store.SaveLocalePack(ctx, &b.LocalePacks[i]); err != nil {
+			return fmt.Errorf("write locale pack %s: %w", b.LocalePacks[i].Lang, err)
+		}
+	}
+
+	current, err := store.SchemaVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if b.SchemaVersion < current {
+		if err := store.SetSchemaVersion(ctx, b.SchemaVersion); err != nil {
+			return fmt.Errorf("write schema version: %w", err)
+		}
+		return runMigrations()
+	}
+	return nil
+}
+
+// clearForRestore deletes the chats, API keys and locale packs that are not
+// in b.
+func clearForRestore(ctx context.Context, b *Backup) error {
+	keep := make(map[int64]bool, len(b.Chats))
+	for _, cs := range b.Chats {
+		keep[cs.ChatID] = true
+	}
+
+	chats, err := store.AllChatSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("read chat settings: %w", err)
+	}
+	for _, cs := range chats {
+		if keep[cs.ChatID] {
+			continue
+		}
+		if err := store.DeleteChatSettings(ctx, cs.ChatID); err != nil {
+			return fmt.Errorf("delete chat %d: %w", cs.ChatID, err)
+		}
+	}
+
+	keys, err := store.AllAPIKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("read api keys: %w", err)
+	}
+	for _, key := range keys {
+		if _, err := store.DeleteAPIKeys(ctx, key.Name); err != nil {
+			return fmt.Errorf("delete api key %s: %w", key.Name, err)
+		}
+	}
+
+	packs, err := store.AllLocalePacks(ctx)
+	if err != nil {
+		return fmt.Errorf("read locale packs: %w", err)
+	}
+	for _, pack := range packs {
+		if _, err := store.DeleteLocalePack(ctx, pack.Lang); err != nil {
+			return fmt.Errorf("delete locale pack %s: %w", pack.Lang, err)
+		}
+	}
+	return nil
+}
+
+// mergeBotState unions the id lists of both states (sudoers, served and
+// blacklist) and their stored assistants; flags such as maintenance and
+// autoleave keep their current value.
+func mergeBotState(current, backup *BotState) *BotState {
+	if backup == nil {
+		return current
+	}
+	if current == nil {
+		return backup
+	}
+
+	merged := *current
+	merged.Sudoers = unionIDs(current.Sudoers, backup.Sudoers)
+	merged.Served.Users = unionIDs(current.Served.Users, backup.Served.Users)
+	merged.Served.Chats = unionIDs(current.Served.Chats, backup.Served.Chats)
+	merged.Blacklist.Users = unionIDs(current.Blacklist.Users, backup.Blacklist.Users)
+	merged.Blacklist.Chats = unionIDs(current.Blacklist.Chats, backup.Blacklist.Chats)
+	merged.Assistants = slices.Clone(current.Assistants)
+	for _, a := range backup.Assistants {
+		if !slices.ContainsFunc(merged.Assistants, func(c StoredAssistant) bool {
+			return c.UserID == a.UserID
+		}) {
+			merged.Assistants = append(merged.Assistants, a)
+		}
+	}
+	return &merged
+}
+
+func unionIDs(a, b []int64) []int64 {
+	out := slices.Clone(a)
+	seen := make(map[int64]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import "slices"
+
+const blacklistCacheKey = "blacklist_set"
+
+// blacklistSet is the lookup form of BotState.Blacklist. It is rebuilt
+// lazily and dropped from the cache on every bot state update.
+type blacklistSet struct {
+	users map[int64]struct{}
+	chats map[int64]struct{}
+}
+
+func getBlacklistSet() (*blacklistSet, error) {
+	if cached, found := dbCache.Get(blacklistCacheKey); found {
+		if set, ok := cached.(*blacklistSet); ok {
+			return set, nil
+		}
+	}
+
+	state, err := getBotState()
+	if err != nil {
+		return nil, err
+	}
+
+	set := &blacklistSet{
+		users: make(map[int64]struct{}, len(state.Blacklist.Users)),
+		chats: make(map[int64]struct{}, len(state.Blacklist.Chats)),
+	}
+	for _, id := range state.Blacklist.Users {
+		set.users[id] = struct{}{}
+	}
+	for _, id := range state.Blacklist.Chats {
+		set.chats[id] = struct{}{}
+	}
+
+	dbCache.Set(blacklistCacheKey, set)
+	return set, nil
+}
+
+// IsBlacklistedUser reports whether the user is globally blacklisted.
+// Lookup errors are logged and treated as not blacklisted.
+func IsBlacklistedUser(id int64) bool {
+	set, err := getBlacklistSet()
+	if err != nil {
+		logger.ErrorF("Failed to get blacklist: %v", err)
+		return false
+	}
+	_, ok := set.users[id]
+	return ok
+}
+
+// IsBlacklistedChat reports whether the chat is globally blacklisted.
+// Lookup errors are logged and treated as not blacklisted.
+func IsBlacklistedChat(id int64) bool {
+	set, err := getBlacklistSet()
+	if err != nil {
+		logger.ErrorF("Failed to get blacklist: %v", err)
+		return false
+	}
+	_, ok := set.chats[id]
+	return ok
+}
+
+// GetBlacklist returns the blacklisted users and chats.
+func GetBlacklist() (UsersChats, error) {
+	state, err := getBotState()
+	if err != nil {
+		logger.ErrorF("Failed to get blacklist: %v", err)
+		return UsersChats{}, err
+	}
+	return UsersChats{
+		Users: slices.Clone(state.Blacklist.Users),
+		Chats: slices.Clone(state.Blacklist.Chats),
+	}, nil
+}
+
+// BlacklistUser adds the user to the blacklist. It reports false if the
+// user was already blacklisted.
+func BlacklistUser(id int64) (bool, error) {
+	return updateBlacklist(func(bl *UsersChats) bool {
+		return addID(&bl.Users, id)
+	})
+}
+
+// UnblacklistUser removes the user from the blacklist. It reports false
+// if the user was not blacklisted.
+func UnblacklistUser(id int64) (bool, error) {
+	return updateBlacklist(func(bl *UsersChats) bool {
+		return removeID(&bl.Users, id)
+	})
+}
+
+// BlacklistChat adds the chat to the blacklist. It reports false if the
+// chat was already blacklisted.
+func BlacklistChat(id int64) (bool, error) {
+	return updateBlacklist(func(bl *UsersChats) bool {
+		return addID(&bl.Chats, id)
+	})
+}
+
+// UnblacklistChat removes the chat from the blacklist. It reports false
+// if the chat was not blacklisted.
+func UnblacklistChat(id int64) (bool, error) {
+	return updateBlacklist(func(bl *UsersChats) bool {
+		return removeID(&bl.Chats, id)
+	})
+}
+
+func updateBlacklist(mutate func(*UsersChats) bool) (bool, error) {
+	state, err := getBotState()
+	if err != nil {
+		logger.ErrorF("Failed to get blacklist: %v", err)
+		return false, err
+	}
+
+	if !mutate(&state.Blacklist) {
+		return false, nil
+	}
+
+	if err := updateBotState(state); err != nil {
+		logger.ErrorF("Failed to update blacklist: %v", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func addID(ids *[]int64, id int64) bool {
+	if slices.Contains(*ids, id) {
+		return false
+	}
+	*ids = append(*ids, id)
+	return true
+}
+
+func removeID(ids *[]int64, id int64) bool {
+	i := slices.Index(*ids, id)
+	if i < 0 {
+		return false
+	}
+	*ids = slices.Delete(slices.Clone(*ids), i, i+1)
+	return true
+}
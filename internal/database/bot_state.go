@@ -20,12 +20,6 @@
 */
 package database
 
-import (
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
-)
-
 // TODO: reflect deepequal checked removed so handle caching of same opt in high-level
 
 type UsersChats struct {
@@ -44,6 +38,10 @@ type BotState struct {
 	AutoLeave     bool        `bson:"autoleave"`
 	LoggerEnabled bool        `bson:"logger"`
 	Maintenance   Maintenance `bson:"maint,omitempty"`
+	Blacklist     UsersChats  `bson:"blacklist"`
+	// Assistants added at runtime with /addassistant, see
+	// assistant_sessions.go.
+	Assistants []StoredAssistant `bson:"assistants,omitempty"`
 }
 
 const cacheKey = "bot_state"
@@ -52,6 +50,7 @@ var defaultBotState = &BotState{
 	ID:            "global",
 	Served:        UsersChats{Users: []int64{}, Chats: []int64{}},
 	Sudoers:       []int64{},
+	Blacklist:     UsersChats{Users: []int64{}, Chats: []int64{}},
 	LoggerEnabled: true,
 }
 
@@ -64,36 +63,30 @@ func getBotState() (*BotState, error) {
 	ctx, cancel := mongoCtx()
 	defer cancel()
 
-	var state BotState
-	err := settingsColl.FindOne(ctx, bson.M{"_id": "global"}).Decode(&state)
-	if err == mongo.ErrNoDocuments {
-		dbCache.Set(cacheKey, defaultBotState)
-		return defaultBotState, nil
-	} else if err != nil {
+	state, err := store.GetBotState(ctx)
+	if err != nil {
 		logger.ErrorF("Failed to get bot state: %v", err)
 		return nil, err
 	}
+	if state == nil {
+		dbCache.Set(cacheKey, defaultBotState)
+		return defaultBotState, nil
+	}
 
-	dbCache.Set(cacheKey, &state)
-	return &state, nil
+	dbCache.Set(cacheKey, state)
+	return state, nil
 }
 
 func updateBotState(newState *BotState) error {
 	ctx, cancel := mongoCtx()
 	defer cancel()
 
-	opts := options.UpdateOne().SetUpsert(true)
-	_, err := settingsColl.UpdateOne(
-		ctx,
-		bson.M{"_id": "global"},
-		bson.M{"$set": newState},
-		opts,
-	)
-	if err != nil {
+	if err := store.SaveBotState(ctx, newState); err != nil {
 		logger.ErrorF("Failed to update bot state: %v", err)
 		return err
 	}
 
 	dbCache.Set(cacheKey, newState)
+	dbCache.Delete(blacklistCacheKey)
 	return nil
 }
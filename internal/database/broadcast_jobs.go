@@ -0,0 +1,177 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	BroadcastScheduled = "scheduled"
+	BroadcastRunning   = "running"
+	BroadcastDone      = "done"
+	BroadcastCancelled = "cancelled"
+)
+
+// MaxBroadcastFailures caps the failures kept per job; FailureCounts still
+// counts every one of them.
+const MaxBroadcastFailures = 1000
+
+// BroadcastSegment selects who a broadcast goes to. The zero value is every
+// served chat and user.
+type BroadcastSegment struct {
+	NoChats       bool   `bson:"no_chats,omitempty"`
+	NoUsers       bool   `bson:"no_users,omitempty"`
+	Lang          string `bson:"lang,omitempty"`
+	ActiveDays    int    `bson:"active_days,omitempty"`
+	WithAssistant bool   `bson:"with_assistant,omitempty"`
+}
+
+type BroadcastFailure struct {
+	ID     int64  `bson:"id"`
+	Reason string `bson:"reason"`
+}
+
+// BroadcastJob is a broadcast with its progress. Targets are sent to in
+// ascending id order, so Cursor, the last target handled, is all a resumed
+// job needs to skip what was already sent.
+type BroadcastJob struct {
+	ID        string    `bson:"_id"`
+	CreatedBy int64     `bson:"created_by"`
+	CreatedAt time.Time `bson:"created_at"`
+	RunAt     time.Time `bson:"run_at"`
+	Status    string    `bson:"status"`
+
+	// Either Text or the message to forward
+	Text      string `bson:"text,omitempty"`
+	FromChat  int64  `bson:"from_chat,omitempty"`
+	MessageID int32  `bson:"message_id,omitempty"`
+
+	Copy    bool             `bson:"copy,omitempty"`
+	Pin     bool             `bson:"pin,omitempty"`
+	PinLoud bool             `bson:"pin_loud,omitempty"`
+	Delay   float64          `bson:"delay"`
+	Limit   int              `bson:"limit,omitempty"`
+	Segment BroadcastSegment `bson:"segment"`
+
+	// Where the progress message lives
+	ReportChat  int64 `bson:"report_chat"`
+	ReportMsgID int32 `bson:"report_msg_id,omitempty"`
+
+	StartedAt     time.Time          `bson:"started_at,omitempty"`
+	FinishedAt    time.Time          `bson:"finished_at,omitempty"`
+	TotalChats    int                `bson:"total_chats"`
+	TotalUsers    int                `bson:"total_users"`
+	DoneChats     int                `bson:"done_chats"`
+	DoneUsers     int                `bson:"done_users"`
+	Skipped       int                `bson:"skipped"`
+	Pruned        int                `bson:"pruned,omitempty"`
+	Cursor        int64              `bson:"cursor"`
+	Failures      []BroadcastFailure `bson:"failures,omitempty"`
+	FailureCounts map[string]int     `bson:"failure_counts,omitempty"`
+}
+
+// Handled is how many targets the job is done with, sent or not.
+func (j *BroadcastJob) Handled() int {
+	return j.DoneChats + j.DoneUsers + j.Skipped
+}
+
+// FailedCount is how many sends failed.
+func (j *BroadcastJob) FailedCount() int {
+	n := 0
+	for _, c := range j.FailureCounts {
+		n += c
+	}
+	return n
+}
+
+func (j *BroadcastJob) AddFailure(id int64, reason string) {
+	if len(j.Failures) < MaxBroadcastFailures {
+		j.Failures = append(j.Failures, BroadcastFailure{ID: id, Reason: reason})
+	}
+	if j.FailureCounts == nil {
+		j.FailureCounts = make(map[string]int)
+	}
+	j.FailureCounts[reason]++
+}
+
+func SaveBroadcastJob(j *BroadcastJob) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	if err := store.SaveBroadcastJob(ctx, j); err != nil {
+		logger.ErrorF("Failed to save broadcast %s: %v", j.ID, err)
+		return err
+	}
+	return nil
+}
+
+// GetBroadcastJob returns the job with the given id, or nil if there is
+// none.
+func GetBroadcastJob(id string) (*BroadcastJob, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	return store.GetBroadcastJob(ctx, id)
+}
+
+// GetBroadcastJobs returns every stored job, newest first.
+func GetBroadcastJobs() ([]*BroadcastJob, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	jobs, err := store.AllBroadcastJobs(ctx)
+	if err != nil {
+		logger.ErrorF("Failed to list broadcasts: %v", err)
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].CreatedAt.After(jobs[k].CreatedAt)
+	})
+	return jobs, nil
+}
+
+func DeleteBroadcastJob(id string) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	return store.DeleteBroadcastJob(ctx, id)
+}
+
+// GetChatLanguages returns the language of every chat that picked one;
+// chats missing from the map use DefaultLang.
+func GetChatLanguages() (map[int64]string, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	all, err := store.AllChatSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	langs := make(map[int64]string)
+	for _, cs := range all {
+		if cs.Language != "" {
+			langs[cs.ChatID] = cs.Language
+		}
+	}
+	return langs, nil
+}
@@ -0,0 +1,94 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import state "main/internal/core/models"
+
+const (
+	PlayModeEveryone = ""
+	PlayModeAdmins   = "admins"
+)
+
+// ChatPrefs holds the options of the /settings panel. The zero value is the
+// default behaviour, so chats that never opened the panel store nothing.
+type ChatPrefs struct {
+	// PlayModeAdmins limits the play commands to admins and auth users.
+	PlayMode string `bson:"play_mode,omitempty"`
+	// Limits in tracks and seconds; 0 uses the global QUEUE_LIMIT and
+	// DURATION_LIMIT, which also cap these.
+	QueueLimit     int             `bson:"queue_limit,omitempty"`
+	DurationLimit  int             `bson:"duration_limit,omitempty"`
+	VideoDisabled  bool            `bson:"video_disabled,omitempty"`
+	VideoMode      state.VideoMode `bson:"video_mode,omitempty"`
+	SearchPlatform string          `bson:"search_platform,omitempty"`
+	AutoDeleteCmds bool            `bson:"auto_delete_cmds,omitempty"`
+	// KeepNowPlaying keeps old "now playing" messages instead of deleting
+	// them when the next track starts.
+	KeepNowPlaying bool `bson:"keep_np,omitempty"`
+	// KeepPlayingAlone keeps streaming into a voice chat nobody listens to
+	// instead of pausing and leaving after IDLE_LEAVE_TIMEOUT.
+	KeepPlayingAlone bool `bson:"keep_playing_alone,omitempty"`
+}
+
+func GetChatPrefs(chatID int64) (ChatPrefs, error) {
+	settings, err := getChatSettings(chatID)
+	if err != nil {
+		return ChatPrefs{}, err
+	}
+	return settings.Prefs, nil
+}
+
+func SetChatPrefs(chatID int64, prefs ChatPrefs) error {
+	settings, err := getChatSettings(chatID)
+	if err != nil {
+		return err
+	}
+	if settings.Prefs == prefs {
+		return nil
+	}
+	settings.Prefs = prefs
+	return updateChatSettings(settings)
+}
+
+// CleanNowPlaying reports whether old "now playing" messages of the chat
+// should be deleted.
+func CleanNowPlaying(chatID int64) bool {
+	prefs, _ := GetChatPrefs(chatID)
+	return !prefs.KeepNowPlaying
+}
+
+// MaxQueue returns the chat's queue limit; chats can only lower the global
+// limit.
+func (p ChatPrefs) MaxQueue(global int) int {
+	if p.QueueLimit > 0 && p.QueueLimit < global {
+		return p.QueueLimit
+	}
+	return global
+}
+
+// MaxDuration returns the chat's track duration limit in seconds; chats can
+// only lower the global limit.
+func (p ChatPrefs) MaxDuration(global int) int {
+	if p.DurationLimit > 0 && p.DurationLimit < global {
+		return p.DurationLimit
+	}
+	return global
+}
@@ -20,13 +20,7 @@
 */
 package database
 
-import (
-	"strconv"
-
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
-)
+import "strconv"
 
 // TODO: reflect deepequal checked removed so handle caching of same opt in high-level
 type RTMPConfig struct {
@@ -34,12 +28,15 @@ type RTMPConfig struct {
 	RtmpKey string `bson:"rtmp_key"`
 }
 type ChatSettings struct {
-	ChatID         int64      `bson:"_id"`
-	CPlayID        int64      `bson:"cplay_id"`
-	AuthUsers      []int64    `bson:"auth_users"`
-	Language       string     `bson:"language"`
-	RTMPConfig     RTMPConfig `bson:"rtmp_config"`
-	AssistantIndex int        `bson:"ass_index,omitempty"`
+	ChatID         int64         `bson:"_id"`
+	CPlayID        int64         `bson:"cplay_id"`
+	AuthUsers      []int64       `bson:"auth_users"`
+	Language       string        `bson:"language"`
+	RTMPConfig     RTMPConfig    `bson:"rtmp_config"`
+	AssistantIndex int           `bson:"ass_index,omitempty"`
+	RadioToken     string        `bson:"radio_token,omitempty"`
+	Prefs          ChatPrefs     `bson:"prefs,omitempty"`
+	Filter         ContentFilter `bson:"filter,omitempty"`
 }
 
 func defaultChatSettings(chatID int64) *ChatSettings {
@@ -60,19 +57,18 @@ func getChatSettings(chatID int64) (*ChatSettings, error) {
 		}
 	}
 
-	var settings ChatSettings
-	err := chatSettingsColl.FindOne(ctx, bson.M{"_id": chatID}).
-		Decode(&settings)
-	if err == mongo.ErrNoDocuments {
+	settings, err := store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		logger.ErrorF("Failed to get chat settings chat_id=%d: %v", chatID, err)
+		return nil, err
+	}
+	if settings == nil {
 		def := defaultChatSettings(chatID)
 		dbCache.Set(cacheKey, def)
 		return def, nil
-	} else if err != nil {
-		logger.Error("Failed to get chat settings for chat " + strconv.FormatInt(chatID, 10) + " :" + err.Error())
-		return nil, err
 	}
 
-	dbCache.Set(cacheKey, &settings)
+	dbCache.Set(cacheKey, settings)
 
 	// Proactively cache the cplayID -> chatID mapping
 	if settings.CPlayID != 0 {
@@ -80,7 +76,7 @@ func getChatSettings(chatID int64) (*ChatSettings, error) {
 		dbCache.Set(cplayCacheKey, settings.ChatID)
 	}
 
-	return &settings, nil
+	return settings, nil
 }
 
 func updateChatSettings(newSettings *ChatSettings) error {
@@ -88,19 +84,11 @@ func updateChatSettings(newSettings *ChatSettings) error {
 	ctx, cancel := mongoCtx()
 	defer cancel()
 
-	opts := options.UpdateOne().SetUpsert(true)
-	_, err := chatSettingsColl.UpdateOne(
-		ctx,
-		bson.M{"_id": newSettings.ChatID},
-		bson.M{"$set": newSettings},
-		opts,
-	)
-	if err != nil {
-		logger.Error(
-			"Failed to update chat settings for chat " + strconv.FormatInt(
-				newSettings.ChatID,
-				10,
-			) + " :" + err.Error(),
+	if err := store.SaveChatSettings(ctx, newSettings); err != nil {
+		logger.ErrorF(
+			"Failed to update chat settings chat_id=%d: %v",
+			newSettings.ChatID,
+			err,
 		)
 		return err
 	}
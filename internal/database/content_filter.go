@@ -0,0 +1,168 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	state "main/internal/core/models"
+	"main/internal/utils"
+)
+
+// Kinds of /filter rules, also used in the locale keys filter_rule_<kind>.
+const (
+	FilterKeyword    = "keyword"
+	FilterRegex      = "regex"
+	FilterPlatform   = "platform"
+	FilterChannel    = "channel"
+	FilterResolution = "resolution"
+)
+
+var filterRegexCache = utils.NewCache[string, *regexp.Regexp](1 * time.Hour)
+
+// ContentFilter holds a chat's /filter rules. Tracks matching any rule are
+// refused before they are downloaded.
+type ContentFilter struct {
+	// Keywords are matched case-insensitively against track titles.
+	Keywords []string `bson:"keywords,omitempty"`
+	// Patterns are regular expressions matched against track titles.
+	Patterns []string `bson:"patterns,omitempty"`
+	// Platforms are blocked track sources (state.PlatformName values).
+	Platforms []string `bson:"platforms,omitempty"`
+	// Channels are blocked YouTube channel ids, handles or names.
+	Channels []string `bson:"channels,omitempty"`
+	// MaxResolution caps the video height in pixels, 0 for no cap.
+	MaxResolution int `bson:"max_resolution,omitempty"`
+}
+
+// IsEmpty reports whether the filter has no rules.
+func (f ContentFilter) IsEmpty() bool {
+	return len(f.Keywords) == 0 && len(f.Patterns) == 0 &&
+		len(f.Platforms) == 0 && len(f.Channels) == 0 &&
+		f.MaxResolution == 0
+}
+
+// GetContentFilter returns a copy of the chat's filter rules.
+func GetContentFilter(chatID int64) (ContentFilter, error) {
+	settings, err := getChatSettings(chatID)
+	if err != nil {
+		return ContentFilter{}, err
+	}
+	f := settings.Filter
+	f.Keywords = slices.Clone(f.Keywords)
+	f.Patterns = slices.Clone(f.Patterns)
+	f.Platforms = slices.Clone(f.Platforms)
+	f.Channels = slices.Clone(f.Channels)
+	return f, nil
+}
+
+// SetContentFilter replaces the chat's filter rules.
+func SetContentFilter(chatID int64, f ContentFilter) error {
+	settings, err := getChatSettings(chatID)
+	if err != nil {
+		return err
+	}
+	settings.Filter = f
+	return updateChatSettings(settings)
+}
+
+// Match returns the kind and value of the first rule that blocks the
+// track, or empty strings if the track is allowed.
+func (f ContentFilter) Match(t *state.Track) (string, string) {
+	title := strings.ToLower(t.Title)
+	for _, kw := range f.Keywords {
+		if strings.Contains(title, strings.ToLower(kw)) {
+			return FilterKeyword, kw
+		}
+	}
+
+	for _, p := range f.Patterns {
+		re, ok := filterRegexCache.Get(p)
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(p); err != nil {
+				logger.WarnF("Skipping invalid filter pattern %q: %v", p, err)
+				continue
+			}
+			filterRegexCache.Set(p, re)
+		}
+		if re.MatchString(t.Title) {
+			return FilterRegex, p
+		}
+	}
+
+	for _, p := range f.Platforms {
+		if strings.EqualFold(string(t.Source), p) {
+			return FilterPlatform, p
+		}
+	}
+
+	for _, c := range f.Channels {
+		if (t.ChannelID != "" && strings.EqualFold(t.ChannelID, c)) ||
+			(t.Channel != "" && strings.EqualFold(t.Channel, c)) {
+			return FilterChannel, c
+		}
+	}
+
+	return "", ""
+}
+
+// BlockedTrack is a track refused by a chat's /filter rules.
+type BlockedTrack struct {
+	Track *state.Track
+	Rule  string // one of the Filter* kinds
+	Value string // the rule value that matched
+}
+
+// FilterTracks drops the tracks blocked by the chat's /filter rules. Video
+// tracks that pass are copied with the chat's resolution cap and video
+// mode applied. If the rules cannot be loaded the tracks pass unfiltered.
+func FilterTracks(
+	chatID int64,
+	prefs ChatPrefs,
+	tracks []*state.Track,
+) ([]*state.Track, []BlockedTrack) {
+	filter, err := GetContentFilter(chatID)
+	if err != nil {
+		logger.ErrorF("Failed to get content filter chat_id=%d: %v", chatID, err)
+	}
+
+	var allowed []*state.Track
+	var blocked []BlockedTrack
+	for _, track := range tracks {
+		if kind, value := filter.Match(track); kind != "" {
+			blocked = append(blocked, BlockedTrack{Track: track, Rule: kind, Value: value})
+			continue
+		}
+
+		if track.Video && (filter.MaxResolution > 0 || prefs.VideoMode != state.VideoCamera) {
+			capped := *track
+			capped.MaxHeight = filter.MaxResolution
+			capped.VideoMode = prefs.VideoMode
+			track = &capped
+		}
+		allowed = append(allowed, track)
+	}
+	return allowed, blocked
+}
@@ -20,12 +20,7 @@
 */
 package database
 
-import (
-	"fmt"
-
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-)
+import "fmt"
 
 func GetCPlayID(chatID int64) (int64, error) {
 	settings, err := getChatSettings(chatID)
@@ -71,15 +66,13 @@ func GetChatIDFromCPlayID(cplayID int64) (int64, error) {
 	ctx, cancel := mongoCtx()
 	defer cancel()
 
-	var settings ChatSettings
-	err := chatSettingsColl.FindOne(ctx, bson.M{"cplay_id": cplayID}).
-		Decode(&settings)
+	settings, err := store.FindChatByCPlayID(ctx, cplayID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return 0, fmt.Errorf("no chat found with cplayID %d", cplayID)
-		}
 		return 0, err
 	}
+	if settings == nil {
+		return 0, fmt.Errorf("no chat found with cplayID %d", cplayID)
+	}
 
 	dbCache.Set(cacheKey, settings.ChatID)
 	return settings.ChatID, nil
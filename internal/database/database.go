@@ -3,50 +3,40 @@ package database
 import (
 	"time"
 
-	"github.com/Laky-64/gologging"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
-
+	"main/internal/config"
 	"main/internal/utils"
 )
 
 var (
-	client           *mongo.Client
-	database         *mongo.Database
-	settingsColl     *mongo.Collection
-	chatSettingsColl *mongo.Collection
-
-	// 🔹 المتغير العام المطلوب
-	MongoDB *mongo.Database
+	store Store
 
-	logger  = gologging.GetLogger("Database")
+	logger  = config.GetLogger("Database")
 	dbCache = utils.NewCache[string, any](60 * time.Minute)
 )
 
-func Init(mongoURL string) func() {
+// Init opens the configured storage backend. dsn is the MongoDB URI for the
+// mongo backend and the database file path for bolt.
+func Init(backend, dsn string) func() {
 	var err error
-	logger.Debug("Initializing MongoDB...")
-	client, err = mongo.Connect(options.Client().ApplyURI(mongoURL))
+	logger.DebugF("Opening %s database...", backend)
+	store, err = Open(backend, dsn)
 	if err != nil {
-		logger.Fatal("Failed to connect to MongoDB: %v", err)
+		logger.FatalF("Failed to open %s database: %v", backend, err)
 	}
 
-	logger.Debug("Successfully connected to MongoDB.")
+	logger.DebugF("Successfully opened %s database.", backend)
 
-	database = client.Database("YukkiMusic")
-	MongoDB = database // 🔹 هنا نربط المتغير العام
-	settingsColl = database.Collection("bot_settings")
-	chatSettingsColl = database.Collection("chat_settings")
-
-	go migrateData(mongoURL)
+	if err := runMigrations(); err != nil {
+		logger.FatalF("Database migration failed: %v", err)
+	}
 
 	return func() {
 		ctx, cancel := mongoCtx()
 		defer cancel()
-		if err := client.Disconnect(ctx); err != nil {
-			logger.Error("Error while disconnecting MongoDB: %v", err)
+		if err := store.Close(ctx); err != nil {
+			logger.ErrorF("Error while closing database: %v", err)
 		} else {
-			logger.Info("MongoDB disconnected successfully")
+			logger.Info("Database closed successfully")
 		}
 	}
 }
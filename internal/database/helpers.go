@@ -22,9 +22,18 @@ package database
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
 func mongoCtx() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 5*time.Second)
 }
+
+// Ping checks that the storage backend is reachable.
+func Ping(ctx context.Context) error {
+	if store == nil {
+		return errors.New("database is not initialized")
+	}
+	return store.Ping(ctx)
+}
@@ -19,12 +19,14 @@
  */
 package database
 
-import "main/internal/config"
+// DefaultLang is returned for chats without a language; main sets it from
+// the config so the package does not depend on it.
+var DefaultLang = "en"
 
 func GetChatLanguage(chatID int64) (string, error) {
 	settings, err := getChatSettings(chatID)
 	if err != nil || settings.Language == "" {
-		return config.DefaultLang, err
+		return DefaultLang, err
 	}
 	return settings.Language, nil
 }
@@ -0,0 +1,177 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// ListenSample is one sampling tick of a playing room: how many listeners
+// were in the voice chat and how long the tick covered.
+type ListenSample struct {
+	ChatID    int64
+	TrackID   string
+	Title     string
+	Source    string
+	Listeners int
+	Interval  time.Duration
+	At        time.Time
+}
+
+// listenerMinutes weighs the tick by its audience, so ten people
+// listening for a minute count as ten minutes.
+func (ls *ListenSample) listenerMinutes() float64 {
+	return float64(ls.Listeners) * ls.Interval.Minutes()
+}
+
+// TrackListens holds the audience of one track in one chat across all the
+// times it was played there.
+type TrackListens struct {
+	ID          string    `bson:"_id"`
+	ChatID      int64     `bson:"chat_id"`
+	TrackID     string    `bson:"track_id"`
+	Title       string    `bson:"title"`
+	Source      string    `bson:"source"`
+	Peak        int       `bson:"peak"`
+	Samples     int64     `bson:"samples"`
+	ListenerSum int64     `bson:"listener_sum"`
+	LastAt      time.Time `bson:"last_at"`
+}
+
+// Average is the mean number of listeners over every sample of the track.
+func (t *TrackListens) Average() float64 {
+	if t.Samples == 0 {
+		return 0
+	}
+	return float64(t.ListenerSum) / float64(t.Samples)
+}
+
+func (t *TrackListens) add(ls *ListenSample) {
+	t.ID = trackListensID(ls.ChatID, ls.TrackID)
+	t.ChatID = ls.ChatID
+	t.TrackID = ls.TrackID
+	t.Title = ls.Title
+	t.Source = ls.Source
+	t.Peak = max(t.Peak, ls.Listeners)
+	t.Samples++
+	t.ListenerSum += int64(ls.Listeners)
+	t.LastAt = ls.At.UTC()
+}
+
+// ListenDay is the listening time of one chat on one UTC day. Hours splits
+// the minutes by UTC hour, keyed "00" to "23".
+type ListenDay struct {
+	ID      string             `bson:"_id"`
+	ChatID  int64              `bson:"chat_id"`
+	Day     string             `bson:"day"`
+	Minutes float64            `bson:"minutes"`
+	Peak    int                `bson:"peak"`
+	Hours   map[string]float64 `bson:"hours"`
+}
+
+func (d *ListenDay) add(ls *ListenSample) {
+	at := ls.At.UTC()
+	d.ID = listenDayID(ls.ChatID, ListenDayKey(at))
+	d.ChatID = ls.ChatID
+	d.Day = ListenDayKey(at)
+	d.Minutes += ls.listenerMinutes()
+	d.Peak = max(d.Peak, ls.Listeners)
+	if d.Hours == nil {
+		d.Hours = make(map[string]float64)
+	}
+	d.Hours[listenHourKey(at)] += ls.listenerMinutes()
+}
+
+// ListenTotals aggregates the listening days of every chat.
+type ListenTotals struct {
+	Minutes float64 `bson:"minutes"`
+	Peak    int     `bson:"peak"`
+	Chats   int     `bson:"chats"`
+}
+
+// ListenDayKey is the day a sample taken at t is counted in.
+func ListenDayKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func listenHourKey(t time.Time) string {
+	return fmt.Sprintf("%02d", t.UTC().Hour())
+}
+
+func trackListensID(chatID int64, trackID string) string {
+	return fmt.Sprintf("%d:%s", chatID, trackID)
+}
+
+func listenDayID(chatID int64, day string) string {
+	return fmt.Sprintf("%d:%s", chatID, day)
+}
+
+func AddListenSample(ls *ListenSample) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	if err := store.AddListenSample(ctx, ls); err != nil {
+		logger.ErrorF("Failed to save listen sample chat_id=%d: %v", ls.ChatID, err)
+		return err
+	}
+	return nil
+}
+
+// GetTopTrackListens returns the tracks of chatID with the most listener
+// time, most listened first.
+func GetTopTrackListens(chatID int64, limit int) ([]TrackListens, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	tracks, err := store.TopTrackListens(ctx, chatID, limit)
+	if err != nil {
+		logger.ErrorF("Failed to get top tracks chat_id=%d: %v", chatID, err)
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// GetListenDays returns the listening days of chatID from since onwards.
+func GetListenDays(chatID int64, since time.Time) ([]ListenDay, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	days, err := store.ListenDays(ctx, chatID, ListenDayKey(since))
+	if err != nil {
+		logger.ErrorF("Failed to get listen days chat_id=%d: %v", chatID, err)
+		return nil, err
+	}
+	return days, nil
+}
+
+// GetListenTotals sums the listening days of all chats from since onwards.
+func GetListenTotals(since time.Time) (*ListenTotals, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	totals, err := store.ListenTotals(ctx, ListenDayKey(since))
+	if err != nil {
+		logger.ErrorF("Failed to get listen totals: %v", err)
+		return nil, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,69 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import "time"
+
+// LocalePack is a translation uploaded at runtime with /langpack. Data is
+// the raw YAML so the pack can be re-parsed by the locales package on every
+// start.
+type LocalePack struct {
+	Lang      string    `bson:"_id"`
+	Data      string    `bson:"data"`
+	UpdatedBy int64     `bson:"updated_by"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func GetLocalePacks() ([]LocalePack, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	packs, err := store.AllLocalePacks(ctx)
+	if err != nil {
+		logger.ErrorF("Failed to list locale packs: %v", err)
+		return nil, err
+	}
+	return packs, nil
+}
+
+func SaveLocalePack(pack *LocalePack) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	if err := store.SaveLocalePack(ctx, pack); err != nil {
+		logger.ErrorF("Failed to save locale pack %s: %v", pack.Lang, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteLocalePack removes the stored pack and reports whether it existed.
+func DeleteLocalePack(lang string) (bool, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	deleted, err := store.DeleteLocalePack(ctx, lang)
+	if err != nil {
+		logger.ErrorF("Failed to delete locale pack %s: %v", lang, err)
+		return false, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,71 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+
+	"main/internal/metrics"
+)
+
+var (
+	mongoLatency = metrics.NewHistogram(
+		"yukki_mongo_command_duration_seconds",
+		"Latency of MongoDB commands.",
+		metrics.DefaultBuckets,
+	)
+	mongoFailures = metrics.NewCounter(
+		"yukki_mongo_command_failures_total",
+		"MongoDB commands that failed, by command.",
+		"command",
+	)
+
+	mongoMonitor = &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			mongoLatency.Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			mongoLatency.Observe(e.Duration.Seconds())
+			mongoFailures.Inc(e.CommandName)
+		},
+	}
+)
+
+func init() {
+	metrics.NewCounterFunc(
+		"yukki_db_cache_hits_total",
+		"Database cache lookups served from memory.",
+		func() float64 {
+			hits, _ := dbCache.Stats()
+			return float64(hits)
+		},
+	)
+	metrics.NewCounterFunc(
+		"yukki_db_cache_misses_total",
+		"Database cache lookups that missed.",
+		func() float64 {
+			_, misses := dbCache.Stats()
+			return float64(misses)
+		},
+	)
+}
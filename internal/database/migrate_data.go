@@ -21,6 +21,8 @@
 package database
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -50,168 +52,145 @@ type (
 	}
 )
 
-func migrateData(mongoURI string) {
-	logger.Info("Checking for old database to migrate...")
+// migrateLegacyData (v1) imports the old Python bot's "Yukki" database into
+// the current layout. Only MongoDB deployments can have one. Every step is
+// an idempotent upsert and drops its old collection last, so a failed run
+// is simply retried on the next start.
+func migrateLegacyData(ctx context.Context) error {
+	ms, ok := store.(*mongoStore)
+	if !ok {
+		return nil
+	}
 
-	oldDB := client.Database(oldDBName)
-	ctx, cancel := mongoCtx()
-	defer cancel()
+	oldDB := ms.client.Database(oldDBName)
 
+	// Set by releases before versioned migrations existed.
 	flagColl := oldDB.Collection("migration_status")
-	var result bson.M
-	err := flagColl.FindOne(ctx, bson.M{"migrated": true}).Decode(&result)
+	err := flagColl.FindOne(ctx, bson.M{"migrated": true}).Err()
 	if err == nil {
-		logger.Info("Migration already completed previously. Skipping.")
-		return
+		logger.Info("Legacy data was already migrated. Skipping.")
+		return nil
+	} else if err != mongo.ErrNoDocuments {
+		return fmt.Errorf("read legacy migration flag: %w", err)
+	}
+
+	steps := []struct {
+		name string
+		run  func(context.Context, *mongo.Database) error
+	}{
+		{"cplay settings", migrateCPlay},
+		{"served users", migrateServedUsers},
+		{"served chats", migrateServedChats},
+		{"sudoers", migrateSudoers},
+	}
+	for _, step := range steps {
+		if err := step.run(ctx, oldDB); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+		logger.InfoF("Finished migrating %s.", step.name)
 	}
 
-	// perform migration
-	migrateCPlay(oldDB)
-	migrateServedUsers(oldDB)
-	migrateServedChats(oldDB)
-	migrateSudoers(oldDB)
-
 	_, err = flagColl.InsertOne(ctx, bson.M{
 		"migrated":  true,
 		"timestamp": time.Now(),
 	})
 	if err != nil {
-		logger.ErrorF("Failed to write migration flag: %v", err)
+		return fmt.Errorf("write legacy migration flag: %w", err)
 	}
-	logger.Info("Data migration complete.")
+	return nil
 }
 
-func migrateCPlay(db *mongo.Database) {
+func migrateCPlay(ctx context.Context, db *mongo.Database) error {
 	coll := db.Collection("cplaymode")
-	ctx, cancel := mongoCtx()
-	defer cancel()
 
 	cursor, err := coll.Find(ctx, bson.M{})
 	if err != nil {
-		if err != mongo.ErrNoDocuments {
-			logger.ErrorF("Failed to query old cplaymode collection: %v", err)
-		}
-		return
+		return err
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var old oldCPlay
 		if err := cursor.Decode(&old); err != nil {
-			logger.ErrorF("Failed to decode old cplay document: %v", err)
-			continue
+			return fmt.Errorf("decode cplay document: %w", err)
 		}
 		if err := SetCPlayID(old.ChatID, old.Mode); err != nil {
-			logger.ErrorF(
-				"Failed to migrate cplay for chat %d: %v",
-				old.ChatID,
-				err,
-			)
+			return fmt.Errorf("chat %d: %w", old.ChatID, err)
 		}
 	}
-
-	if err := coll.Drop(ctx); err != nil {
-		logger.ErrorF("Failed to drop cplaymode collection: %v", err)
+	if err := cursor.Err(); err != nil {
+		return err
 	}
 
-	logger.Info("Finished migrating cplay settings.")
+	return coll.Drop(ctx)
 }
 
-func migrateServedUsers(db *mongo.Database) {
+func migrateServedUsers(ctx context.Context, db *mongo.Database) error {
 	coll := db.Collection("tgusersdb")
-	ctx, cancel := mongoCtx()
-	defer cancel()
 
 	cursor, err := coll.Find(ctx, bson.M{"user_id": bson.M{"$gt": 0}})
 	if err != nil {
-		if err != mongo.ErrNoDocuments {
-			logger.ErrorF("Failed to query old tgusersdb collection: %v", err)
-		}
-		return
+		return err
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var old oldServedUser
 		if err := cursor.Decode(&old); err != nil {
-			logger.ErrorF("Failed to decode old user document: %v", err)
-			continue
+			return fmt.Errorf("decode user document: %w", err)
 		}
 		if err := AddServed(old.UserID, true); err != nil {
-			logger.ErrorF(
-				"Failed to migrate served user %d: %v",
-				old.UserID,
-				err,
-			)
+			return fmt.Errorf("user %d: %w", old.UserID, err)
 		}
 	}
-
-	if err := coll.Drop(ctx); err != nil {
-		logger.ErrorF("Failed to drop tgusersdb collection: %v", err)
+	if err := cursor.Err(); err != nil {
+		return err
 	}
 
-	logger.Info("Finished migrating served users.")
+	return coll.Drop(ctx)
 }
 
-func migrateServedChats(db *mongo.Database) {
+func migrateServedChats(ctx context.Context, db *mongo.Database) error {
 	coll := db.Collection("chats")
-	ctx, cancel := mongoCtx()
-	defer cancel()
 
 	cursor, err := coll.Find(ctx, bson.M{"chat_id": bson.M{"$lt": 0}})
 	if err != nil {
-		if err != mongo.ErrNoDocuments {
-			logger.ErrorF("Failed to query old chats collection: %v", err)
-		}
-		return
+		return err
 	}
 	defer cursor.Close(ctx)
 
 	for cursor.Next(ctx) {
 		var old oldServedChat
 		if err := cursor.Decode(&old); err != nil {
-			logger.ErrorF("Failed to decode old chat document: %v", err)
-			continue
+			return fmt.Errorf("decode chat document: %w", err)
 		}
 		if err := AddServed(old.ChatID, false); err != nil {
-			logger.ErrorF(
-				"Failed to migrate served chat %d: %v",
-				old.ChatID,
-				err,
-			)
+			return fmt.Errorf("chat %d: %w", old.ChatID, err)
 		}
 	}
-
-	if err := coll.Drop(ctx); err != nil {
-		logger.ErrorF("Failed to drop chats collection: %v", err)
+	if err := cursor.Err(); err != nil {
+		return err
 	}
 
-	logger.Info("Finished migrating served chats.")
+	return coll.Drop(ctx)
 }
 
-func migrateSudoers(db *mongo.Database) {
+func migrateSudoers(ctx context.Context, db *mongo.Database) error {
 	coll := db.Collection("sudoers")
-	ctx, cancel := mongoCtx()
-	defer cancel()
 
 	var doc oldSudoers
 	err := coll.FindOne(ctx, bson.M{"sudo": "sudo"}).Decode(&doc)
-	if err != nil {
-		if err != mongo.ErrNoDocuments {
-			logger.ErrorF("Failed to query old sudoers collection: %v", err)
-		}
-		return
+	if err == mongo.ErrNoDocuments {
+		return nil
+	} else if err != nil {
+		return err
 	}
 
 	for _, sudoerID := range doc.Sudoers {
 		if err := AddSudo(sudoerID); err != nil {
-			logger.ErrorF("Failed to migrate sudoer %d: %v", sudoerID, err)
+			return fmt.Errorf("sudoer %d: %w", sudoerID, err)
 		}
 	}
 
-	if err := coll.Drop(ctx); err != nil {
-		logger.ErrorF("Failed to drop empty sudoers collection: %v", err)
-	}
-
-	logger.Info("Finished migrating sudoers.")
+	return coll.Drop(ctx)
 }
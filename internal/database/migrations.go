@@ -0,0 +1,154 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// migration is one numbered, idempotent schema change. Migrations run in
+// order at startup and the highest applied version is recorded in the
+// schema_version document, so each one runs once per database. Never
+// renumber or remove an entry: append a new one instead.
+type migration struct {
+	version int
+	name    string
+	up      func(ctx context.Context) error
+}
+
+var migrations = []migration{
+	{1, "import legacy Yukki database", migrateLegacyData},
+}
+
+const (
+	migrationLock     = "schema_migration"
+	migrationLockTTL  = 15 * time.Minute
+	migrationLockWait = 5 * time.Minute
+	migrationTimeout  = 10 * time.Minute
+)
+
+// SchemaVersion is the version this build migrates the database to.
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// runMigrations brings the database up to SchemaVersion. Another instance
+// migrating the same database holds the lock, so this waits for it and then
+// usually finds nothing left to do.
+func runMigrations() error {
+	ctx := context.Background()
+	latest := SchemaVersion()
+
+	current, err := store.SchemaVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if current == latest {
+		logger.DebugF("Database schema is up to date (v%d)", current)
+		return nil
+	}
+	if current > latest {
+		return fmt.Errorf(
+			"database schema v%d is newer than this build supports (v%d)",
+			current,
+			latest,
+		)
+	}
+
+	owner := lockOwner()
+	if err := acquireMigrationLock(ctx, owner); err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.ReleaseLock(ctx, migrationLock, owner); err != nil {
+			logger.WarnF("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	// Re-read: whoever held the lock before us may have done the work.
+	if current, err = store.SchemaVersion(ctx); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		// Extend the lock so a long run of migrations does not outlive it.
+		ok, err := store.AcquireLock(ctx, migrationLock, owner, migrationLockTTL)
+		if err != nil || !ok {
+			return fmt.Errorf("lost migration lock before v%d: %v", m.version, err)
+		}
+
+		logger.InfoF("Applying migration v%d: %s", m.version, m.name)
+		start := time.Now()
+
+		mctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+		err = m.up(mctx)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("migration v%d (%s) failed: %w", m.version, m.name, err)
+		}
+
+		if err := store.SetSchemaVersion(ctx, m.version); err != nil {
+			return fmt.Errorf("record schema version %d: %w", m.version, err)
+		}
+		logger.InfoF(
+			"Migration v%d done in %s",
+			m.version,
+			time.Since(start).Round(time.Millisecond),
+		)
+	}
+
+	logger.InfoF("Database schema is at v%d", latest)
+	return nil
+}
+
+func acquireMigrationLock(ctx context.Context, owner string) error {
+	deadline := time.Now().Add(migrationLockWait)
+	for {
+		ok, err := store.AcquireLock(ctx, migrationLock, owner, migrationLockTTL)
+		if err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf(
+				"another instance is still migrating the database after %s",
+				migrationLockWait,
+			)
+		}
+		logger.Info("Waiting for another instance to finish migrating the database...")
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func lockOwner() string {
+	host, _ := os.Hostname()
+	return host + ":" + strconv.Itoa(os.Getpid())
+}
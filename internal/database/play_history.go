@@ -0,0 +1,121 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import "time"
+
+// PlayEntry is one track play logged in the play history. ExpiresAt is
+// when the entry is dropped; zero keeps it forever.
+type PlayEntry struct {
+	ChatID      int64     `bson:"chat_id"`
+	TrackID     string    `bson:"track_id"`
+	Title       string    `bson:"title"`
+	Source      string    `bson:"source"`
+	RequesterID int64     `bson:"requester_id,omitempty"`
+	Duration    int       `bson:"duration"`
+	Played      int       `bson:"played"`
+	Skipped     bool      `bson:"skipped"`
+	StartedAt   time.Time `bson:"started_at"`
+	ExpiresAt   time.Time `bson:"expires_at,omitempty"`
+}
+
+// TrackPlays is how often a track was played in a period.
+type TrackPlays struct {
+	TrackID string `bson:"_id"`
+	Title   string `bson:"title"`
+	Source  string `bson:"source"`
+	Plays   int    `bson:"plays"`
+	Skips   int    `bson:"skips"`
+	Played  int64  `bson:"played"`
+}
+
+// RequesterPlays is how many plays a user requested in a period.
+type RequesterPlays struct {
+	UserID int64 `bson:"_id"`
+	Plays  int   `bson:"plays"`
+	Played int64 `bson:"played"`
+}
+
+func AddPlay(p *PlayEntry) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	if err := store.AddPlay(ctx, p); err != nil {
+		logger.ErrorF("Failed to log play chat_id=%d track_id=%s: %v", p.ChatID, p.TrackID, err)
+		return err
+	}
+	return nil
+}
+
+// GetTopTracks returns the most played tracks of chatID since the given
+// time, or of every chat when chatID is 0.
+func GetTopTracks(chatID int64, since time.Time, limit int) ([]TrackPlays, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	tracks, err := store.TopPlayedTracks(ctx, chatID, since, limit)
+	if err != nil {
+		logger.ErrorF("Failed to get top tracks chat_id=%d: %v", chatID, err)
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// splitActivePlayers turns the rooms and requesters of the play history
+// into chats and users; a room with a positive id is a private call.
+func splitActivePlayers(rooms, requesters []int64) (chats, users []int64) {
+	users = append(users, requesters...)
+	for _, id := range rooms {
+		if id < 0 {
+			chats = append(chats, id)
+		} else {
+			users = append(users, id)
+		}
+	}
+	return chats, users
+}
+
+// GetActivePlayers returns the chats that played something since the given
+// time and the users who requested a play or took a private call.
+func GetActivePlayers(since time.Time) (chats, users []int64, err error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	chats, users, err = store.ActivePlayers(ctx, since)
+	if err != nil {
+		logger.ErrorF("Failed to get active chats: %v", err)
+	}
+	return chats, users, err
+}
+
+// GetTopRequesters returns the users who requested the most plays in
+// chatID since the given time, or in every chat when chatID is 0.
+func GetTopRequesters(chatID int64, since time.Time, limit int) ([]RequesterPlays, error) {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	users, err := store.TopRequesters(ctx, chatID, since, limit)
+	if err != nil {
+		logger.ErrorF("Failed to get top requesters chat_id=%d: %v", chatID, err)
+		return nil, err
+	}
+	return users, nil
+}
@@ -101,3 +101,41 @@ func DeleteServed(id int64, user ...bool) error {
 	*target = newSlice
 	return updateBotState(state)
 }
+
+// PruneServed drops ids from the served chats and users in one write and
+// returns how many were removed.
+func PruneServed(ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	state, err := getBotState()
+	if err != nil {
+		return 0, err
+	}
+
+	drop := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		drop[id] = struct{}{}
+	}
+
+	removed := 0
+	keep := func(list []int64) []int64 {
+		kept := make([]int64, 0, len(list))
+		for _, v := range list {
+			if _, ok := drop[v]; ok {
+				removed++
+				continue
+			}
+			kept = append(kept, v)
+		}
+		return kept
+	}
+
+	state.Served.Chats = keep(state.Served.Chats)
+	state.Served.Users = keep(state.Served.Users)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, updateBotState(state)
+}
@@ -0,0 +1,70 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import "time"
+
+// SpotifyMatch is the YouTube video picked with /wrongsong for a Spotify
+// track. It is played instead of the best scored search result.
+type SpotifyMatch struct {
+	SpotifyID string    `bson:"_id"`
+	YouTubeID string    `bson:"youtube_id"`
+	SetBy     int64     `bson:"set_by"`
+	SetAt     time.Time `bson:"set_at"`
+}
+
+// GetSpotifyMatch returns the picked YouTube ID of a Spotify track, or ""
+// when none was picked.
+func GetSpotifyMatch(spotifyID string) (string, error) {
+	cacheKey := "spotify_match_" + spotifyID
+	if cached, ok := dbCache.Get(cacheKey); ok {
+		if id, ok := cached.(string); ok {
+			return id, nil
+		}
+	}
+
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	m, err := store.GetSpotifyMatch(ctx, spotifyID)
+	if err != nil {
+		logger.ErrorF("Failed to get Spotify match track_id=%s: %v", spotifyID, err)
+		return "", err
+	}
+	id := ""
+	if m != nil {
+		id = m.YouTubeID
+	}
+	dbCache.Set(cacheKey, id)
+	return id, nil
+}
+
+func SaveSpotifyMatch(m *SpotifyMatch) error {
+	ctx, cancel := mongoCtx()
+	defer cancel()
+
+	if err := store.SaveSpotifyMatch(ctx, m); err != nil {
+		logger.ErrorF("Failed to save Spotify match track_id=%s: %v", m.SpotifyID, err)
+		return err
+	}
+	dbCache.Set("spotify_match_"+m.SpotifyID, m.YouTubeID)
+	return nil
+}
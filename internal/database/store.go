@@ -0,0 +1,181 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	BackendMongo = "mongo"
+	BackendBolt  = "bolt"
+)
+
+// Store is the persistence backend behind the database package. Everything
+// the bot keeps (sudoers, served stats, auth users, RTMP config, assistant
+// indexes, ...) lives in the chat settings and bot state documents, so a
+// backend only has to store those plus the API keys, locale packs, the
+// listener analytics, the play history, broadcast jobs and /wrongsong
+// picks.
+//
+// Getters return nil and no error when the document does not exist. Saves
+// replace the whole document, so zero and empty fields are cleared too.
+type Store interface {
+	GetChatSettings(ctx context.Context, chatID int64) (*ChatSettings, error)
+	SaveChatSettings(ctx context.Context, s *ChatSettings) error
+	DeleteChatSettings(ctx context.Context, chatID int64) error
+	AllChatSettings(ctx context.Context) ([]*ChatSettings, error)
+	FindChatByCPlayID(ctx context.Context, cplayID int64) (*ChatSettings, error)
+
+	GetBotState(ctx context.Context) (*BotState, error)
+	SaveBotState(ctx context.Context, s *BotState) error
+
+	GetAPIKey(ctx context.Context, hash string) (*APIKey, error)
+	SaveAPIKey(ctx context.Context, key *APIKey) error
+	AllAPIKeys(ctx context.Context) ([]APIKey, error)
+	DeleteAPIKeys(ctx context.Context, name string) ([]APIKey, error)
+
+	AllLocalePacks(ctx context.Context) ([]LocalePack, error)
+	SaveLocalePack(ctx context.Context, pack *LocalePack) error
+	DeleteLocalePack(ctx context.Context, lang string) (bool, error)
+
+	// Listener analytics, see listen_stats.go. Days are ListenDayKey strings.
+	AddListenSample(ctx context.Context, ls *ListenSample) error
+	TopTrackListens(ctx context.Context, chatID int64, limit int) ([]TrackListens, error)
+	ListenDays(ctx context.Context, chatID int64, since string) ([]ListenDay, error)
+	ListenTotals(ctx context.Context, since string) (*ListenTotals, error)
+
+	// Play history, see play_history.go. A chatID of 0 covers every chat.
+	AddPlay(ctx context.Context, p *PlayEntry) error
+	TopPlayedTracks(ctx context.Context, chatID int64, since time.Time, limit int) ([]TrackPlays, error)
+	TopRequesters(ctx context.Context, chatID int64, since time.Time, limit int) ([]RequesterPlays, error)
+	ActivePlayers(ctx context.Context, since time.Time) (chats, users []int64, err error)
+
+	// Broadcast jobs, see broadcast_jobs.go.
+	SaveBroadcastJob(ctx context.Context, j *BroadcastJob) error
+	GetBroadcastJob(ctx context.Context, id string) (*BroadcastJob, error)
+	AllBroadcastJobs(ctx context.Context) ([]*BroadcastJob, error)
+	DeleteBroadcastJob(ctx context.Context, id string) error
+
+	// /wrongsong picks, see spotify_matches.go.
+	GetSpotifyMatch(ctx context.Context, spotifyID string) (*SpotifyMatch, error)
+	SaveSpotifyMatch(ctx context.Context, m *SpotifyMatch) error
+
+	// Schema bookkeeping and the advisory lock used by migrations.go.
+	// AcquireLock succeeds when the lock is free, expired or already held by
+	// owner, and extends it by ttl.
+	SchemaVersion(ctx context.Context) (int, error)
+	SetSchemaVersion(ctx context.Context, version int) error
+	AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, name, owner string) error
+
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Open connects to a backend: dsn is the connection URI for mongo and the
+// file path for bolt.
+func Open(backend, dsn string) (Store, error) {
+	switch backend {
+	case BackendMongo, "":
+		return openMongoStore(dsn)
+	case BackendBolt:
+		return openBoltStore(dsn)
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", backend)
+	}
+}
+
+// CopyStats reports how many documents Copy wrote.
+type CopyStats struct {
+	Chats         int
+	BotState      bool
+	APIKeys       int
+	LocalePacks   int
+	SchemaVersion int
+}
+
+// Copy writes every document of src into dst, overwriting documents that
+// already exist there. It is used to move a deployment between backends.
+// Listener analytics, play history, broadcast jobs and /wrongsong picks are
+// not copied; they start over on the new backend.
+func Copy(ctx context.Context, src, dst Store) (CopyStats, error) {
+	var stats CopyStats
+
+	chats, err := src.AllChatSettings(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("read chat settings: %w", err)
+	}
+	for _, cs := range chats {
+		if err := dst.SaveChatSettings(ctx, cs); err != nil {
+			return stats, fmt.Errorf("write chat %d: %w", cs.ChatID, err)
+		}
+		stats.Chats++
+	}
+
+	state, err := src.GetBotState(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("read bot state: %w", err)
+	}
+	if state != nil {
+		if err := dst.SaveBotState(ctx, state); err != nil {
+			return stats, fmt.Errorf("write bot state: %w", err)
+		}
+		stats.BotState = true
+	}
+
+	keys, err := src.AllAPIKeys(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("read api keys: %w", err)
+	}
+	for i := range keys {
+		if err := dst.SaveAPIKey(ctx, &keys[i]); err != nil {
+			return stats, fmt.Errorf("write api key %s: %w", keys[i].Name, err)
+		}
+		stats.APIKeys++
+	}
+
+	packs, err := src.AllLocalePacks(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("read locale packs: %w", err)
+	}
+	for i := range packs {
+		if err := dst.SaveLocalePack(ctx, &packs[i]); err != nil {
+			return stats, fmt.Errorf("write locale pack %s: %w", packs[i].Lang, err)
+		}
+		stats.LocalePacks++
+	}
+
+	// Carry the schema version over so the target does not re-run
+	// migrations against data that is already in the current layout.
+	version, err := src.SchemaVersion(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("read schema version: %w", err)
+	}
+	if err := dst.SetSchemaVersion(ctx, version); err != nil {
+		return stats, fmt.Errorf("write schema version: %w", err)
+	}
+	stats.SchemaVersion = version
+
+	return stats, nil
+}
@@ -0,0 +1,712 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	boltChatSettings = []byte("chat_settings")
+	boltBotSettings  = []byte("bot_settings")
+	boltAPIKeys      = []byte("api_keys")
+	boltLocalePacks  = []byte("locale_packs")
+	boltMeta         = []byte("meta")
+	boltTrackListens = []byte("track_listens")
+	boltListenDays   = []byte("listen_days")
+	boltPlayHistory  = []byte("play_history")
+	boltBroadcasts   = []byte("broadcast_jobs")
+	boltSpotify      = []byte("spotify_matches")
+
+	botStateKey      = []byte("global")
+	schemaVersionKey = []byte("schema_version")
+)
+
+// boltStore keeps everything in a single bbolt file, one bucket per
+// collection of the mongo backend. Documents are stored BSON encoded so
+// both backends share the same struct tags.
+type boltStore struct {
+	db *bolt.DB
+
+	// Last sweep of expired play history, only touched in write
+	// transactions.
+	lastPrune time.Time
+}
+
+func openBoltStore(path string) (*boltStore, error) {
+	if path == "" {
+		return nil, errors.New("DB_PATH is required for the bolt backend")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{
+			boltChatSettings, boltBotSettings, boltAPIKeys,
+			boltLocalePacks, boltMeta, boltTrackListens, boltListenDays,
+			boltPlayHistory, boltBroadcasts, boltSpotify,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func chatKey(chatID int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(chatID))
+	return key
+}
+
+func (s *boltStore) get(bucket, key []byte, v any) (bool, error) {
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(key)
+		if data == nil {
+			return nil
+		}
+		found = true
+		return bson.Unmarshal(data, v)
+	})
+	return found, err
+}
+
+func (s *boltStore) put(bucket, key []byte, v any) error {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, data)
+	})
+}
+
+func (s *boltStore) GetChatSettings(
+	_ context.Context,
+	chatID int64,
+) (*ChatSettings, error) {
+	var settings ChatSettings
+	found, err := s.get(boltChatSettings, chatKey(chatID), &settings)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *boltStore) SaveChatSettings(_ context.Context, cs *ChatSettings) error {
+	return s.put(boltChatSettings, chatKey(cs.ChatID), cs)
+}
+
+func (s *boltStore) DeleteChatSettings(_ context.Context, chatID int64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltChatSettings).Delete(chatKey(chatID))
+	})
+}
+
+func (s *boltStore) AllChatSettings(_ context.Context) ([]*ChatSettings, error) {
+	var all []*ChatSettings
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltChatSettings).ForEach(func(_, data []byte) error {
+			var cs ChatSettings
+			if err := bson.Unmarshal(data, &cs); err != nil {
+				return err
+			}
+			all = append(all, &cs)
+			return nil
+		})
+	})
+	return all, err
+}
+
+// FindChatByCPlayID scans the whole bucket; it is only hit on a cache miss
+// of the cplay mapping.
+func (s *boltStore) FindChatByCPlayID(
+	ctx context.Context,
+	cplayID int64,
+) (*ChatSettings, error) {
+	all, err := s.AllChatSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cs := range all {
+		if cs.CPlayID == cplayID {
+			return cs, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *boltStore) GetBotState(_ context.Context) (*BotState, error) {
+	var state BotState
+	found, err := s.get(boltBotSettings, botStateKey, &state)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *boltStore) SaveBotState(_ context.Context, state *BotState) error {
+	return s.put(boltBotSettings, botStateKey, state)
+}
+
+func (s *boltStore) GetAPIKey(_ context.Context, hash string) (*APIKey, error) {
+	var key APIKey
+	found, err := s.get(boltAPIKeys, []byte(hash), &key)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (s *boltStore) SaveAPIKey(_ context.Context, key *APIKey) error {
+	return s.put(boltAPIKeys, []byte(key.Hash), key)
+}
+
+func (s *boltStore) AllAPIKeys(_ context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltAPIKeys).ForEach(func(_, data []byte) error {
+			var key APIKey
+			if err := bson.Unmarshal(data, &key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (s *boltStore) DeleteAPIKeys(_ context.Context, name string) ([]APIKey, error) {
+	var deleted []APIKey
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltAPIKeys)
+		err := b.ForEach(func(_, data []byte) error {
+			var key APIKey
+			if err := bson.Unmarshal(data, &key); err != nil {
+				return err
+			}
+			if key.Name == name {
+				deleted = append(deleted, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range deleted {
+			if err := b.Delete([]byte(key.Hash)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+func (s *boltStore) AllLocalePacks(_ context.Context) ([]LocalePack, error) {
+	var packs []LocalePack
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltLocalePacks).ForEach(func(_, data []byte) error {
+			var pack LocalePack
+			if err := bson.Unmarshal(data, &pack); err != nil {
+				return err
+			}
+			packs = append(packs, pack)
+			return nil
+		})
+	})
+	return packs, err
+}
+
+func (s *boltStore) SaveLocalePack(_ context.Context, pack *LocalePack) error {
+	return s.put(boltLocalePacks, []byte(pack.Lang), pack)
+}
+
+func (s *boltStore) DeleteLocalePack(_ context.Context, lang string) (bool, error) {
+	deleted := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltLocalePacks)
+		if b.Get([]byte(lang)) == nil {
+			return nil
+		}
+		deleted = true
+		return b.Delete([]byte(lang))
+	})
+	return deleted && err == nil, err
+}
+
+// Analytics keys start with the chat key so a chat's documents can be read
+// with a prefix scan.
+func chatPrefixKey(chatID int64, suffix string) []byte {
+	return append(chatKey(chatID), suffix...)
+}
+
+func (s *boltStore) AddListenSample(_ context.Context, ls *ListenSample) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var track TrackListens
+		if err := updateBolt(
+			tx.Bucket(boltTrackListens),
+			chatPrefixKey(ls.ChatID, ls.TrackID),
+			&track,
+			func() { track.add(ls) },
+		); err != nil {
+			return err
+		}
+
+		var day ListenDay
+		return updateBolt(
+			tx.Bucket(boltListenDays),
+			chatPrefixKey(ls.ChatID, ListenDayKey(ls.At)),
+			&day,
+			func() { day.add(ls) },
+		)
+	})
+}
+
+// updateBolt decodes the document at key into v if there is one, applies
+// change and writes v back.
+func updateBolt(b *bolt.Bucket, key []byte, v any, change func()) error {
+	if data := b.Get(key); data != nil {
+		if err := bson.Unmarshal(data, v); err != nil {
+			return err
+		}
+	}
+	change()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+func (s *boltStore) TopTrackListens(
+	_ context.Context,
+	chatID int64,
+	limit int,
+) ([]TrackListens, error) {
+	var tracks []TrackListens
+	prefix := chatKey(chatID)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltTrackListens).Cursor()
+		for k, data := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, data = c.Next() {
+			var t TrackListens
+			if err := bson.Unmarshal(data, &t); err != nil {
+				return err
+			}
+			tracks = append(tracks, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(tracks, func(i, j int) bool {
+		return tracks[i].ListenerSum > tracks[j].ListenerSum
+	})
+	if len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks, nil
+}
+
+func (s *boltStore) ListenDays(
+	_ context.Context,
+	chatID int64,
+	since string,
+) ([]ListenDay, error) {
+	var days []ListenDay
+	prefix := chatKey(chatID)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltListenDays).Cursor()
+		for k, data := c.Seek(chatPrefixKey(chatID, since)); k != nil && bytes.HasPrefix(k, prefix); k, data = c.Next() {
+			var d ListenDay
+			if err := bson.Unmarshal(data, &d); err != nil {
+				return err
+			}
+			days = append(days, d)
+		}
+		return nil
+	})
+	return days, err
+}
+
+func (s *boltStore) ListenTotals(_ context.Context, since string) (*ListenTotals, error) {
+	totals := &ListenTotals{}
+	chats := make(map[int64]struct{})
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltListenDays).ForEach(func(_, data []byte) error {
+			var d ListenDay
+			if err := bson.Unmarshal(data, &d); err != nil {
+				return err
+			}
+			if d.Day < since {
+				return nil
+			}
+			totals.Minutes += d.Minutes
+			totals.Peak = max(totals.Peak, d.Peak)
+			chats[d.ChatID] = struct{}{}
+			return nil
+		})
+	})
+	totals.Chats = len(chats)
+	return totals, err
+}
+
+// Mongo drops expired play history with a TTL index; bolt sweeps it while
+// logging new plays, at most once per boltPruneInterval.
+const boltPruneInterval = time.Hour
+
+// playKey orders a chat's plays by start time.
+func playKey(chatID int64, at time.Time) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(chatID))
+	if at.After(time.Unix(0, 0)) {
+		binary.BigEndian.PutUint64(key[8:], uint64(at.UnixNano()))
+	}
+	return key
+}
+
+func (s *boltStore) AddPlay(_ context.Context, p *PlayEntry) error {
+	data, err := bson.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltPlayHistory)
+		if err := s.pruneExpiredPlays(b); err != nil {
+			return err
+		}
+		return b.Put(playKey(p.ChatID, p.StartedAt), data)
+	})
+}
+
+func (s *boltStore) pruneExpiredPlays(b *bolt.Bucket) error {
+	now := time.Now()
+	if now.Sub(s.lastPrune) < boltPruneInterval {
+		return nil
+	}
+	s.lastPrune = now
+
+	var expired [][]byte
+	err := b.ForEach(func(k, data []byte) error {
+		var p PlayEntry
+		if err := bson.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		if !p.ExpiresAt.IsZero() && p.ExpiresAt.Before(now) {
+			expired = append(expired, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachPlay calls fn with the plays of chatID, or of every chat when
+// chatID is 0, that started at or after since.
+func (s *boltStore) forEachPlay(chatID int64, since time.Time, fn func(*PlayEntry)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltPlayHistory).Cursor()
+
+		k, data := c.First()
+		prefix := []byte{}
+		if chatID != 0 {
+			prefix = chatKey(chatID)
+			k, data = c.Seek(playKey(chatID, since))
+		}
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, data = c.Next() {
+			var p PlayEntry
+			if err := bson.Unmarshal(data, &p); err != nil {
+				return err
+			}
+			if !p.StartedAt.Before(since) {
+				fn(&p)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) TopPlayedTracks(
+	_ context.Context,
+	chatID int64,
+	since time.Time,
+	limit int,
+) ([]TrackPlays, error) {
+	byID := make(map[string]*TrackPlays)
+	err := s.forEachPlay(chatID, since, func(p *PlayEntry) {
+		t, ok := byID[p.TrackID]
+		if !ok {
+			t = &TrackPlays{TrackID: p.TrackID}
+			byID[p.TrackID] = t
+		}
+		t.Title = p.Title
+		t.Source = p.Source
+		t.Plays++
+		t.Played += int64(p.Played)
+		if p.Skipped {
+			t.Skips++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]TrackPlays, 0, len(byID))
+	for _, t := range byID {
+		tracks = append(tracks, *t)
+	}
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].Plays != tracks[j].Plays {
+			return tracks[i].Plays > tracks[j].Plays
+		}
+		return tracks[i].Played > tracks[j].Played
+	})
+	if len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks, nil
+}
+
+func (s *boltStore) TopRequesters(
+	_ context.Context,
+	chatID int64,
+	since time.Time,
+	limit int,
+) ([]RequesterPlays, error) {
+	byID := make(map[int64]*RequesterPlays)
+	err := s.forEachPlay(chatID, since, func(p *PlayEntry) {
+		if p.RequesterID <= 0 {
+			return
+		}
+		u, ok := byID[p.RequesterID]
+		if !ok {
+			u = &RequesterPlays{UserID: p.RequesterID}
+			byID[p.RequesterID] = u
+		}
+		u.Plays++
+		u.Played += int64(p.Played)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]RequesterPlays, 0, len(byID))
+	for _, u := range byID {
+		users = append(users, *u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Plays != users[j].Plays {
+			return users[i].Plays > users[j].Plays
+		}
+		return users[i].Played > users[j].Played
+	})
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (s *boltStore) ActivePlayers(
+	_ context.Context,
+	since time.Time,
+) ([]int64, []int64, error) {
+	rooms := make(map[int64]struct{})
+	requesters := make(map[int64]struct{})
+	err := s.forEachPlay(0, since, func(p *PlayEntry) {
+		rooms[p.ChatID] = struct{}{}
+		if p.RequesterID > 0 {
+			requesters[p.RequesterID] = struct{}{}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	chats, users := splitActivePlayers(
+		slices.Collect(maps.Keys(rooms)),
+		slices.Collect(maps.Keys(requesters)),
+	)
+	return chats, users, nil
+}
+
+func (s *boltStore) SaveBroadcastJob(_ context.Context, j *BroadcastJob) error {
+	return s.put(boltBroadcasts, []byte(j.ID), j)
+}
+
+func (s *boltStore) GetBroadcastJob(_ context.Context, id string) (*BroadcastJob, error) {
+	var job BroadcastJob
+	found, err := s.get(boltBroadcasts, []byte(id), &job)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *boltStore) AllBroadcastJobs(_ context.Context) ([]*BroadcastJob, error) {
+	var jobs []*BroadcastJob
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBroadcasts).ForEach(func(_, data []byte) error {
+			var job BroadcastJob
+			if err := bson.Unmarshal(data, &job); err != nil {
+				return err
+			}
+			jobs = append(jobs, &job)
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+func (s *boltStore) DeleteBroadcastJob(_ context.Context, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBroadcasts).Delete([]byte(id))
+	})
+}
+
+func (s *boltStore) GetSpotifyMatch(_ context.Context, spotifyID string) (*SpotifyMatch, error) {
+	var m SpotifyMatch
+	found, err := s.get(boltSpotify, []byte(spotifyID), &m)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *boltStore) SaveSpotifyMatch(_ context.Context, m *SpotifyMatch) error {
+	return s.put(boltSpotify, []byte(m.SpotifyID), m)
+}
+
+func (s *boltStore) SchemaVersion(_ context.Context) (int, error) {
+	var doc schemaDoc
+	_, err := s.get(boltMeta, schemaVersionKey, &doc)
+	return doc.Version, err
+}
+
+func (s *boltStore) SetSchemaVersion(_ context.Context, version int) error {
+	return s.put(
+		boltMeta,
+		schemaVersionKey,
+		schemaDoc{Version: version, UpdatedAt: time.Now()},
+	)
+}
+
+type boltLock struct {
+	Owner     string    `bson:"owner"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// AcquireLock mirrors the mongo lock. bbolt already holds an exclusive file
+// lock for the whole process, so this only matters within one process.
+func (s *boltStore) AcquireLock(
+	_ context.Context,
+	name, owner string,
+	ttl time.Duration,
+) (bool, error) {
+	key := []byte("lock_" + name)
+	acquired := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltMeta)
+		now := time.Now()
+		if data := b.Get(key); data != nil {
+			var held boltLock
+			if err := bson.Unmarshal(data, &held); err != nil {
+				return err
+			}
+			if held.Owner != owner && held.ExpiresAt.After(now) {
+				return nil
+			}
+		}
+		data, err := bson.Marshal(boltLock{Owner: owner, ExpiresAt: now.Add(ttl)})
+		if err != nil {
+			return err
+		}
+		acquired = true
+		return b.Put(key, data)
+	})
+	return acquired && err == nil, err
+}
+
+func (s *boltStore) ReleaseLock(_ context.Context, name, owner string) error {
+	key := []byte("lock_" + name)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltMeta)
+		data := b.Get(key)
+		if data == nil {
+			return nil
+		}
+		var held boltLock
+		if err := bson.Unmarshal(data, &held); err != nil {
+			return err
+		}
+		if held.Owner != owner {
+			return nil
+		}
+		return b.Delete(key)
+	})
+}
+
+func (s *boltStore) Ping(_ context.Context) error {
+	return s.db.View(func(*bolt.Tx) error { return nil })
+}
+
+func (s *boltStore) Close(_ context.Context) error {
+	return s.db.Close()
+}
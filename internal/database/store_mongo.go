@@ -0,0 +1,610 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoStore struct {
+	client       *mongo.Client
+	settings     *mongo.Collection
+	chatSettings *mongo.Collection
+	apiKeys      *mongo.Collection
+	localePacks  *mongo.Collection
+	schema       *mongo.Collection
+	locks        *mongo.Collection
+	trackListens *mongo.Collection
+	listenDays   *mongo.Collection
+	playHistory  *mongo.Collection
+	broadcasts   *mongo.Collection
+	spotify      *mongo.Collection
+}
+
+func openMongoStore(uri string) (*mongoStore, error) {
+	if uri == "" {
+		return nil, errors.New("MONGO_DB_URI is required for the mongo backend")
+	}
+
+	c, err := mongo.Connect(
+		options.Client().ApplyURI(uri).SetMonitor(mongoMonitor),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	db := c.Database("YukkiMusic")
+	s := &mongoStore{
+		client:       c,
+		settings:     db.Collection("bot_settings"),
+		chatSettings: db.Collection("chat_settings"),
+		apiKeys:      db.Collection("api_keys"),
+		localePacks:  db.Collection("locale_packs"),
+		schema:       db.Collection("schema"),
+		locks:        db.Collection("locks"),
+		trackListens: db.Collection("track_listens"),
+		listenDays:   db.Collection("listen_days"),
+		playHistory:  db.Collection("play_history"),
+		broadcasts:   db.Collection("broadcast_jobs"),
+		spotify:      db.Collection("spotify_matches"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.ensureIndexes(ctx); err != nil {
+		c.Disconnect(ctx)
+		return nil, err
+	}
+	return s, nil
+}
+
+// ensureIndexes creates the indexes the analytics queries rely on, and the
+// TTL index that drops expired play history. Creating an index that already
+// exists is a no-op.
+func (s *mongoStore) ensureIndexes(ctx context.Context) error {
+	_, err := s.trackListens.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "listener_sum", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.listenDays.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "day", Value: 1}}},
+		{Keys: bson.D{{Key: "day", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.playHistory.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "started_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+func (s *mongoStore) GetChatSettings(
+	ctx context.Context,
+	chatID int64,
+) (*ChatSettings, error) {
+	var settings ChatSettings
+	err := s.chatSettings.FindOne(ctx, bson.M{"_id": chatID}).Decode(&settings)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *mongoStore) SaveChatSettings(ctx context.Context, cs *ChatSettings) error {
+	_, err := s.chatSettings.ReplaceOne(
+		ctx,
+		bson.M{"_id": cs.ChatID},
+		cs,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) DeleteChatSettings(ctx context.Context, chatID int64) error {
+	_, err := s.chatSettings.DeleteOne(ctx, bson.M{"_id": chatID})
+	return err
+}
+
+func (s *mongoStore) AllChatSettings(ctx context.Context) ([]*ChatSettings, error) {
+	cursor, err := s.chatSettings.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var all []*ChatSettings
+	for cursor.Next(ctx) {
+		var cs ChatSettings
+		if err := cursor.Decode(&cs); err != nil {
+			return nil, err
+		}
+		all = append(all, &cs)
+	}
+	return all, cursor.Err()
+}
+
+func (s *mongoStore) FindChatByCPlayID(
+	ctx context.Context,
+	cplayID int64,
+) (*ChatSettings, error) {
+	var settings ChatSettings
+	err := s.chatSettings.FindOne(ctx, bson.M{"cplay_id": cplayID}).
+		Decode(&settings)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *mongoStore) GetBotState(ctx context.Context) (*BotState, error) {
+	var state BotState
+	err := s.settings.FindOne(ctx, bson.M{"_id": "global"}).Decode(&state)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *mongoStore) SaveBotState(ctx context.Context, state *BotState) error {
+	_, err := s.settings.ReplaceOne(
+		ctx,
+		bson.M{"_id": "global"},
+		state,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) GetAPIKey(ctx context.Context, hash string) (*APIKey, error) {
+	var key APIKey
+	err := s.apiKeys.FindOne(ctx, bson.M{"_id": hash}).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (s *mongoStore) SaveAPIKey(ctx context.Context, key *APIKey) error {
+	_, err := s.apiKeys.ReplaceOne(
+		ctx,
+		bson.M{"_id": key.Hash},
+		key,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) AllAPIKeys(ctx context.Context) ([]APIKey, error) {
+	cursor, err := s.apiKeys.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *mongoStore) DeleteAPIKeys(ctx context.Context, name string) ([]APIKey, error) {
+	cursor, err := s.apiKeys.Find(ctx, bson.M{"name": name})
+	if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	if _, err := s.apiKeys.DeleteMany(ctx, bson.M{"name": name}); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *mongoStore) AllLocalePacks(ctx context.Context) ([]LocalePack, error) {
+	cursor, err := s.localePacks.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []LocalePack
+	if err := cursor.All(ctx, &packs); err != nil {
+		return nil, err
+	}
+	return packs, nil
+}
+
+func (s *mongoStore) SaveLocalePack(ctx context.Context, pack *LocalePack) error {
+	_, err := s.localePacks.ReplaceOne(
+		ctx,
+		bson.M{"_id": pack.Lang},
+		pack,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) DeleteLocalePack(ctx context.Context, lang string) (bool, error) {
+	res, err := s.localePacks.DeleteOne(ctx, bson.M{"_id": lang})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (s *mongoStore) AddListenSample(ctx context.Context, ls *ListenSample) error {
+	at := ls.At.UTC()
+	_, err := s.trackListens.UpdateOne(
+		ctx,
+		bson.M{"_id": trackListensID(ls.ChatID, ls.TrackID)},
+		bson.M{
+			"$set": bson.M{
+				"chat_id":  ls.ChatID,
+				"track_id": ls.TrackID,
+				"title":    ls.Title,
+				"source":   ls.Source,
+				"last_at":  at,
+			},
+			"$max": bson.M{"peak": ls.Listeners},
+			"$inc": bson.M{"samples": 1, "listener_sum": ls.Listeners},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	day := ListenDayKey(at)
+	_, err = s.listenDays.UpdateOne(
+		ctx,
+		bson.M{"_id": listenDayID(ls.ChatID, day)},
+		bson.M{
+			"$set": bson.M{"chat_id": ls.ChatID, "day": day},
+			"$max": bson.M{"peak": ls.Listeners},
+			"$inc": bson.M{
+				"minutes":                    ls.listenerMinutes(),
+				"hours." + listenHourKey(at): ls.listenerMinutes(),
+			},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) TopTrackListens(
+	ctx context.Context,
+	chatID int64,
+	limit int,
+) ([]TrackListens, error) {
+	cursor, err := s.trackListens.Find(
+		ctx,
+		bson.M{"chat_id": chatID},
+		options.Find().
+			SetSort(bson.D{{Key: "listener_sum", Value: -1}}).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []TrackListens
+	if err := cursor.All(ctx, &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (s *mongoStore) ListenDays(
+	ctx context.Context,
+	chatID int64,
+	since string,
+) ([]ListenDay, error) {
+	cursor, err := s.listenDays.Find(
+		ctx,
+		bson.M{"chat_id": chatID, "day": bson.M{"$gte": since}},
+		options.Find().SetSort(bson.D{{Key: "day", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []ListenDay
+	if err := cursor.All(ctx, &days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func (s *mongoStore) ListenTotals(ctx context.Context, since string) (*ListenTotals, error) {
+	cursor, err := s.listenDays.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"day": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"minutes": bson.M{"$sum": "$minutes"},
+			"peak":    bson.M{"$max": "$peak"},
+			"chats":   bson.M{"$addToSet": "$chat_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"minutes": 1,
+			"peak":    1,
+			"chats":   bson.M{"$size": "$chats"},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []ListenTotals
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+	if len(totals) == 0 {
+		return &ListenTotals{}, nil
+	}
+	return &totals[0], nil
+}
+
+func (s *mongoStore) AddPlay(ctx context.Context, p *PlayEntry) error {
+	_, err := s.playHistory.InsertOne(ctx, p)
+	return err
+}
+
+func playHistoryMatch(chatID int64, since time.Time) bson.M {
+	match := bson.M{"started_at": bson.M{"$gte": since}}
+	if chatID != 0 {
+		match["chat_id"] = chatID
+	}
+	return match
+}
+
+func (s *mongoStore) TopPlayedTracks(
+	ctx context.Context,
+	chatID int64,
+	since time.Time,
+	limit int,
+) ([]TrackPlays, error) {
+	cursor, err := s.playHistory.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: playHistoryMatch(chatID, since)}},
+		{{Key: "$sort", Value: bson.D{{Key: "started_at", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$track_id",
+			"title":  bson.M{"$last": "$title"},
+			"source": bson.M{"$last": "$source"},
+			"plays":  bson.M{"$sum": 1},
+			"skips":  bson.M{"$sum": bson.M{"$cond": bson.A{"$skipped", 1, 0}}},
+			"played": bson.M{"$sum": "$played"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "plays", Value: -1}, {Key: "played", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []TrackPlays
+	if err := cursor.All(ctx, &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (s *mongoStore) TopRequesters(
+	ctx context.Context,
+	chatID int64,
+	since time.Time,
+	limit int,
+) ([]RequesterPlays, error) {
+	match := playHistoryMatch(chatID, since)
+	match["requester_id"] = bson.M{"$gt": 0}
+
+	cursor, err := s.playHistory.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$requester_id",
+			"plays":  bson.M{"$sum": 1},
+			"played": bson.M{"$sum": "$played"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "plays", Value: -1}, {Key: "played", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var users []RequesterPlays
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (s *mongoStore) ActivePlayers(
+	ctx context.Context,
+	since time.Time,
+) ([]int64, []int64, error) {
+	var rooms, requesters []int64
+	filter := bson.M{"started_at": bson.M{"$gte": since}}
+	if err := s.playHistory.Distinct(ctx, "chat_id", filter).Decode(&rooms); err != nil {
+		return nil, nil, err
+	}
+	filter["requester_id"] = bson.M{"$gt": 0}
+	if err := s.playHistory.Distinct(ctx, "requester_id", filter).Decode(&requesters); err != nil {
+		return nil, nil, err
+	}
+	chats, users := splitActivePlayers(rooms, requesters)
+	return chats, users, nil
+}
+
+func (s *mongoStore) SaveBroadcastJob(ctx context.Context, j *BroadcastJob) error {
+	_, err := s.broadcasts.ReplaceOne(
+		ctx,
+		bson.M{"_id": j.ID},
+		j,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) GetBroadcastJob(ctx context.Context, id string) (*BroadcastJob, error) {
+	var job BroadcastJob
+	err := s.broadcasts.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *mongoStore) AllBroadcastJobs(ctx context.Context) ([]*BroadcastJob, error) {
+	cursor, err := s.broadcasts.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*BroadcastJob
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *mongoStore) DeleteBroadcastJob(ctx context.Context, id string) error {
+	_, err := s.broadcasts.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (s *mongoStore) GetSpotifyMatch(ctx context.Context, spotifyID string) (*SpotifyMatch, error) {
+	var m SpotifyMatch
+	err := s.spotify.FindOne(ctx, bson.M{"_id": spotifyID}).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *mongoStore) SaveSpotifyMatch(ctx context.Context, m *SpotifyMatch) error {
+	_, err := s.spotify.ReplaceOne(
+		ctx,
+		bson.M{"_id": m.SpotifyID},
+		m,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+type schemaDoc struct {
+	Version   int       `bson:"version"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func (s *mongoStore) SchemaVersion(ctx context.Context) (int, error) {
+	var doc schemaDoc
+	err := s.schema.FindOne(ctx, bson.M{"_id": "schema_version"}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return doc.Version, err
+}
+
+func (s *mongoStore) SetSchemaVersion(ctx context.Context, version int) error {
+	_, err := s.schema.UpdateOne(
+		ctx,
+		bson.M{"_id": "schema_version"},
+		bson.M{"$set": schemaDoc{Version: version, UpdatedAt: time.Now()}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+// AcquireLock relies on the unique _id: when the lock is held by someone
+// else the filter does not match and the upsert fails with a duplicate key.
+func (s *mongoStore) AcquireLock(
+	ctx context.Context,
+	name, owner string,
+	ttl time.Duration,
+) (bool, error) {
+	now := time.Now()
+	_, err := s.locks.UpdateOne(
+		ctx,
+		bson.M{
+			"_id": name,
+			"$or": bson.A{
+				bson.M{"owner": owner},
+				bson.M{"expires_at": bson.M{"$lt": now}},
+			},
+		},
+		bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *mongoStore) ReleaseLock(ctx context.Context, name, owner string) error {
+	_, err := s.locks.DeleteOne(ctx, bson.M{"_id": name, "owner": owner})
+	return err
+}
+
+func (s *mongoStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx, nil)
+}
+
+func (s *mongoStore) Close(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
@@ -0,0 +1,164 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"main/internal/server"
+)
+
+const checkTimeout = 5 * time.Second
+
+type check struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Heartbeat is a liveness signal fed by real work, such as a loop of the
+// bot. /healthz reports the process as stalled when a heartbeat has not
+// beaten within its timeout.
+type Heartbeat struct {
+	name    string
+	timeout time.Duration
+	last    atomic.Int64
+}
+
+var (
+	checks   []check
+	checksMu sync.RWMutex
+
+	heartbeats   []*Heartbeat
+	heartbeatsMu sync.RWMutex
+
+	started = time.Now()
+)
+
+// NewHeartbeat registers a heartbeat that must beat at least once every
+// timeout. It counts as fresh from the time it is created.
+func NewHeartbeat(name string, timeout time.Duration) *Heartbeat {
+	h := &Heartbeat{name: name, timeout: timeout}
+	h.Beat()
+
+	heartbeatsMu.Lock()
+	heartbeats = append(heartbeats, h)
+	heartbeatsMu.Unlock()
+	return h
+}
+
+// Beat marks the work behind h as alive.
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Register adds a readiness check. It is run on every /readyz request and
+// the component is reported as failing when it returns an error.
+func Register(name string, fn func(ctx context.Context) error) {
+	checksMu.Lock()
+	checks = append(checks, check{name: name, fn: fn})
+	checksMu.Unlock()
+}
+
+// Init registers /healthz and /readyz on the shared HTTP server.
+func Init() {
+	server.HandleFunc("GET /healthz", livenessHandler)
+	server.HandleFunc("GET /readyz", readinessHandler)
+}
+
+func livenessHandler(w http.ResponseWriter, _ *http.Request) {
+	heartbeatsMu.RLock()
+	list := append([]*Heartbeat(nil), heartbeats...)
+	heartbeatsMu.RUnlock()
+
+	beats := make(map[string]string, len(list))
+	stalled := []string{}
+	for _, h := range list {
+		since := time.Since(time.Unix(0, h.last.Load()))
+		beats[h.name] = since.Round(time.Millisecond).String()
+		if since > h.timeout {
+			stalled = append(stalled, h.name)
+		}
+	}
+
+	status, code := "ok", http.StatusOK
+	if len(stalled) > 0 {
+		status, code = "stalled", http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, code, map[string]any{
+		"status":         status,
+		"uptime_seconds": int(time.Since(started).Seconds()),
+		"heartbeats":     beats,
+		"stalled":        stalled,
+		"goroutines":     runtime.NumGoroutine(),
+	})
+}
+
+func readinessHandler(w http.ResponseWriter, r *http.Request) {
+	checksMu.RLock()
+	list := append([]check(nil), checks...)
+	checksMu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		failing = make(map[string]string)
+	)
+	for _, c := range list {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.fn(ctx); err != nil {
+				mu.Lock()
+				failing[c.name] = err.Error()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	status, code := "ok", http.StatusOK
+	if len(failing) > 0 {
+		status, code = "fail", http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, code, map[string]any{
+		"status":  status,
+		"checks":  len(list),
+		"failing": failing,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -23,60 +23,188 @@ package locales
 import (
 	"embed"
 	"fmt"
-	"path"
+	"io/fs"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
-	"github.com/Laky-64/gologging"
 	"gopkg.in/yaml.v3"
 
 	"main/internal/config"
 )
 
+// Locale files are named <lang>.yml; a language may be split over extra
+// files named <lang>.<part>.yml (for example en.help.yml).
+//
 //go:embed *.yml
 var locales embed.FS
 
-var loadedLocales = make(map[string]map[string]string)
+// message is one locale entry: either plain text or plural forms keyed by
+// CLDR category (zero, one, two, few, many, other).
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+var (
+	logger = config.GetLogger(config.DefaultLogger)
+
+	mu       sync.RWMutex
+	embedded = make(map[string]map[string]message)
+	// packs are locale files uploaded at runtime; they override and extend
+	// the embedded locale of the same language.
+	packs = make(map[string]map[string]message)
+
+	missingKeys sync.Map
+)
+
+var placeholderRegex = regexp.MustCompile(`\{([a-z_][a-z0-9_]*)\}`)
 
 type Arg map[string]any
 
 func init() {
-	files, err := locales.ReadDir(".")
+	files, err := fs.Glob(locales, "*.yml")
 	if err != nil {
-		gologging.Fatal("Failed to read embedded locales:", err)
+		logger.FatalF("Failed to read embedded locales: %v", err)
 		return
 	}
-	for _, f := range files {
-		if f.IsDir() {
+	for _, name := range files {
+		lang := strings.SplitN(name, ".", 2)[0]
+		file, err := locales.ReadFile(name)
+		if err != nil {
+			logger.FatalF("Failed to read locale file %s: %v", name, err)
 			continue
 		}
-		lang := f.Name()[:len(f.Name())-len(path.Ext(f.Name()))]
-		file, err := locales.ReadFile(f.Name())
+		msgs, err := parseLocale(file)
 		if err != nil {
-			gologging.Fatal("Failed to read locale file:", f.Name(), err)
+			logger.FatalF("Failed to parse locale file %s: %v", name, err)
 			continue
 		}
-		var locale map[string]string
-		if err := yaml.Unmarshal(file, &locale); err != nil {
-			gologging.Fatal("Failed to unmarshal locale file:", f.Name(), err)
-			continue
+
+		if embedded[lang] == nil {
+			embedded[lang] = make(map[string]message, len(msgs))
+		}
+		for key, msg := range msgs {
+			if _, dup := embedded[lang][key]; dup {
+				logger.FatalF("Duplicate locale key %q in %s", key, name)
+			}
+			embedded[lang][key] = msg
 		}
-		loadedLocales[lang] = locale
 	}
-	if _, ok := loadedLocales[config.DefaultLang]; !ok {
-		gologging.Fatal("Default language not found:", config.DefaultLang)
+	if _, ok := embedded[config.DefaultLang]; !ok {
+		logger.FatalF("Default language not found: %s", config.DefaultLang)
 	}
-	gologging.Info("Loaded", len(loadedLocales), "locales.")
+	logger.InfoF("Loaded %d locales.", len(embedded))
 }
 
-func Get(lang, key string, values Arg) string {
-	if _, ok := loadedLocales[lang]; !ok {
-		lang = config.DefaultLang
+// parseLocale decodes a locale file. Values are either strings or maps of
+// plural forms, which must include "other".
+func parseLocale(data []byte) (map[string]message, error) {
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	msgs := make(map[string]message, len(raw))
+	for key, node := range raw {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			msgs[key] = message{text: node.Value}
+
+		case yaml.MappingNode:
+			var forms map[string]string
+			if err := node.Decode(&forms); err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			for form := range forms {
+				if !isPluralCategory(form) {
+					return nil, fmt.Errorf(
+						"key %q: unknown plural form %q",
+						key,
+						form,
+					)
+				}
+			}
+			if _, ok := forms[pluralOther]; !ok {
+				return nil, fmt.Errorf("key %q: plural form %q is required", key, pluralOther)
+			}
+			msgs[key] = message{plural: forms}
+
+		default:
+			return nil, fmt.Errorf("key %q: value must be a string or plural forms", key)
+		}
+	}
+	return msgs, nil
+}
+
+// FallbackChain returns the languages tried for lang, most specific first:
+// pt-BR falls back to pt and then to the default language.
+func FallbackChain(lang string) []string {
+	var chain []string
+	add := func(l string) {
+		for _, c := range chain {
+			if c == l {
+				return
+			}
+		}
+		chain = append(chain, l)
 	}
 
-	val, ok := loadedLocales[lang][key]
+	for l := lang; l != ""; {
+		add(l)
+		i := strings.LastIndexAny(l, "-_")
+		if i < 0 {
+			break
+		}
+		l = l[:i]
+	}
+	add(config.DefaultLang)
+	return chain
+}
+
+// lookup finds key along lang's fallback chain and returns the message and
+// the language it was found in.
+func lookup(lang, key string) (message, string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, l := range FallbackChain(lang) {
+		if msg, ok := packs[l][key]; ok {
+			return msg, l, true
+		}
+		if msg, ok := embedded[l][key]; ok {
+			return msg, l, true
+		}
+	}
+	return message{}, "", false
+}
+
+// Exists reports whether key resolves for lang, including fallbacks.
+func Exists(lang, key string) bool {
+	_, _, ok := lookup(lang, key)
+	return ok
+}
+
+// Get returns the text of key in lang with {name} placeholders replaced by
+// values. Plural messages pick their form from values["count"].
+func Get(lang, key string, values Arg) string {
+	msg, found, ok := lookup(lang, key)
 	if !ok {
-		val = loadedLocales[config.DefaultLang][key]
+		if _, seen := missingKeys.LoadOrStore(key, struct{}{}); !seen {
+			logger.WarnF("Locale key %q is missing in every language", key)
+		}
+		return ""
+	}
+
+	val := msg.text
+	if msg.plural != nil {
+		val = msg.plural[pluralOther]
+		if n, ok := pluralCount(values); ok {
+			if form, ok := msg.plural[PluralForm(found, n)]; ok {
+				val = form
+			}
+		}
 	}
 
 	if values == nil {
@@ -95,10 +223,18 @@ func Get(lang, key string, values Arg) string {
 }
 
 func GetAvailableLanguages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
 	var langs []string
-	for lang := range loadedLocales {
+	for lang := range embedded {
 		langs = append(langs, lang)
 	}
+	for lang := range packs {
+		if _, ok := embedded[lang]; !ok {
+			langs = append(langs, lang)
+		}
+	}
 	sort.Strings(langs)
 	return langs
 }
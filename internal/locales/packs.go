@@ -0,0 +1,98 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package locales
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+var langCodeRegex = regexp.MustCompile(`^[a-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// ValidLangCode reports whether lang looks like a language tag such as
+// en, pt-BR or zh_Hant.
+func ValidLangCode(lang string) bool {
+	return langCodeRegex.MatchString(lang)
+}
+
+// ParsePack checks a locale pack and returns its number of keys.
+func ParsePack(data []byte) (int, error) {
+	msgs, err := parseLocale(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(msgs) == 0 {
+		return 0, fmt.Errorf("locale pack is empty")
+	}
+	return len(msgs), nil
+}
+
+// LoadPack installs a runtime locale pack for lang, replacing any pack
+// loaded before for the same language.
+func LoadPack(lang string, data []byte) error {
+	if !ValidLangCode(lang) {
+		return fmt.Errorf("invalid language code %q", lang)
+	}
+	msgs, err := parseLocale(data)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	packs[lang] = msgs
+	mu.Unlock()
+	return nil
+}
+
+// RemovePack drops the runtime pack of lang. Embedded strings of the same
+// language stay available.
+func RemovePack(lang string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := packs[lang]; !ok {
+		return false
+	}
+	delete(packs, lang)
+	return true
+}
+
+// Packs returns the languages that have a runtime pack loaded.
+func Packs() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	langs := make([]string, 0, len(packs))
+	for lang := range packs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsEmbedded reports whether lang ships with the bot.
+func IsEmbedded(lang string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := embedded[lang]
+	return ok
+}
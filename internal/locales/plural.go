@@ -0,0 +1,156 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package locales
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CLDR plural categories.
+const (
+	pluralZero  = "zero"
+	pluralOne   = "one"
+	pluralTwo   = "two"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+func isPluralCategory(form string) bool {
+	switch form {
+	case pluralZero, pluralOne, pluralTwo, pluralFew, pluralMany, pluralOther:
+		return true
+	}
+	return false
+}
+
+// pluralRules are the CLDR cardinal rules for whole numbers, keyed by
+// primary language subtag. Languages not listed use the English rule.
+var pluralRules = map[string]func(n int) string{
+	"ar": pluralArabic,
+	"hi": pluralZeroOne,
+	"bn": pluralZeroOne,
+	"fr": pluralZeroOne,
+	"pt": pluralZeroOne,
+	"ru": pluralSlavic,
+	"uk": pluralSlavic,
+	"be": pluralSlavic,
+	"pl": pluralPolish,
+	"id": pluralNone,
+	"ja": pluralNone,
+	"ko": pluralNone,
+	"zh": pluralNone,
+	"vi": pluralNone,
+	"th": pluralNone,
+	"ms": pluralNone,
+}
+
+// PluralForm returns the plural category of n in lang.
+func PluralForm(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	base := strings.ToLower(lang)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if rule, ok := pluralRules[base]; ok {
+		return rule(n)
+	}
+	return pluralEnglish(n)
+}
+
+func pluralEnglish(n int) string {
+	if n == 1 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+func pluralZeroOne(n int) string {
+	if n == 0 || n == 1 {
+		return pluralOne
+	}
+	return pluralOther
+}
+
+func pluralNone(int) string {
+	return pluralOther
+}
+
+func pluralArabic(n int) string {
+	switch mod := n % 100; {
+	case n == 0:
+		return pluralZero
+	case n == 1:
+		return pluralOne
+	case n == 2:
+		return pluralTwo
+	case mod >= 3 && mod <= 10:
+		return pluralFew
+	case mod >= 11 && mod <= 99:
+		return pluralMany
+	}
+	return pluralOther
+}
+
+func pluralSlavic(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return pluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return pluralFew
+	}
+	return pluralMany
+}
+
+func pluralPolish(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case n == 1:
+		return pluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return pluralFew
+	}
+	return pluralMany
+}
+
+// pluralCount reads the "count" argument used to pick plural forms.
+func pluralCount(values Arg) (int, bool) {
+	switch v := values["count"].(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,145 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package locales
+
+import (
+	"slices"
+	"sort"
+
+	"main/internal/config"
+)
+
+// Report lists the problems of one translation compared to the default
+// language. The default language itself is checked against the code by
+// cmd/localekeys, since the source is not available at runtime.
+type Report struct {
+	Lang string
+	// Missing keys fall back to a parent or the default language.
+	Missing []string
+	// Unused keys do not exist in the default language, so no code asks
+	// for them; usually typos or leftovers of renamed keys.
+	Unused []string
+	// Placeholders are keys using {names} the default text does not have,
+	// which are never filled in.
+	Placeholders []string
+}
+
+func (r Report) OK() bool {
+	return len(r.Missing) == 0 && len(r.Unused) == 0 && len(r.Placeholders) == 0
+}
+
+// Validate compares every other language, including uploaded packs, with
+// the default language. With only the default language loaded there is
+// nothing to compare and it returns no reports.
+func Validate() []Report {
+	var reports []Report
+	for _, lang := range GetAvailableLanguages() {
+		if lang == config.DefaultLang {
+			continue
+		}
+		reports = append(reports, ValidateLang(lang))
+	}
+	return reports
+}
+
+// ValidateLang compares lang, including its runtime pack, with the default
+// language.
+func ValidateLang(lang string) Report {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	ref := merged(config.DefaultLang)
+	own := merged(lang)
+	r := Report{Lang: lang}
+
+	// Keys of parent languages (pt for pt-BR) count as translated.
+	var chain []map[string]message
+	for _, l := range FallbackChain(lang) {
+		if l != config.DefaultLang {
+			chain = append(chain, merged(l))
+		}
+	}
+	for key := range ref {
+		found := false
+		for _, msgs := range chain {
+			if _, ok := msgs[key]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.Missing = append(r.Missing, key)
+		}
+	}
+
+	for key, msg := range own {
+		refMsg, ok := ref[key]
+		if !ok {
+			r.Unused = append(r.Unused, key)
+			continue
+		}
+		allowed := placeholders(refMsg)
+		for _, p := range placeholders(msg) {
+			if !slices.Contains(allowed, p) {
+				r.Placeholders = append(r.Placeholders, key)
+				break
+			}
+		}
+	}
+
+	sort.Strings(r.Missing)
+	sort.Strings(r.Unused)
+	sort.Strings(r.Placeholders)
+	return r
+}
+
+// merged returns the embedded messages of lang overlaid with its pack.
+// Callers hold mu.
+func merged(lang string) map[string]message {
+	if packs[lang] == nil {
+		return embedded[lang]
+	}
+	out := make(map[string]message, len(embedded[lang])+len(packs[lang]))
+	for k, v := range embedded[lang] {
+		out[k] = v
+	}
+	for k, v := range packs[lang] {
+		out[k] = v
+	}
+	return out
+}
+
+func placeholders(msg message) []string {
+	texts := []string{msg.text}
+	for _, t := range msg.plural {
+		texts = append(texts, t)
+	}
+
+	var names []string
+	for _, t := range texts {
+		for _, m := range placeholderRegex.FindAllStringSubmatch(t, -1) {
+			if !slices.Contains(names, m[1]) {
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
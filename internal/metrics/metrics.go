@@ -0,0 +1,292 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+// Package metrics is a minimal Prometheus text exposition registry. Packages
+// declare their metrics as package variables and the collected values are
+// served on /metrics.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"main/internal/server"
+)
+
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	registry   []collector
+	registryMu sync.Mutex
+)
+
+// DefaultBuckets suits operations taking from milliseconds to a minute.
+var DefaultBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+func register(c collector) {
+	registryMu.Lock()
+	registry = append(registry, c)
+	registryMu.Unlock()
+}
+
+// Init registers the /metrics route on the shared HTTP server.
+func Init() {
+	server.HandleFunc("GET /metrics", handler)
+}
+
+func handler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	registryMu.Lock()
+	collectors := append([]collector(nil), registry...)
+	registryMu.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+	writeRuntime(w)
+}
+
+// Counter is a monotonically increasing value, optionally split by one label.
+type Counter struct {
+	name, help, label string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func NewCounter(name, help string, label ...string) *Counter {
+	c := &Counter{name: name, help: help, values: make(map[string]float64)}
+	if len(label) > 0 {
+		c.label = label[0]
+	}
+	register(c)
+	return c
+}
+
+// Inc adds one, labelValue is required when the counter has a label.
+func (c *Counter) Inc(labelValue ...string) {
+	c.Add(1, labelValue...)
+}
+
+func (c *Counter) Add(v float64, labelValue ...string) {
+	key := ""
+	if len(labelValue) > 0 {
+		key = labelValue[0]
+	}
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	if c.label == "" {
+		writeSample(w, c.name, "", c.values[""])
+		return
+	}
+	writeLabeled(w, c.name, c.label, c.values)
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	name, help string
+
+	mu    sync.Mutex
+	value float64
+}
+
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	register(g)
+	return g
+}
+
+func (g *Gauge) Inc() { g.Add(1) }
+func (g *Gauge) Dec() { g.Add(-1) }
+
+func (g *Gauge) Add(v float64) {
+	g.mu.Lock()
+	g.value += v
+	g.mu.Unlock()
+}
+
+func (g *Gauge) Set(v float64) {
+	g.mu.Lock()
+	g.value = v
+	g.mu.Unlock()
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	writeHeader(w, g.name, g.help, "gauge")
+	writeSample(w, g.name, "", g.value)
+}
+
+// funcMetric reads its values on every scrape, for state that is already
+// tracked elsewhere.
+type funcMetric struct {
+	name, help, kind, label string
+	fn                      func() map[string]float64
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) {
+	register(&funcMetric{
+		name: name, help: help, kind: "gauge",
+		fn: func() map[string]float64 { return map[string]float64{"": fn()} },
+	})
+}
+
+// NewGaugeVecFunc reports one sample per label value returned by fn.
+func NewGaugeVecFunc(name, help, label string, fn func() map[string]float64) {
+	register(&funcMetric{name: name, help: help, kind: "gauge", label: label, fn: fn})
+}
+
+func NewCounterFunc(name, help string, fn func() float64) {
+	register(&funcMetric{
+		name: name, help: help, kind: "counter",
+		fn: func() map[string]float64 { return map[string]float64{"": fn()} },
+	})
+}
+
+func (f *funcMetric) write(w io.Writer) {
+	values := f.fn()
+	writeHeader(w, f.name, f.help, f.kind)
+	if f.label == "" {
+		writeSample(w, f.name, "", values[""])
+		return
+	}
+	writeLabeled(w, f.name, f.label, values)
+}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	name, help string
+	buckets    []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+	register(h)
+	return h
+}
+
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	for i, b := range h.buckets {
+		writeSample(w, h.name+"_bucket", `le="`+formatFloat(b)+`"`, float64(h.counts[i]))
+	}
+	writeSample(w, h.name+"_bucket", `le="+Inf"`, float64(h.count))
+	writeSample(w, h.name+"_sum", "", h.sum)
+	writeSample(w, h.name+"_count", "", float64(h.count))
+}
+
+func writeRuntime(w io.Writer) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	gauges := []struct {
+		name, help string
+		value      float64
+	}{
+		{"go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine())},
+		{"go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(ms.Alloc)},
+		{"go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(ms.HeapInuse)},
+		{"go_memstats_sys_bytes", "Number of bytes obtained from the system.", float64(ms.Sys)},
+	}
+	for _, g := range gauges {
+		writeHeader(w, g.name, g.help, "gauge")
+		writeSample(w, g.name, "", g.value)
+	}
+
+	writeHeader(w, "go_gc_cycles_total", "Number of completed GC cycles.", "counter")
+	writeSample(w, "go_gc_cycles_total", "", float64(ms.NumGC))
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeSample(w io.Writer, name, labels string, v float64) {
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(v))
+}
+
+func writeLabeled(w io.Writer, name, label string, values map[string]float64) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeSample(w, name, label+`="`+escapeLabel(k)+`"`, values[k])
+	}
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,124 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"html"
+	"strings"
+
+	"github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/api"
+	"main/internal/config"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+func apiKeyHandler(m *telegram.NewMessage) error {
+	chatID := m.ChannelID()
+
+	if !m.IsPrivate() {
+		m.Reply(F(chatID, "apikey_private_only"))
+		return telegram.ErrEndGroup
+	}
+
+	if !config.APIEnabled || config.HTTPPort <= 0 {
+		m.Reply(F(chatID, "apikey_disabled"))
+		return telegram.ErrEndGroup
+	}
+
+	args := strings.Fields(m.Args())
+	if len(args) == 0 {
+		m.Reply(F(chatID, "apikey_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	name := strings.Join(args[1:], " ")
+
+	switch strings.ToLower(args[0]) {
+	case "new", "create", "add":
+		if name == "" {
+			break
+		}
+		key, err := api.NewKey(name, m.SenderID())
+		if err != nil {
+			m.Reply(F(chatID, "apikey_fail", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+			return telegram.ErrEndGroup
+		}
+		m.Reply(F(chatID, "apikey_created", locales.Arg{
+			"name": html.EscapeString(name),
+			"key":  key,
+		}))
+		return telegram.ErrEndGroup
+
+	case "list":
+		keys, err := database.GetAPIKeys()
+		if err != nil {
+			m.Reply(F(chatID, "apikey_fail", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+			return telegram.ErrEndGroup
+		}
+		if len(keys) == 0 {
+			m.Reply(F(chatID, "apikey_list_empty"))
+			return telegram.ErrEndGroup
+		}
+
+		var b strings.Builder
+		b.WriteString(F(chatID, "apikey_list_header"))
+		for _, k := range keys {
+			b.WriteString("\n")
+			b.WriteString(F(chatID, "apikey_list_item", locales.Arg{
+				"name": html.EscapeString(k.Name),
+				"date": k.CreatedAt.Format("2006-01-02"),
+			}))
+		}
+		m.Reply(b.String())
+		return telegram.ErrEndGroup
+
+	case "revoke", "delete", "del", "rm":
+		if name == "" {
+			break
+		}
+		found, err := database.DeleteAPIKey(name)
+		if err != nil {
+			m.Reply(F(chatID, "apikey_fail", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+			return telegram.ErrEndGroup
+		}
+		key := utils.IfElse(found, "apikey_revoked", "apikey_not_found")
+		m.Reply(F(chatID, key, locales.Arg{
+			"name": html.EscapeString(name),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	m.Reply(F(chatID, "apikey_usage", locales.Arg{
+		"cmd": getCommand(m),
+	}))
+	return telegram.ErrEndGroup
+}
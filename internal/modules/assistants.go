@@ -0,0 +1,300 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+func assistantsHandler(m *telegram.NewMessage) error {
+	chatID := m.ChannelID()
+	args := strings.Fields(m.Args())
+
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "move":
+			return assistantsMove(m, args[1:])
+		case "rebalance":
+			return assistantsRebalance(m)
+		default:
+			m.Reply(F(chatID, "assistants_usage", locales.Arg{
+				"cmd": getCommand(m),
+			}))
+			return telegram.ErrEndGroup
+		}
+	}
+
+	counts := database.AssistantChatCounts()
+
+	var b strings.Builder
+	b.WriteString(F(chatID, "assistants_header", locales.Arg{
+		"count": core.Assistants.Count(),
+	}))
+	for _, l := range core.Assistants.Loads() {
+		a, err := core.Assistants.Get(l.Index)
+		if err != nil {
+			continue
+		}
+
+		status := F(chatID, "assistants_status_up")
+		if !l.Healthy {
+			reason, _ := a.HealthStatus()
+			status = F(chatID, "assistants_status_down", locales.Arg{
+				"reason": html.EscapeString(reason),
+			})
+		}
+
+		var chats int64
+		if l.Index < len(counts) {
+			chats = counts[l.Index]
+		}
+
+		b.WriteString("\n\n")
+		b.WriteString(F(chatID, "assistants_item", locales.Arg{
+			"index":  l.Index,
+			"name":   html.EscapeString(a.User.FirstName),
+			"id":     a.User.ID,
+			"status": status,
+			"calls":  l.Calls,
+			"chats":  chats,
+			"cpu":    fmt.Sprintf("%.1f", l.CPU),
+			"floods": l.FloodWaits,
+			"joins":  fmt.Sprintf("%.0f", l.JoinRate*100),
+			"score":  fmt.Sprintf("%.2f", l.Score),
+		}))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(F(chatID, "assistants_footer", locales.Arg{
+		"cmd": getCommand(m),
+	}))
+
+	m.Reply(b.String())
+	return telegram.ErrEndGroup
+}
+
+// assistantsMove handles "move <chat_id> <index>", or "move <index>" for
+// the current group.
+func assistantsMove(m *telegram.NewMessage, args []string) error {
+	chatID := m.ChannelID()
+
+	target := chatID
+	if len(args) == 2 {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			target = 0
+		} else {
+			target = id
+		}
+		args = args[1:]
+	} else if m.IsPrivate() {
+		target = 0
+	}
+
+	if len(args) != 1 || target == 0 {
+		m.Reply(F(chatID, "assistants_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return telegram.ErrEndGroup
+	}
+	idx, err := strconv.Atoi(args[0])
+	if err != nil {
+		m.Reply(F(chatID, "assistants_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	ass, err := core.Assistants.Move(target, idx)
+	if err != nil {
+		m.Reply(F(chatID, "assistants_move_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	m.Reply(F(chatID, "assistants_moved", locales.Arg{
+		"chat_id": target,
+		"index":   ass.Index() + 1,
+		"name":    html.EscapeString(ass.User.FirstName),
+	}))
+	return telegram.ErrEndGroup
+}
+
+func assistantsRebalance(m *telegram.NewMessage) error {
+	chatID := m.ChannelID()
+	mystic, _ := m.Reply(F(chatID, "assistants_rebalancing"))
+
+	moved, err := rebalanceAssistants()
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "assistants_move_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	utils.EOR(mystic, F(chatID, "assistants_rebalanced", locales.Arg{
+		"count": moved,
+	}))
+	return telegram.ErrEndGroup
+}
+
+// rebalanceAssistants spreads the stored assignments over the current
+// assistants and moves the cached chats along. It returns how many of
+// those changed assistant.
+func rebalanceAssistants() (int, error) {
+	if err := database.RebalanceAssistantIndexes(core.Assistants.Count()); err != nil {
+		return 0, err
+	}
+	return core.Assistants.Resync(), nil
+}
+
+var sessionTypes = []string{"pyrogram", "pyro", "telethon", "gogram"}
+
+// addAssistantHandler handles /addassistant <session> [type]. The session
+// is stored encrypted and the assistant starts on every boot from then on.
+func addAssistantHandler(m *telegram.NewMessage) error {
+	chatID := m.ChannelID()
+	args := strings.Fields(m.Args())
+
+	// The message holds a login session, never leave it in the chat.
+	if len(args) > 0 {
+		m.Delete()
+	}
+	if !m.IsPrivate() {
+		m.Respond(F(chatID, "addassistant_private_only"))
+		return telegram.ErrEndGroup
+	}
+	if config.SessionSecret == "" {
+		m.Respond(F(chatID, "addassistant_no_secret"))
+		return telegram.ErrEndGroup
+	}
+
+	sessionType := strings.ToLower(config.SessionType)
+	if len(args) == 2 {
+		sessionType = strings.ToLower(args[1])
+	}
+	if len(args) == 0 || len(args) > 2 || !slices.Contains(sessionTypes, sessionType) {
+		m.Respond(F(chatID, "addassistant_usage", locales.Arg{
+			"cmd":     getCommand(m),
+			"default": config.SessionType,
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	mystic, _ := m.Respond(F(chatID, "addassistant_starting"))
+
+	a, err := core.Assistants.Add(args[0], sessionType)
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "addassistant_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	text := F(chatID, "addassistant_done", locales.Arg{
+		"index": a.Index() + 1,
+		"name":  html.EscapeString(a.User.FirstName),
+		"id":    a.User.ID,
+	})
+	if err := database.SaveAssistantSession(a.User.ID, args[0], sessionType, m.SenderID()); err != nil {
+		text += "\n" + F(chatID, "addassistant_not_saved", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		})
+	}
+
+	if moved, err := rebalanceAssistants(); err != nil {
+		logger.ErrorF("Rebalance after adding assistant=%d failed: %v", a.Index()+1, err)
+	} else {
+		text += "\n\n" + F(chatID, "assistants_rebalanced", locales.Arg{
+			"count": moved,
+		})
+	}
+
+	utils.EOR(mystic, text)
+	return telegram.ErrEndGroup
+}
+
+// removeAssistantHandler handles /removeassistant <index>. The chats of
+// the assistant move to the others before it is stopped.
+func removeAssistantHandler(m *telegram.NewMessage) error {
+	chatID := m.ChannelID()
+	args := strings.Fields(m.Args())
+
+	if len(args) != 1 {
+		m.Reply(F(chatID, "removeassistant_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return telegram.ErrEndGroup
+	}
+	idx, err := strconv.Atoi(args[0])
+	if err != nil {
+		m.Reply(F(chatID, "removeassistant_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	mystic, _ := m.Reply(F(chatID, "removeassistant_draining", locales.Arg{
+		"index": idx,
+	}))
+
+	a, moved, err := core.Assistants.Remove(idx)
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "removeassistant_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return telegram.ErrEndGroup
+	}
+
+	text := F(chatID, "removeassistant_done", locales.Arg{
+		"index": idx,
+		"name":  html.EscapeString(a.User.FirstName),
+		"count": moved,
+	})
+	deleted, err := database.DeleteAssistantSession(a.User.ID)
+	switch {
+	case err != nil:
+		text += "\n" + F(chatID, "removeassistant_not_deleted", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		})
+	case !deleted:
+		text += "\n" + F(chatID, "removeassistant_from_env")
+	}
+
+	if _, err := rebalanceAssistants(); err != nil {
+		logger.ErrorF("Rebalance after removing assistant=%d failed: %v", idx, err)
+	}
+
+	utils.EOR(mystic, text)
+	return telegram.ErrEndGroup
+}
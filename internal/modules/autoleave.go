@@ -21,13 +21,11 @@
 package modules
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -43,24 +41,6 @@ var (
 	limit            = 50
 )
 
-func init() {
-	helpTexts["autoleave"] = fmt.Sprintf(
-		`<i>Automatically makes the assistant leave inactive or unnecessary chats every 10 minutes.</i>
-
-<u>Usage:</u>
-<b>/autoleave </b>— Shows current auto-leave status (enabled/disabled).  
-<b>/autoleave enable</b> — Enable auto-leave mode.  
-<b>/autoleave disable</b> — Disable auto-leave mode.
-
-<b>🧠 Details:</b>
-Once enabled, the bot checks all joined groups/channels every <b>10 minutes</b> and leaves up to <b>%d chats per cycle</b> that are not in the active room  list.
-
-<b>⚠️ Restrictions:</b>
-This command can only be used by <b>owners</b> or <b>sudo users</b>.`,
-		limit,
-	)
-}
-
 func autoLeaveHandler(m *tg.NewMessage) error {
 	args := strings.Fields(m.Text())
 	chatID := m.ChannelID()
@@ -167,7 +147,7 @@ func autoLeaveAssistant(
 
 		chatID, err := utils.GetPeerID(ass.Client, d.Peer)
 		if err != nil {
-			gologging.Error("[Autoleave] Peer error: " + err.Error())
+			logger.Error("[Autoleave] Peer error: " + err.Error())
 			return nil
 		}
 
@@ -181,7 +161,8 @@ func autoLeaveAssistant(
 
 		if err := ass.Client.LeaveChannel(chatID); err != nil {
 			if wait := tg.GetFloodWait(err); wait > 0 {
-				gologging.Error(
+				floodWaits.Inc("autoleave")
+				logger.Error(
 					"FloodWait detected (" + strconv.Itoa(
 						wait,
 					) + "s). Sleeping...",
@@ -195,17 +176,17 @@ func autoLeaveAssistant(
 				return nil
 			}
 
-			gologging.WarnF(
-				"AutoLeave (Assistant %d) failed to leave %d: %v",
-				ass.Index, chatID, err,
+			logger.WarnF(
+				"AutoLeave: assistant=%d failed to leave chat_id=%d: %v",
+				ass.Index(), chatID, err,
 			)
 			return nil
 		}
 
 		leaveCount++
-		gologging.InfoF(
-			"AutoLeave: Assistant %d left %d (%d/%d)",
-			ass.Index, chatID, leaveCount, limit,
+		logger.InfoF(
+			"AutoLeave: assistant=%d left chat_id=%d (%d/%d)",
+			ass.Index(), chatID, leaveCount, limit,
 		)
 
 		time.Sleep(3 * time.Second)
@@ -220,9 +201,9 @@ func autoLeaveAssistant(
 	})
 
 	if err != nil && err != tg.ErrStopIteration {
-		gologging.WarnF(
-			"AutoLeave: IterDialogs error (assistant %d): %v",
-			ass.Index, err,
+		logger.WarnF(
+			"AutoLeave: IterDialogs error assistant=%d: %v",
+			ass.Index(), err,
 		)
 	}
 }
@@ -0,0 +1,251 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+const (
+	restoreConfirmTTL = 10 * time.Minute
+	maxRestoreFileMB  = 50
+)
+
+// pendingRestore is the archive waiting for the owner to pick a restore
+// mode. There is only one owner, so one slot is enough.
+var (
+	pendingRestoreMu sync.Mutex
+	pendingRestore   *database.Backup
+	pendingRestoreAt time.Time
+)
+
+func backupHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+	mystic, _ := m.Reply(F(chatID, "backup_creating"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	b, err := database.CreateBackup(ctx)
+	if err == nil {
+		err = sendBackup(b)
+	}
+
+	if err != nil {
+		logger.ErrorF("Backup failed: %v", err)
+		utils.EOR(mystic, F(chatID, "backup_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	if chatID == config.OwnerID {
+		if mystic != nil {
+			mystic.Delete()
+		}
+	} else {
+		utils.EOR(mystic, F(chatID, "backup_sent_dm"))
+	}
+	return tg.ErrEndGroup
+}
+
+func sendBackup(b *database.Backup) error {
+	name := fmt.Sprintf("yukki-backup-%s.json.gz", b.CreatedAt.Format("20060102-150405"))
+	path := filepath.Join(os.TempDir(), name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	if err := database.WriteBackup(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	ownerID := config.OwnerID
+	_, err = core.Bot.SendMedia(ownerID, path, &tg.MediaOptions{
+		Caption: F(ownerID, "backup_caption", backupStatsArgs(b, locales.Arg{
+			"date": b.CreatedAt.Format("2006-01-02 15:04 MST"),
+		})),
+		ForceDocument: true,
+	})
+	return err
+}
+
+func restoreHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	if !m.IsReply() {
+		m.Reply(F(chatID, "restore_reply_required", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	reply, err := m.GetReplyMessage()
+	if err != nil || reply.Document() == nil {
+		m.Reply(F(chatID, "restore_reply_required", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	if reply.Document().Size > maxRestoreFileMB<<20 {
+		m.Reply(F(chatID, "restore_too_large", locales.Arg{
+			"max": maxRestoreFileMB,
+		}))
+		return tg.ErrEndGroup
+	}
+
+	mystic, _ := m.Reply(F(chatID, "restore_checking"))
+
+	var buf bytes.Buffer
+	if _, err := reply.Download(&tg.DownloadOptions{Buffer: &buf}); err != nil {
+		utils.EOR(mystic, F(chatID, "restore_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	b, err := database.ReadBackup(&buf)
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "restore_invalid", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	pendingRestoreMu.Lock()
+	pendingRestore = b
+	pendingRestoreAt = time.Now()
+	pendingRestoreMu.Unlock()
+
+	kb := tg.NewKeyboard().
+		AddRow(
+			tg.Button.Data(F(chatID, "RESTORE_MERGE_BTN"), "restore:merge"),
+			tg.Button.Data(F(chatID, "RESTORE_REPLACE_BTN"), "restore:replace"),
+		).
+		AddRow(
+			tg.Button.Data(F(chatID, "CLOSE_BTN"), "restore:cancel"),
+		)
+
+	text := F(chatID, "restore_preview", backupStatsArgs(b, locales.Arg{
+		"date":   b.CreatedAt.Format("2006-01-02 15:04 MST"),
+		"schema": b.SchemaVersion,
+	}))
+	utils.EOR(mystic, text, &tg.SendOptions{ReplyMarkup: kb.Build()})
+	return tg.ErrEndGroup
+}
+
+func restoreCB(cb *tg.CallbackQuery) error {
+	chatID := cb.ChannelID()
+	opt := &tg.CallbackOptions{Alert: true}
+
+	if cb.SenderID != config.OwnerID {
+		cb.Answer(F(chatID, "restore_owner_only"), opt)
+		return tg.ErrEndGroup
+	}
+
+	action := strings.TrimPrefix(cb.DataString(), "restore:")
+
+	pendingRestoreMu.Lock()
+	b := pendingRestore
+	expired := time.Since(pendingRestoreAt) > restoreConfirmTTL
+	pendingRestore = nil
+	pendingRestoreMu.Unlock()
+
+	if action == "cancel" {
+		cb.Answer("")
+		cb.Edit(F(chatID, "restore_cancelled"))
+		return tg.ErrEndGroup
+	}
+
+	if b == nil || expired {
+		cb.Answer(F(chatID, "restore_expired"), opt)
+		cb.Edit(F(chatID, "restore_expired"))
+		return tg.ErrEndGroup
+	}
+
+	mode := database.RestoreMerge
+	if action == "replace" {
+		mode = database.RestoreReplace
+	}
+
+	cb.Answer("")
+	cb.Edit(F(chatID, "restore_running"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	err := database.RestoreBackup(ctx, b, mode)
+	if err == nil {
+		// The archive may come from a deployment with more assistants.
+		err = database.RebalanceAssistantIndexes(core.Assistants.Count())
+	}
+	if err != nil {
+		logger.ErrorF("Restore (%s) failed: %v", action, err)
+		cb.Edit(F(chatID, "restore_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	loadLocalePacks()
+	logger.InfoF("Restored backup from %s (%s)", b.CreatedAt.Format(time.RFC3339), action)
+	cb.Edit(F(chatID, "restore_done", locales.Arg{
+		"mode": action,
+	}))
+	return tg.ErrEndGroup
+}
+
+func backupStatsArgs(b *database.Backup, arg locales.Arg) locales.Arg {
+	s := b.Stats()
+	arg["chats"] = s.Chats
+	arg["sudoers"] = s.Sudoers
+	arg["users"] = s.ServedUsers
+	arg["served_chats"] = s.ServedChats
+	arg["auth"] = s.AuthUsers
+	arg["rtmp"] = s.RTMPConfigs
+	arg["api_keys"] = s.APIKeys
+	arg["locale_packs"] = s.LocalePacks
+	return arg
+}
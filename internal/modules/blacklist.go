@@ -0,0 +1,299 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+func handleBlacklistUser(m *tg.NewMessage) error {
+	return setUserBlacklisted(m, true)
+}
+
+func handleUnblacklistUser(m *tg.NewMessage) error {
+	return setUserBlacklisted(m, false)
+}
+
+func setUserBlacklisted(m *tg.NewMessage, blacklist bool) error {
+	chatID := m.ChannelID()
+
+	if m.Args() == "" && !m.IsReply() {
+		m.Reply(F(chatID, "auth_no_user", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	targetID, err := utils.ExtractUser(m)
+	if err != nil {
+		m.Reply(F(chatID, "user_extract_fail", locales.Arg{
+			"error": err.Error(),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	if blacklist {
+		if key := blacklistProtected(targetID); key != "" {
+			m.Reply(F(chatID, key))
+			return tg.ErrEndGroup
+		}
+	}
+
+	uname := "<code>" + strconv.FormatInt(targetID, 10) + "</code>"
+	if user, err := m.Client.GetUser(targetID); err == nil {
+		uname = utils.MentionHTML(user)
+		if user.Username != "" {
+			uname = "@" + user.Username
+		}
+	}
+
+	var changed bool
+	if blacklist {
+		changed, err = database.BlacklistUser(targetID)
+	} else {
+		changed, err = database.UnblacklistUser(targetID)
+	}
+	if err != nil {
+		m.Reply(F(chatID, "blacklist_update_fail", locales.Arg{
+			"error": err.Error(),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	key := "blacklist_user_removed"
+	switch {
+	case blacklist && changed:
+		key = "blacklist_user_added"
+	case blacklist:
+		key = "blacklist_user_already"
+	case !changed:
+		key = "blacklist_user_not_listed"
+	}
+
+	m.Reply(F(chatID, key, locales.Arg{
+		"user": uname,
+		"id":   targetID,
+	}))
+	return tg.ErrEndGroup
+}
+
+// blacklistProtected returns the locale key explaining why the user can
+// not be blacklisted, or "" if they can.
+func blacklistProtected(userID int64) string {
+	if userID == config.OwnerID {
+		return "blacklist_owner"
+	}
+	if userID == core.BUser.ID {
+		return "blacklist_bot"
+	}
+	if database.IsSudoWithoutError(userID) {
+		return "blacklist_sudo"
+	}
+
+	isAssistant := false
+	core.Assistants.ForEach(func(a *core.Assistant) {
+		if a.User != nil && a.User.ID == userID {
+			isAssistant = true
+		}
+	})
+	if isAssistant {
+		return "blacklist_assistant"
+	}
+	return ""
+}
+
+func handleBlacklistChat(m *tg.NewMessage) error {
+	return setChatBlacklisted(m, true)
+}
+
+func handleUnblacklistChat(m *tg.NewMessage) error {
+	return setChatBlacklisted(m, false)
+}
+
+func setChatBlacklisted(m *tg.NewMessage, blacklist bool) error {
+	chatID := m.ChannelID()
+
+	targetID := chatID
+	if arg := strings.TrimSpace(m.Args()); arg != "" {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id == 0 {
+			m.Reply(F(chatID, "blacklist_chat_invalid", locales.Arg{
+				"cmd": getCommand(m),
+			}))
+			return tg.ErrEndGroup
+		}
+		targetID = id
+	} else if m.ChatType() == tg.EntityUser {
+		m.Reply(F(chatID, "blacklist_chat_invalid", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	if blacklist && targetID == config.LoggerID {
+		m.Reply(F(chatID, "blacklist_logger"))
+		return tg.ErrEndGroup
+	}
+
+	var (
+		changed bool
+		err     error
+	)
+	if blacklist {
+		changed, err = database.BlacklistChat(targetID)
+	} else {
+		changed, err = database.UnblacklistChat(targetID)
+	}
+	if err != nil {
+		m.Reply(F(chatID, "blacklist_update_fail", locales.Arg{
+			"error": err.Error(),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	key := "blacklist_chat_removed"
+	switch {
+	case blacklist && changed:
+		key = "blacklist_chat_added"
+	case blacklist:
+		key = "blacklist_chat_already"
+	case !changed:
+		key = "blacklist_chat_not_listed"
+	}
+
+	m.Reply(F(chatID, key, locales.Arg{"id": targetID}))
+
+	if blacklist {
+		go leaveBlacklistedChat(m.Client, targetID)
+	}
+	return tg.ErrEndGroup
+}
+
+func handleBlacklist(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	list, err := database.GetBlacklist()
+	if err != nil {
+		m.Reply(F(chatID, "blacklist_update_fail", locales.Arg{
+			"error": err.Error(),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	if len(list.Users) == 0 && len(list.Chats) == 0 {
+		m.Reply(F(chatID, "blacklist_empty"))
+		return tg.ErrEndGroup
+	}
+
+	var sb strings.Builder
+	sb.WriteString(F(chatID, "blacklist_header"))
+
+	if len(list.Users) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(F(chatID, "blacklist_users_title", locales.Arg{
+			"count": len(list.Users),
+		}))
+		for i, id := range list.Users {
+			sb.WriteString("\n" + strconv.Itoa(i+1) + ". <code>" +
+				strconv.FormatInt(id, 10) + "</code>")
+		}
+	}
+
+	if len(list.Chats) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(F(chatID, "blacklist_chats_title", locales.Arg{
+			"count": len(list.Chats),
+		}))
+		for i, id := range list.Chats {
+			sb.WriteString("\n" + strconv.Itoa(i+1) + ". <code>" +
+				strconv.FormatInt(id, 10) + "</code>")
+		}
+	}
+
+	m.Reply(sb.String())
+	return tg.ErrEndGroup
+}
+
+// leaveBlacklistedChat stops playback in the chat and makes the bot and
+// every assistant leave it.
+func leaveBlacklistedChat(client *tg.Client, chatID int64) {
+	core.DeleteRoom(chatID)
+
+	// Give the confirmation message a moment before leaving.
+	time.Sleep(1 * time.Second)
+
+	if err := client.LeaveChannel(chatID); err != nil {
+		logger.DebugF(
+			"Bot failed to leave blacklisted chat_id=%d: %v",
+			chatID,
+			err,
+		)
+	}
+
+	core.Assistants.ForEach(func(a *core.Assistant) {
+		if err := a.Client.LeaveChannel(chatID); err != nil {
+			logger.DebugF(
+				"assistant=%d failed to leave blacklisted chat_id=%d: %v",
+				a.Index(),
+				chatID,
+				err,
+			)
+		}
+	})
+
+	logger.InfoF("Left blacklisted chat_id=%d", chatID)
+}
+
+// checkBlacklisted reports whether the message must be dropped because its
+// sender or chat is blacklisted. Blacklisted groups are left on sight; the
+// owner is never blocked so the blacklist can always be undone.
+func checkBlacklisted(m *tg.NewMessage) bool {
+	senderID := m.SenderID()
+	if senderID == config.OwnerID {
+		return false
+	}
+
+	if database.IsBlacklistedUser(senderID) {
+		logger.DebugF("Ignoring blacklisted user %d", senderID)
+		return true
+	}
+
+	chatID := m.ChannelID()
+	if m.ChatType() != tg.EntityUser && database.IsBlacklistedChat(chatID) {
+		if database.IsSudoWithoutError(senderID) {
+			return false
+		}
+		logger.DebugF("Message in blacklisted chat_id=%d, leaving", chatID)
+		go leaveBlacklistedChat(m.Client, chatID)
+		return true
+	}
+	return false
+}
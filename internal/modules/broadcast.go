@@ -22,14 +22,20 @@ package modules
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -38,99 +44,81 @@ import (
 	"main/internal/locales"
 )
 
+const (
+	// Finished jobs are kept this long for -list and -report
+	broadcastKeepFinished = 30 * 24 * time.Hour
+	broadcastListLimit    = 10
+	broadcastReportLimit  = 5
+	broadcastTimeLayout   = "2006-01-02 15:04"
+	// Progress is saved every this many targets; a restart may send to at
+	// most this many targets twice.
+	broadcastSaveEvery = 25
+)
+
+// runningBroadcast is the job being sent. Only its runner writes to job,
+// others read it under mu.
+type runningBroadcast struct {
+	mu     sync.Mutex
+	job    *database.BroadcastJob
+	cancel context.CancelFunc
+}
+
 var (
-	broadcastMu     sync.Mutex
-	broadcastActive bool
-	broadcastCancel context.CancelFunc
-	broadcastCtx    context.Context
+	broadcastMu      sync.Mutex
+	currentBroadcast *runningBroadcast
+	broadcastWake    = make(chan struct{}, 1)
 
 	defaultDelay = 1.5
-)
 
-type BroadcastStats struct {
-	TotalChats  int
-	TotalUsers  int
-	DoneChats   int
-	DoneUsers   int
-	FailedChats []int64
-	FailedUsers []int64
-	Delay       float64
-	StartTime   time.Time
-	LastUpdate  time.Time
-	Finished    bool
-	mu          sync.Mutex
-}
+	rpcErrorCode = regexp.MustCompile(`[A-Z][A-Z0-9_]{3,}`)
+)
 
 type BroadcastFlags struct {
-	NoChat  bool
-	NoUser  bool
-	Copy    bool
-	Limit   int
-	Delay   float64
-	Pin     bool
-	PinLoud bool
+	NoChat        bool
+	NoUser        bool
+	Copy          bool
+	Limit         int
+	Delay         float64
+	Pin           bool
+	PinLoud       bool
+	Lang          string
+	ActiveDays    int
+	WithAssistant bool
+	RunAt         time.Time
+	DryRun        bool
 }
 
-func init() {
-	helpTexts["broadcast"] = `<i>Broadcast a message to all served chats and users.</i>
-
-<u>Usage:</u>
-<b>/broadcast [flags] [text] </b> — Broadcast text message.
-<b>/broadcast [flags] [reply to message]</b> — Broadcast the replied message.
-<b>/broadcast -cancel</b> — Cancel ongoing broadcast.
-
-<blockquote>
-<b>📋 Flags:</b>
-• <code>--nochat</code> — Exclude groups from broadcast
-• <code>--nouser</code> — Exclude users from broadcast
-• <code>--copy</code> — Remove forwarded tag, when broadcasting a replied message (copy mode)
-• <code>--limit [n]</code> — Limit total messages sent (default: 0 = no limit)
-• <code>--delay [seconds]</code> — Delay between messages (default: 1.5s)
-• <code>--pin</code> — Pin the message (silent)
-• <code>--pinloud</code> — Pin the message (with notification)
-
-• <code>-cancel</code> - Cancel a ongoing broadcast.
-</blockquote>
-<blockquote>
-<b>📌 Examples:</b>
-/broadcast -nochat -delay 2 Important announcement
-/broadcast -copy -nochat -pin [reply to message]
-/broadcast -limit 10 -delay 3 Limited broadcast
-</blockquote>
-<b>⚠️ Notes:</b>
-• Only the <b>owner</b> can use this command
-• After every 30 messages, there's an automatic 7.5s pause
-• You can cancel ongoing broadcasts using the inline button or <code>/broadcast -cancel</code>
-• Only one broadcast can run at a time`
-
-	helpTexts["gcast"] = helpTexts["broadcast"]
-	helpTexts["bcast"] = helpTexts["broadcast"]
+func (f *BroadcastFlags) segment() database.BroadcastSegment {
+	return database.BroadcastSegment{
+		NoChats:       f.NoChat,
+		NoUsers:       f.NoUser,
+		Lang:          f.Lang,
+		ActiveDays:    f.ActiveDays,
+		WithAssistant: f.WithAssistant,
+	}
 }
 
 func broadcastHandler(m *tg.NewMessage) error {
-	// Check for cancel flag
-	text := strings.ToLower(m.Text())
 	chatID := m.ChannelID()
-	if strings.Contains(text, "-cancel") || strings.Contains(text, "--cancel") {
-		return handleBroadcastCancel(m)
-	}
 
-	// Check if broadcast is already running
-	broadcastMu.Lock()
-	if broadcastActive {
-		broadcastMu.Unlock()
-		m.Reply(F(chatID, "broadcast_already_running"))
-		return tg.ErrEndGroup
+	if args := strings.Fields(m.Args()); len(args) > 0 {
+		var id string
+		if len(args) > 1 {
+			id = args[1]
+		}
+		switch strings.ToLower(args[0]) {
+		case "-cancel", "--cancel":
+			return handleBroadcastCancel(m, id)
+		case "-list", "--list":
+			return handleBroadcastList(m)
+		case "-report", "--report":
+			return handleBroadcastReport(m, id)
+		}
 	}
-	broadcastActive = true
-	broadcastMu.Unlock()
 
 	// Parse flags and content
 	flags, content, err := parseBroadcastCommand(m)
 	if err != nil {
-		broadcastMu.Lock()
-		broadcastActive = false
-		broadcastMu.Unlock()
 		m.Reply(F(chatID, "broadcast_parse_failed", locales.Arg{
 			"error": html.EscapeString(err.Error()),
 		}))
@@ -138,124 +126,104 @@ func broadcastHandler(m *tg.NewMessage) error {
 	}
 
 	if content == "" && !m.IsReply() {
-		broadcastMu.Lock()
-		broadcastActive = false
-		broadcastMu.Unlock()
 		m.Reply(F(chatID, "broadcast_no_content", locales.Arg{
 			"cmd": getCommand(m),
 		}))
 		return tg.ErrEndGroup
 	}
 
-	// Get served chats and users
-	var servedChats, servedUsers []int64
-	var servedChatErr, servedUserErr error
-
-	if !flags.NoChat {
-		servedChats, servedChatErr = database.GetServed()
-		if servedChatErr != nil {
-			broadcastMu.Lock()
-			broadcastActive = false
-			broadcastMu.Unlock()
-			m.Reply(F(chatID, "broadcast_fetch_chats_failed", locales.Arg{
-				"error": html.EscapeString(servedChatErr.Error()),
-			}))
+	now := time.Now()
+	job := &database.BroadcastJob{
+		ID:         newBroadcastID(),
+		CreatedBy:  m.SenderID(),
+		CreatedAt:  now,
+		RunAt:      now,
+		Status:     database.BroadcastScheduled,
+		Copy:       flags.Copy,
+		Pin:        flags.Pin,
+		PinLoud:    flags.PinLoud,
+		Delay:      flags.Delay,
+		Limit:      flags.Limit,
+		Segment:    flags.segment(),
+		ReportChat: chatID,
+	}
+	if !flags.RunAt.IsZero() {
+		job.RunAt = flags.RunAt
+	}
+	if m.IsReply() {
+		job.FromChat = chatID
+		job.MessageID = m.ReplyID()
+	} else {
+		job.Text = content
+	}
 
-			return tg.ErrEndGroup
-		}
+	targets, err := resolveBroadcastTargets(&job.Segment, job.Limit)
+	if err != nil {
+		m.Reply(F(chatID, "broadcast_fetch_targets_failed", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
 	}
+	chats, users := countBroadcastTargets(targets)
 
-	if !flags.NoUser {
-		servedUsers, servedUserErr = database.GetServed(true)
-		if servedUserErr != nil {
-			broadcastMu.Lock()
-			broadcastActive = false
-			broadcastMu.Unlock()
-			m.Reply(F(chatID, "broadcast_fetch_users_failed", locales.Arg{
-				"error": html.EscapeString(servedUserErr.Error()),
-			}))
-			return tg.ErrEndGroup
+	if flags.DryRun {
+		text := F(chatID, "broadcast_dry_run", locales.Arg{
+			"chats": chats,
+			"users": users,
+		})
+		if job.Segment.WithAssistant && chats > 0 {
+			text += "\n" + F(chatID, "broadcast_dry_run_assistant")
 		}
+		m.Reply(text)
+		return tg.ErrEndGroup
 	}
 
 	// Check if there are any targets
-	if len(servedChats) == 0 && len(servedUsers) == 0 {
-		broadcastMu.Lock()
-		broadcastActive = false
-		broadcastMu.Unlock()
+	if len(targets) == 0 {
 		m.Reply(F(chatID, "broadcast_no_targets"))
 		return tg.ErrEndGroup
 	}
 
-	// Apply limit if specified
-	totalTargets := len(servedChats) + len(servedUsers)
-	if flags.Limit > 0 && totalTargets > flags.Limit {
-		if len(servedChats) >= flags.Limit {
-			servedChats = servedChats[:flags.Limit]
-			servedUsers = nil
-		} else {
-			remaining := flags.Limit - len(servedChats)
-			if len(servedUsers) > remaining {
-				servedUsers = servedUsers[:remaining]
-			}
-		}
-	}
+	broadcastMu.Lock()
+	defer broadcastMu.Unlock()
 
-	now := time.Now()
-	stats := &BroadcastStats{
-		TotalChats:  len(servedChats),
-		TotalUsers:  len(servedUsers),
-		Delay:       flags.Delay,
-		StartTime:   now,
-		LastUpdate:  now,
-		FailedChats: make([]int64, 0),
-		FailedUsers: make([]int64, 0),
+	if job.RunAt.After(now) || currentBroadcast != nil {
+		if err := database.SaveBroadcastJob(job); err != nil {
+			m.Reply(F(chatID, "broadcast_save_failed", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+			return tg.ErrEndGroup
+		}
+		key := "broadcast_scheduled"
+		if !job.RunAt.After(now) {
+			key = "broadcast_queued"
+		}
+		m.Reply(F(chatID, key, locales.Arg{
+			"id":    job.ID,
+			"time":  formatBroadcastTime(job.RunAt),
+			"chats": chats,
+			"users": users,
+		}))
+		return tg.ErrEndGroup
 	}
 
-	broadcastCtx, broadcastCancel = context.WithCancel(context.Background())
-
 	progressMsg, err := m.Reply(F(chatID, "broadcast_initializing"),
 		&tg.SendOptions{
 			ReplyMarkup: core.GetBroadcastCancelKeyboard(chatID),
 		})
 	if err != nil {
-		broadcastMu.Lock()
-		broadcastActive = false
-		broadcastCtx = nil
-		broadcastCancel = nil
-		broadcastMu.Unlock()
-		gologging.ErrorF("Failed to send broadcast progress message: %v", err)
+		logger.ErrorF("Failed to send broadcast progress message: %v", err)
 		return tg.ErrEndGroup
 	}
+	job.ReportMsgID = progressMsg.ID
 
-	// Start progress updater in goroutine
-	go updateBroadcastProgress(broadcastCtx, progressMsg, stats)
-
-	// Start broadcast in goroutine
-	go func() {
-		defer func() {
-			broadcastMu.Lock()
-			if broadcastCancel != nil {
-				broadcastCancel()
-			}
-			broadcastActive = false
-			broadcastCtx = nil
-			broadcastCancel = nil
-			broadcastMu.Unlock()
-		}()
-
-		startBroadcast(
-			broadcastCtx,
-			m,
-			progressMsg,
-			flags,
-			content,
-			servedChats,
-			servedUsers,
-			stats,
-		)
-	}()
-
+	if err := database.SaveBroadcastJob(job); err != nil {
+		progressMsg.Edit(F(chatID, "broadcast_save_failed", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+	startBroadcastJob(job, targets)
 	return tg.ErrEndGroup
 }
 
@@ -276,6 +244,15 @@ func parseBroadcastCommand(m *tg.NewMessage) (*BroadcastFlags, string, error) {
 	var contentWords []string
 	skipNext := false
 
+	// value returns the word after a flag that needs one
+	value := func(i int) (string, error) {
+		if i+1 >= len(words) {
+			return "", fmt.Errorf("%s requires a value", words[i])
+		}
+		skipNext = true
+		return words[i+1], nil
+	}
+
 	for i := 0; i < len(words); i++ {
 		if skipNext {
 			skipNext = false
@@ -295,43 +272,83 @@ func parseBroadcastCommand(m *tg.NewMessage) (*BroadcastFlags, string, error) {
 			flags.Pin = true
 		case "-pinloud", "--pinloud":
 			flags.PinLoud = true
+		case "-assistant", "--assistant":
+			flags.WithAssistant = true
+		case "-dry", "--dry", "-dryrun", "--dryrun":
+			flags.DryRun = true
 		case "-limit", "--limit":
-			if i+1 < len(words) {
-				limit, err := strconv.Atoi(words[i+1])
-				if err != nil {
-					return nil, "", fmt.Errorf(
-						"invalid limit value: %s",
-						words[i+1],
-					)
-				}
-				if limit < 0 {
-					return nil, "", fmt.Errorf("limit must be non-negative")
-				}
-				flags.Limit = limit
-				skipNext = true
-			} else {
-				return nil, "", fmt.Errorf("-limit requires a value")
+			v, err := value(i)
+			if err != nil {
+				return nil, "", err
 			}
+			limit, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid limit value: %s", v)
+			}
+			if limit < 0 {
+				return nil, "", fmt.Errorf("limit must be non-negative")
+			}
+			flags.Limit = limit
 		case "-delay", "--delay":
-			if i+1 < len(words) {
-				delay, err := strconv.ParseFloat(words[i+1], 64)
-				if err != nil {
-					return nil, "", fmt.Errorf(
-						"invalid delay value: %s",
-						words[i+1],
-					)
-				}
-				if delay < 0 {
-					return nil, "", fmt.Errorf("delay must be non-negative")
-				}
-				flags.Delay = delay
-				skipNext = true
-			} else {
-				return nil, "", fmt.Errorf("-delay requires a value")
+			v, err := value(i)
+			if err != nil {
+				return nil, "", err
 			}
-		case "-cancel", "--cancel":
-			// Special case, handled in main handler
-			continue
+			delay, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid delay value: %s", v)
+			}
+			if delay < 0 {
+				return nil, "", fmt.Errorf("delay must be non-negative")
+			}
+			flags.Delay = delay
+		case "-lang", "--lang":
+			v, err := value(i)
+			if err != nil {
+				return nil, "", err
+			}
+			flags.Lang = strings.ToLower(v)
+			if !slices.Contains(locales.GetAvailableLanguages(), flags.Lang) {
+				return nil, "", fmt.Errorf("unknown language: %s", v)
+			}
+		case "-active", "--active":
+			v, err := value(i)
+			if err != nil {
+				return nil, "", err
+			}
+			days, err := strconv.Atoi(v)
+			if err != nil || days <= 0 {
+				return nil, "", fmt.Errorf("invalid number of days: %s", v)
+			}
+			// Activity comes from the play history, which keeps only
+			// PLAY_HISTORY_DAYS (0 keeps it forever).
+			if config.PlayHistoryDays > 0 && days > config.PlayHistoryDays {
+				return nil, "", fmt.Errorf(
+					"play history only goes back %d days (PLAY_HISTORY_DAYS)",
+					config.PlayHistoryDays,
+				)
+			}
+			flags.ActiveDays = days
+		case "-in", "--in":
+			v, err := value(i)
+			if err != nil {
+				return nil, "", err
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return nil, "", fmt.Errorf("invalid duration: %s", v)
+			}
+			flags.RunAt = time.Now().Add(d)
+		case "-at", "--at":
+			v, err := value(i)
+			if err != nil {
+				return nil, "", err
+			}
+			at, err := parseBroadcastAt(v)
+			if err != nil {
+				return nil, "", err
+			}
+			flags.RunAt = at
 		default:
 			// Not a flag, add to content (preserve original case from words slice)
 			contentWords = append(contentWords, words[i])
@@ -355,100 +372,362 @@ func parseBroadcastCommand(m *tg.NewMessage) (*BroadcastFlags, string, error) {
 	return flags, content, nil
 }
 
-func startBroadcast(
-	ctx context.Context,
-	m, progressMsg *tg.NewMessage,
-	flags *BroadcastFlags,
-	content string,
-	chats, users []int64,
-	stats *BroadcastStats,
-) {
-	defer func() {
-		if r := recover(); r != nil {
-			gologging.ErrorF("Broadcast panic recovered: %v", r)
-			finalizeBroadcast(progressMsg, stats, true)
+// parseBroadcastAt reads a UTC time as 2006-01-02T15:04, or as 15:04 for
+// its next occurrence.
+func parseBroadcastAt(v string) (time.Time, error) {
+	now := time.Now().UTC()
+	if at, err := time.ParseInLocation("2006-01-02T15:04", v, time.UTC); err == nil {
+		if !at.After(now) {
+			return time.Time{}, fmt.Errorf("time is in the past: %s", v)
 		}
-	}()
+		return at, nil
+	}
+	if t, err := time.Parse("15:04", v); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use 2006-01-02T15:04 or 15:04, UTC)", v)
+}
+
+func newBroadcastID() string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func formatBroadcastTime(t time.Time) string {
+	return t.UTC().Format(broadcastTimeLayout) + " UTC"
+}
+
+// resolveBroadcastTargets lists who a segment reaches, in ascending id
+// order: chats first, then users.
+func resolveBroadcastTargets(seg *database.BroadcastSegment, limit int) ([]int64, error) {
+	var targets []int64
+	if !seg.NoChats {
+		chats, err := database.GetServed()
+		if err != nil {
+			return nil, fmt.Errorf("served chats: %w", err)
+		}
+		targets = append(targets, chats...)
+	}
+	if !seg.NoUsers {
+		users, err := database.GetServed(true)
+		if err != nil {
+			return nil, fmt.Errorf("served users: %w", err)
+		}
+		targets = append(targets, users...)
+	}
+
+	if seg.Lang != "" {
+		langs, err := database.GetChatLanguages()
+		if err != nil {
+			return nil, fmt.Errorf("chat languages: %w", err)
+		}
+		targets = slices.DeleteFunc(targets, func(id int64) bool {
+			lang, ok := langs[id]
+			if !ok {
+				lang = database.DefaultLang
+			}
+			return lang != seg.Lang
+		})
+	}
+
+	if seg.ActiveDays > 0 {
+		since := time.Now().AddDate(0, 0, -seg.ActiveDays)
+		chats, users, err := database.GetActivePlayers(since)
+		if err != nil {
+			return nil, fmt.Errorf("active chats: %w", err)
+		}
+		active := make(map[int64]struct{}, len(chats)+len(users))
+		for _, id := range append(chats, users...) {
+			active[id] = struct{}{}
+		}
+		targets = slices.DeleteFunc(targets, func(id int64) bool {
+			_, ok := active[id]
+			return !ok
+		})
+	}
+
+	slices.Sort(targets)
+	targets = slices.Compact(targets)
+	if limit > 0 && len(targets) > limit {
+		targets = targets[:limit]
+	}
+	return targets, nil
+}
+
+func countBroadcastTargets(targets []int64) (chats, users int) {
+	for _, id := range targets {
+		if id < 0 {
+			chats++
+		} else {
+			users++
+		}
+	}
+	return chats, users
+}
 
-	messagesSent := 0
+func wakeBroadcasts() {
+	select {
+	case broadcastWake <- struct{}{}:
+	default:
+	}
+}
 
-	// Broadcast to chats
-	for _, chatID := range chats {
+// RunBroadcasts resumes a broadcast cut short by a restart and starts
+// queued and scheduled ones once they are due, one at a time. Old finished
+// jobs are dropped hourly.
+func RunBroadcasts() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		if time.Since(lastPrune) > time.Hour {
+			pruneBroadcastJobs()
+			lastPrune = time.Now()
+		}
+		startDueBroadcast()
 		select {
-		case <-ctx.Done():
-			finalizeBroadcast(progressMsg, stats, true)
-			return
-		default:
+		case <-ticker.C:
+		case <-broadcastWake:
+		}
+	}
+}
+
+func pruneBroadcastJobs() {
+	jobs, err := database.GetBroadcastJobs()
+	if err != nil {
+		return
+	}
+	for _, job := range jobs {
+		if !job.FinishedAt.IsZero() && time.Since(job.FinishedAt) > broadcastKeepFinished {
+			database.DeleteBroadcastJob(job.ID)
+		}
+	}
+}
+
+func startDueBroadcast() {
+	broadcastMu.Lock()
+	defer broadcastMu.Unlock()
+	if currentBroadcast != nil {
+		return
+	}
+
+	jobs, err := database.GetBroadcastJobs()
+	if err != nil {
+		return
+	}
+
+	// An interrupted job goes first, then the one due the longest
+	var next *database.BroadcastJob
+	for _, job := range jobs {
+		switch job.Status {
+		case database.BroadcastRunning:
+			next = job
+		case database.BroadcastScheduled:
+			if time.Now().Before(job.RunAt) {
+				continue
+			}
+			if next == nil ||
+				(next.Status != database.BroadcastRunning && job.RunAt.Before(next.RunAt)) {
+				next = job
+			}
 		}
+		if next != nil && next.Status == database.BroadcastRunning {
+			break
+		}
+	}
+	if next == nil {
+		return
+	}
+
+	if next.Status == database.BroadcastRunning {
+		logger.InfoF("Resuming broadcast %s after %d targets", next.ID, next.Handled())
+	}
+	startBroadcastJob(next, nil)
+}
+
+// startBroadcastJob runs job in the background; targets are resolved
+// again when nil. broadcastMu must be held.
+func startBroadcastJob(job *database.BroadcastJob, targets []int64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rb := &runningBroadcast{job: job, cancel: cancel}
+	currentBroadcast = rb
+
+	go runBroadcast(ctx, rb, targets)
+}
 
-		success := sendBroadcastMessage(ctx, m, chatID, content, flags)
+func runBroadcast(ctx context.Context, rb *runningBroadcast, targets []int64) {
+	job := rb.job
+	cancelled := true
 
-		stats.mu.Lock()
-		stats.DoneChats++
-		stats.LastUpdate = time.Now()
-		if !success {
-			stats.FailedChats = append(stats.FailedChats, chatID)
+	stopUpdates := func() {}
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ErrorF("Broadcast panic recovered: %v", r)
+			cancelled = true
 		}
-		stats.mu.Unlock()
+		stopUpdates()
+		finishBroadcast(rb, cancelled)
+
+		broadcastMu.Lock()
+		currentBroadcast = nil
+		broadcastMu.Unlock()
+		rb.cancel()
+		wakeBroadcasts()
+	}()
 
-		messagesSent++
-		if !handleBroadcastDelay(ctx, messagesSent, flags.Delay) {
-			finalizeBroadcast(progressMsg, stats, true)
+	if targets == nil {
+		var err error
+		targets, err = resolveBroadcastTargets(&job.Segment, job.Limit)
+		if err != nil {
+			logger.ErrorF("Failed to resolve broadcast %s targets: %v", job.ID, err)
 			return
 		}
 	}
 
-	// Broadcast to users
-	for _, userID := range users {
-		select {
-		case <-ctx.Done():
-			finalizeBroadcast(progressMsg, stats, true)
+	rb.mu.Lock()
+	if job.StartedAt.IsZero() {
+		job.StartedAt = time.Now()
+		job.TotalChats, job.TotalUsers = countBroadcastTargets(targets)
+	} else if job.Cursor != 0 {
+		// Resumed: skip whatever was handled before the restart
+		i, _ := slices.BinarySearch(targets, job.Cursor+1)
+		targets = targets[i:]
+	}
+	job.Status = database.BroadcastRunning
+	rb.mu.Unlock()
+
+	if job.ReportMsgID == 0 {
+		msg, err := core.Bot.SendMessage(
+			job.ReportChat,
+			F(job.ReportChat, "broadcast_initializing"),
+			&tg.SendOptions{ReplyMarkup: core.GetBroadcastCancelKeyboard(job.ReportChat)},
+		)
+		if err == nil {
+			job.ReportMsgID = msg.ID
+		}
+	}
+	database.SaveBroadcastJob(job)
+
+	updCtx, cancelUpdates := context.WithCancel(ctx)
+	updDone := make(chan struct{})
+	go func() {
+		defer close(updDone)
+		updateBroadcastProgress(updCtx, rb)
+	}()
+	stopUpdates = func() {
+		cancelUpdates()
+		<-updDone
+	}
+
+	sent := 0
+	for _, targetID := range targets {
+		if ctx.Err() != nil {
 			return
-		default:
 		}
 
-		success := sendBroadcastMessage(ctx, m, userID, content, flags)
+		if job.Segment.WithAssistant && targetID < 0 && !assistantPresent(targetID) {
+			rb.mu.Lock()
+			job.Skipped++
+			job.Cursor = targetID
+			rb.mu.Unlock()
+			saveBroadcastProgress(job)
+			continue
+		}
+
+		err := sendBroadcastMessage(ctx, job, targetID)
+		if ctx.Err() != nil {
+			return
+		}
 
-		stats.mu.Lock()
-		stats.DoneUsers++
-		stats.LastUpdate = time.Now()
-		if !success {
-			stats.FailedUsers = append(stats.FailedUsers, userID)
+		pruned := false
+		if err != nil {
+			if kind := core.ClassifySendError(err); kind.Permanent() {
+				pruned = database.DeleteServed(targetID, targetID > 0) == nil
+			}
+		}
+
+		rb.mu.Lock()
+		if targetID < 0 {
+			job.DoneChats++
+		} else {
+			job.DoneUsers++
+		}
+		if err != nil {
+			job.AddFailure(targetID, broadcastFailReason(err))
+		}
+		if pruned {
+			job.Pruned++
 		}
-		stats.mu.Unlock()
+		job.Cursor = targetID
+		rb.mu.Unlock()
+		saveBroadcastProgress(job)
 
-		messagesSent++
-		if !handleBroadcastDelay(ctx, messagesSent, flags.Delay) {
-			finalizeBroadcast(progressMsg, stats, true)
+		sent++
+		if !handleBroadcastDelay(ctx, sent, job.Delay) {
 			return
 		}
 	}
+	cancelled = false
+}
+
+// saveBroadcastProgress stores the cursor every broadcastSaveEvery
+// targets; finishBroadcast saves the final state.
+func saveBroadcastProgress(job *database.BroadcastJob) {
+	if job.Handled()%broadcastSaveEvery == 0 {
+		database.SaveBroadcastJob(job)
+	}
+}
+
+func assistantPresent(chatID int64) bool {
+	cs, err := core.GetChatState(chatID)
+	if err != nil {
+		return false
+	}
+	present, err := cs.IsAssistantPresent()
+	return err == nil && present
+}
 
-	finalizeBroadcast(progressMsg, stats, false)
+// broadcastFailReason is the class of a send error followed by its RPC
+// error code when it has one, so failures group well in the report.
+func broadcastFailReason(err error) string {
+	kind := string(core.ClassifySendError(err))
+	if code := rpcErrorCode.FindString(err.Error()); code != "" {
+		return kind + ": " + code
+	}
+	reason := err.Error()
+	if len(reason) > 80 {
+		reason = reason[:80]
+	}
+	return kind + ": " + reason
 }
 
 func sendBroadcastMessage(
 	ctx context.Context,
-	m *tg.NewMessage,
+	job *database.BroadcastJob,
 	targetID int64,
-	content string,
-	flags *BroadcastFlags,
-) bool {
+) error {
 	var (
 		sentMsg *tg.NewMessage
 		err     error
 	)
 
 	try := func() error {
-		if m.IsReply() {
+		if job.MessageID != 0 {
 			fOpts := &tg.ForwardOptions{}
-			if flags.Copy {
+			if job.Copy {
 				fOpts.HideAuthor = true
 			}
-			fMsgs, ferr := m.Client.Forward(
+			fMsgs, ferr := core.Bot.Forward(
 				targetID,
-				m.Peer,
-				[]int32{m.ReplyID()},
+				job.FromChat,
+				[]int32{job.MessageID},
 				fOpts,
 			)
 			if ferr != nil {
@@ -460,7 +739,7 @@ func sendBroadcastMessage(
 			return nil
 		}
 
-		sent, ferr := m.Client.SendMessage(targetID, content)
+		sent, ferr := core.Bot.SendMessage(targetID, job.Text)
 		if ferr != nil {
 			return ferr
 		}
@@ -476,13 +755,14 @@ func sendBroadcastMessage(
 		}
 
 		if wait := tg.GetFloodWait(err); wait > 0 {
-			gologging.ErrorF(
+			floodWaits.Inc("broadcast")
+			logger.ErrorF(
 				"FloodWait detected (%ds). Retrying (attempt %d).",
 				wait,
 				attempt,
 			)
 			if !sleepCtx(ctx, time.Duration(wait)*time.Second) {
-				return false
+				return ctx.Err()
 			}
 			continue
 		} else {
@@ -491,20 +771,18 @@ func sendBroadcastMessage(
 	}
 
 	if err != nil {
-		if !tg.MatchError(err, "USER_IS_BLOCKED") &&
-			!tg.MatchError(err, "CHAT_WRITE_FORBIDDEN") &&
-			!tg.MatchError(err, "USER_IS_DEACTIVATED") {
-			gologging.ErrorF("Broadcast failed for %d: %v", targetID, err)
+		if !core.ClassifySendError(err).Permanent() {
+			logger.ErrorF("Broadcast failed for %d: %v", targetID, err)
 		}
-		return false
+		return err
 	}
 
-	if sentMsg != nil && (flags.Pin || flags.PinLoud) {
-		if _, perr := m.Client.PinMessage(targetID, sentMsg.ID, &tg.PinOptions{Silent: !flags.PinLoud}); perr != nil {
-			gologging.ErrorF("Pin failed for %d: %v", targetID, perr)
+	if sentMsg != nil && (job.Pin || job.PinLoud) {
+		if _, perr := core.Bot.PinMessage(targetID, sentMsg.ID, &tg.PinOptions{Silent: !job.PinLoud}); perr != nil {
+			logger.ErrorF("Pin failed for %d: %v", targetID, perr)
 		}
 	}
-	return true
+	return nil
 }
 
 func handleBroadcastDelay(
@@ -518,119 +796,123 @@ func handleBroadcastDelay(
 	return sleepCtx(ctx, time.Duration(baseDelay*float64(time.Second)))
 }
 
-func updateBroadcastProgress(
-	ctx context.Context,
-	progressMsg *tg.NewMessage,
-	stats *BroadcastStats,
-) {
+func updateBroadcastProgress(ctx context.Context, rb *runningBroadcast) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
+	job := rb.job
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			stats.mu.Lock()
-			if stats.Finished {
-				stats.mu.Unlock()
-				return
+			if job.ReportMsgID == 0 {
+				continue
 			}
-			text := formatBroadcastProgress(
-				stats,
-				false,
-				progressMsg.ChannelID(),
-			)
-			stats.mu.Unlock()
+			rb.mu.Lock()
+			text := formatBroadcastProgress(job, false, job.ReportChat)
+			rb.mu.Unlock()
 
-			progressMsg.Edit(text, &tg.SendOptions{
-				ReplyMarkup: core.GetBroadcastCancelKeyboard(
-					progressMsg.ChannelID(),
-				),
+			core.Bot.EditMessage(job.ReportChat, job.ReportMsgID, text, &tg.SendOptions{
+				ReplyMarkup: core.GetBroadcastCancelKeyboard(job.ReportChat),
 			})
 		}
 	}
 }
 
-func finalizeBroadcast(
-	progressMsg *tg.NewMessage,
-	stats *BroadcastStats,
-	cancelled bool,
-) {
-	stats.mu.Lock()
-	stats.Finished = true
-	text := formatBroadcastProgress(stats, true, progressMsg.ChannelID())
-	stats.mu.Unlock()
+func finishBroadcast(rb *runningBroadcast, cancelled bool) {
+	job := rb.job
 
+	rb.mu.Lock()
+	job.Status = database.BroadcastDone
 	if cancelled {
-		text = F(progressMsg.ChannelID(), "broadcast_cancelled_header")
-	} else {
-		text = F(progressMsg.ChannelID(), "broadcast_completed_header")
+		job.Status = database.BroadcastCancelled
 	}
+	job.FinishedAt = time.Now()
+	text := formatBroadcastReport(job, job.ReportChat)
+	rb.mu.Unlock()
+
+	database.SaveBroadcastJob(job)
 
-	progressMsg.Edit(text)
+	if job.ReportMsgID != 0 {
+		if _, err := core.Bot.EditMessage(job.ReportChat, job.ReportMsgID, text); err == nil {
+			return
+		}
+	}
+	core.Bot.SendMessage(job.ReportChat, text)
 }
 
 func formatBroadcastProgress(
-	stats *BroadcastStats,
+	job *database.BroadcastJob,
 	final bool,
 	chatID int64,
 ) string {
-	elapsed := time.Since(stats.StartTime)
+	elapsed := time.Since(job.StartedAt)
+	if final {
+		elapsed = job.FinishedAt.Sub(job.StartedAt)
+	}
 
 	var sb strings.Builder
 
 	if !final {
-		sb.WriteString(F(chatID, "broadcast_progress_header") + "\n\n")
+		sb.WriteString(F(chatID, "broadcast_progress_header", locales.Arg{
+			"id": job.ID,
+		}) + "\n\n")
 	}
 
 	chatProgress := 0.0
-	if stats.TotalChats > 0 {
+	if job.TotalChats > 0 {
 		chatProgress = float64(
-			stats.DoneChats,
+			job.DoneChats,
 		) / float64(
-			stats.TotalChats,
+			job.TotalChats,
 		) * 100
 	}
 
 	userProgress := 0.0
-	if stats.TotalUsers > 0 {
+	if job.TotalUsers > 0 {
 		userProgress = float64(
-			stats.DoneUsers,
+			job.DoneUsers,
 		) / float64(
-			stats.TotalUsers,
+			job.TotalUsers,
 		) * 100
 	}
 
 	sb.WriteString(F(chatID, "broadcast_total_chats", locales.Arg{
-		"done":     stats.DoneChats,
-		"total":    stats.TotalChats,
+		"done":     job.DoneChats,
+		"total":    job.TotalChats,
 		"progress": fmt.Sprintf("%.1f", chatProgress),
 	}) + "\n")
 
 	sb.WriteString(F(chatID, "broadcast_total_users", locales.Arg{
-		"done":     stats.DoneUsers,
-		"total":    stats.TotalUsers,
+		"done":     job.DoneUsers,
+		"total":    job.TotalUsers,
 		"progress": fmt.Sprintf("%.1f", userProgress),
 	}) + "\n\n")
 
-	if len(stats.FailedChats) > 0 {
-		sb.WriteString(F(chatID, "broadcast_failed_chats", locales.Arg{
-			"count": len(stats.FailedChats),
+	failed := job.FailedCount()
+	if failed > 0 {
+		sb.WriteString(F(chatID, "broadcast_failed", locales.Arg{
+			"count": failed,
 		}) + "\n")
 	}
-	if len(stats.FailedUsers) > 0 {
-		sb.WriteString(F(chatID, "broadcast_failed_users", locales.Arg{
-			"count": len(stats.FailedUsers),
+	if job.Skipped > 0 {
+		sb.WriteString(F(chatID, "broadcast_skipped", locales.Arg{
+			"count": job.Skipped,
+		}) + "\n")
+	}
+	if job.Pruned > 0 {
+		sb.WriteString(F(chatID, "broadcast_pruned", locales.Arg{
+			"count": job.Pruned,
 		}) + "\n")
 	}
 
-	if len(stats.FailedChats) > 0 || len(stats.FailedUsers) > 0 {
+	if failed > 0 || job.Skipped > 0 || job.Pruned > 0 {
 		sb.WriteString("\n")
 	}
 
-	totalDone := stats.DoneChats + stats.DoneUsers
-	totalTargets := stats.TotalChats + stats.TotalUsers
+	totalDone := job.Handled()
+	totalTargets := job.TotalChats + job.TotalUsers
 
 	avgSpeed := 0.0
 	if elapsed.Seconds() > 0 && totalDone > 0 {
@@ -638,7 +920,7 @@ func formatBroadcastProgress(
 	}
 
 	sb.WriteString(F(chatID, "broadcast_delay", locales.Arg{
-		"delay": fmt.Sprintf("%.1f", stats.Delay),
+		"delay": fmt.Sprintf("%.1f", job.Delay),
 	}) + "\n")
 
 	sb.WriteString(F(chatID, "broadcast_elapsed", locales.Arg{
@@ -654,13 +936,12 @@ func formatBroadcastProgress(
 	}
 
 	if final {
-		totalSent := stats.DoneChats + stats.DoneUsers
-		totalFailed := len(stats.FailedChats) + len(stats.FailedUsers)
+		totalSent := job.DoneChats + job.DoneUsers
 
 		successRate := 0.0
 		if totalTargets > 0 {
 			successRate = float64(
-				totalSent-totalFailed,
+				totalSent-failed,
 			) / float64(
 				totalTargets,
 			) * 100
@@ -668,7 +949,7 @@ func formatBroadcastProgress(
 
 		sb.WriteString("\n\n" + F(chatID, "broadcast_success_rate", locales.Arg{
 			"rate":  fmt.Sprintf("%.1f", successRate),
-			"sent":  totalSent - totalFailed,
+			"sent":  totalSent - failed,
 			"total": totalTargets,
 		}))
 	}
@@ -676,23 +957,191 @@ func formatBroadcastProgress(
 	return sb.String()
 }
 
-func handleBroadcastCancel(m *tg.NewMessage) error {
+// formatBroadcastReport is the final summary of a job with its most common
+// failure reasons.
+func formatBroadcastReport(job *database.BroadcastJob, chatID int64) string {
+	var sb strings.Builder
+
+	switch job.Status {
+	case database.BroadcastDone:
+		sb.WriteString(F(chatID, "broadcast_completed_header", locales.Arg{"id": job.ID}))
+	case database.BroadcastCancelled:
+		sb.WriteString(F(chatID, "broadcast_cancelled_header", locales.Arg{"id": job.ID}))
+	default:
+		sb.WriteString(F(chatID, "broadcast_progress_header", locales.Arg{"id": job.ID}))
+	}
+	if job.StartedAt.IsZero() {
+		return sb.String()
+	}
+	sb.WriteString("\n\n" + formatBroadcastProgress(job, !job.FinishedAt.IsZero(), chatID))
+
+	if len(job.FailureCounts) == 0 {
+		return sb.String()
+	}
+
+	reasons := make([]string, 0, len(job.FailureCounts))
+	for reason := range job.FailureCounts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		return job.FailureCounts[reasons[i]] > job.FailureCounts[reasons[j]]
+	})
+	if len(reasons) > broadcastReportLimit {
+		reasons = reasons[:broadcastReportLimit]
+	}
+
+	sb.WriteString("\n\n" + F(chatID, "broadcast_failure_reasons") + "\n")
+	for _, reason := range reasons {
+		sb.WriteString(F(chatID, "broadcast_failure_reason", locales.Arg{
+			"reason": html.EscapeString(reason),
+			"count":  job.FailureCounts[reason],
+		}) + "\n")
+	}
+	return sb.String()
+}
+
+// handleBroadcastCancel stops the running broadcast, or cancels the job
+// with the given id whether it runs or waits.
+func handleBroadcastCancel(m *tg.NewMessage, id string) error {
+	chatID := m.ChannelID()
+
 	broadcastMu.Lock()
 	defer broadcastMu.Unlock()
 
-	if !broadcastActive {
-		m.Reply(F(m.ChannelID(), "broadcast_not_running"))
+	if rb := currentBroadcast; rb != nil && (id == "" || id == rb.job.ID) {
+		rb.cancel()
+		m.Reply(F(chatID, "broadcast_cancel_success"))
+		return tg.ErrEndGroup
+	}
+	if id == "" {
+		m.Reply(F(chatID, "broadcast_not_running"))
+		return tg.ErrEndGroup
+	}
+
+	job, err := database.GetBroadcastJob(id)
+	if err != nil || job == nil {
+		m.Reply(F(chatID, "broadcast_job_not_found", locales.Arg{"id": html.EscapeString(id)}))
+		return tg.ErrEndGroup
+	}
+	if job.Status != database.BroadcastScheduled && job.Status != database.BroadcastRunning {
+		m.Reply(F(chatID, "broadcast_job_finished", locales.Arg{"id": job.ID}))
+		return tg.ErrEndGroup
+	}
+
+	job.Status = database.BroadcastCancelled
+	job.FinishedAt = time.Now()
+	if err := database.SaveBroadcastJob(job); err != nil {
+		m.Reply(F(chatID, "broadcast_save_failed", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+	m.Reply(F(chatID, "broadcast_cancel_success"))
+	return tg.ErrEndGroup
+}
+
+func handleBroadcastList(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	jobs, err := database.GetBroadcastJobs()
+	if err != nil {
+		m.Reply(F(chatID, "broadcast_fetch_targets_failed", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+	if len(jobs) == 0 {
+		m.Reply(F(chatID, "broadcast_list_empty"))
+		return tg.ErrEndGroup
+	}
+	if len(jobs) > broadcastListLimit {
+		jobs = jobs[:broadcastListLimit]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(F(chatID, "broadcast_list_header") + "\n\n")
+	for _, job := range jobs {
+		total := job.TotalChats + job.TotalUsers
+		sb.WriteString(F(chatID, "broadcast_list_item", locales.Arg{
+			"id":     job.ID,
+			"status": F(chatID, "broadcast_status_"+job.Status),
+			"time":   formatBroadcastTime(job.RunAt),
+			"done":   job.Handled(),
+			"total":  total,
+		}) + "\n")
+	}
+	sb.WriteString("\n" + F(chatID, "broadcast_list_footer", locales.Arg{
+		"cmd": getCommand(m),
+	}))
+
+	m.Reply(sb.String())
+	return tg.ErrEndGroup
+}
+
+// handleBroadcastReport shows the summary of a job, the latest one when no
+// id is given, with every recorded failure attached as a file.
+func handleBroadcastReport(m *tg.NewMessage, id string) error {
+	chatID := m.ChannelID()
+
+	var job *database.BroadcastJob
+	var err error
+	if id == "" {
+		var jobs []*database.BroadcastJob
+		if jobs, err = database.GetBroadcastJobs(); err == nil && len(jobs) > 0 {
+			job = jobs[0]
+		}
+	} else {
+		job, err = database.GetBroadcastJob(id)
+	}
+	if err != nil || job == nil {
+		m.Reply(F(chatID, "broadcast_job_not_found", locales.Arg{"id": html.EscapeString(id)}))
+		return tg.ErrEndGroup
+	}
+
+	broadcastMu.Lock()
+	rb := currentBroadcast
+	broadcastMu.Unlock()
+	if rb != nil && rb.job.ID == job.ID {
+		rb.mu.Lock()
+		text := formatBroadcastReport(rb.job, chatID)
+		failures := slices.Clone(rb.job.Failures)
+		rb.mu.Unlock()
+		return sendBroadcastReport(m, text, job.ID, failures)
+	}
+	return sendBroadcastReport(m, formatBroadcastReport(job, chatID), job.ID, job.Failures)
+}
+
+func sendBroadcastReport(
+	m *tg.NewMessage,
+	text, id string,
+	failures []database.BroadcastFailure,
+) error {
+	if len(failures) == 0 {
+		m.Reply(text)
 		return tg.ErrEndGroup
 	}
 
-	if broadcastCancel != nil {
-		broadcastCancel()
+	var sb strings.Builder
+	for _, f := range failures {
+		fmt.Fprintf(&sb, "%d\t%s\n", f.ID, f.Reason)
 	}
-	broadcastActive = false
-	broadcastCtx = nil
-	broadcastCancel = nil
+	path := filepath.Join(os.TempDir(), "broadcast_"+id+"_failures.txt")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		m.Reply(text)
+		return tg.ErrEndGroup
+	}
+	defer os.Remove(path)
 
-	m.Reply(F(m.ChannelID(), "broadcast_cancel_success"))
+	if len(text) > 1000 {
+		m.Reply(text)
+		text = ""
+	}
+	if _, err := m.ReplyMedia(path, &tg.MediaOptions{Caption: text, ForceDocument: true}); err != nil {
+		if text != "" {
+			m.Reply(text)
+		}
+		logger.ErrorF("Failed to send broadcast report %s: %v", id, err)
+	}
 	return tg.ErrEndGroup
 }
 
@@ -708,7 +1157,7 @@ func broadcastCancelCB(cb *tg.CallbackQuery) error {
 	broadcastMu.Lock()
 	defer broadcastMu.Unlock()
 
-	if !broadcastActive {
+	if currentBroadcast == nil {
 		cb.Answer(
 			F(cb.ChannelID(), "broadcast_cancel_none_running"),
 			&tg.CallbackOptions{Alert: true},
@@ -716,18 +1165,11 @@ func broadcastCancelCB(cb *tg.CallbackQuery) error {
 		return tg.ErrEndGroup
 	}
 
-	if broadcastCancel != nil {
-		broadcastCancel()
-	}
-
-	broadcastActive = false
-	broadcastCtx = nil
-	broadcastCancel = nil
+	currentBroadcast.cancel()
 	cb.Answer(
 		F(cb.ChannelID(), "broadcast_cancel_done"),
 		&tg.CallbackOptions{Alert: true},
 	)
-	cb.Edit(F(cb.ChannelID(), "broadcast_cancel_done"))
 	return tg.ErrEndGroup
 }
 
@@ -24,7 +24,6 @@ import (
 	"context"
 	"html"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -37,7 +36,7 @@ import (
 func onStreamEndHandler(chatID int64) {
 	ass, err := core.Assistants.ForChat(chatID)
 	if err != nil {
-		gologging.ErrorF("Failed to get Assistant for %d: %v", chatID, err)
+		logger.ErrorF("Failed to get assistant chat_id=%d: %v", chatID, err)
 		return
 	}
 	r, ok := core.GetRoom(chatID, ass)
@@ -57,6 +56,9 @@ func onStreamEndHandler(chatID int64) {
 	}
 
 	if len(r.Queue()) == 0 && r.Loop() == 0 {
+		if mystic := r.GetMystic(); mystic != nil && database.CleanNowPlaying(chatID) {
+			mystic.Delete()
+		}
 		r.Destroy()
 		core.Bot.SendMessage(chatID, F(chatID, "stream_queue_finished"))
 		return
@@ -68,12 +70,15 @@ func onStreamEndHandler(chatID int64) {
 		F(chatID, "stream_downloading_next"),
 	)
 	if err != nil {
-		gologging.ErrorF("[call.go] Failed to send msg: %v", err)
+		logger.ErrorF("[call.go] Failed to send msg: %v", err)
 	}
 
 	filePath, err := platforms.Download(context.Background(), t, mystic)
 	if err != nil {
-		gologging.ErrorF("Download failed for %s: %v", t.URL, err)
+		logger.ErrorF(
+			"Download failed chat_id=%d track_id=%s platform=%s: %v",
+			chatID, t.ID, t.Source, err,
+		)
 		utils.EOR(mystic, F(chatID, "stream_download_fail", locales.Arg{
 			"error": err.Error(),
 		}))
@@ -81,6 +86,10 @@ func onStreamEndHandler(chatID int64) {
 	}
 
 	if err := r.Play(t, filePath); err != nil {
+		logger.ErrorF(
+			"Play failed chat_id=%d track_id=%s assistant=%d: %v",
+			chatID, t.ID, ass.Index()+1, err,
+		)
 		utils.EOR(mystic, F(chatID, "stream_play_fail"))
 		return
 	}
@@ -0,0 +1,102 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+// callMeHandler has an assistant call the user privately and streams the
+// query into that call. The room id is the user id, so the usual control
+// commands work from the bot DM while the call is up.
+func callMeHandler(m *telegram.NewMessage) error {
+	chatID := m.ChannelID()
+
+	if !m.IsPrivate() {
+		m.Reply(F(chatID, "callme_private_only"))
+		return telegram.ErrEndGroup
+	}
+
+	mention := utils.MentionHTML(m.Sender)
+
+	prefs, err := database.GetChatPrefs(chatID)
+	if err != nil {
+		logger.ErrorF("Failed to get chat prefs chat_id=%d: %v", chatID, err)
+	}
+
+	r, replyMsg, err := prepareRoomAndSearchMessage(m, false, prefs)
+	if err != nil {
+		return telegram.ErrEndGroup
+	}
+
+	ass, err := core.Assistants.ForChat(chatID)
+	if err != nil {
+		r.Destroy()
+		utils.EOR(replyMsg, getErrorMessage(chatID, err))
+		return telegram.ErrEndGroup
+	}
+
+	isActive := r.IsActiveChat()
+	if !isActive {
+		if err := core.ReachUser(ass, m.Sender); err != nil {
+			r.Destroy()
+			utils.EOR(replyMsg, F(chatID, "callme_unreachable", locales.Arg{
+				"assistant": utils.MentionHTML(ass.User),
+			}))
+			return telegram.ErrEndGroup
+		}
+	}
+
+	tracks, err := safeGetTracks(m, replyMsg, chatID, false, prefs.SearchPlatform)
+	if err != nil {
+		utils.EOR(replyMsg, err.Error())
+		return telegram.ErrEndGroup
+	}
+	if len(tracks) == 0 {
+		utils.EOR(replyMsg, F(chatID, "no_song_found"))
+		return telegram.ErrEndGroup
+	}
+
+	tracks, availableSlots, err := filterAndTrimTracks(replyMsg, r, tracks, prefs)
+	if err != nil {
+		return telegram.ErrEndGroup
+	}
+
+	if !isActive {
+		replyMsg, _ = utils.EOR(replyMsg, F(chatID, "callme_calling", locales.Arg{
+			"assistant": utils.MentionHTML(ass.User),
+		}))
+	}
+
+	if err := playTracksAndRespond(
+		m, replyMsg, r, tracks, mention,
+		isActive, false, availableSlots,
+	); err != nil {
+		return err
+	}
+
+	return telegram.ErrEndGroup
+}
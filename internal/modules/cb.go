@@ -26,9 +26,7 @@ import (
 	"html"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -89,7 +87,7 @@ func roomHandle(cb *tg.CallbackQuery) error {
 	action = strings.TrimPrefix(action, "room:")
 
 	if action == "" {
-		gologging.WarnF("Missing action in data: %s", data)
+		logger.WarnF("Missing action in data: %s", data)
 		cb.Answer(F(cb.ChannelID(), "invalid_request"), opt)
 		return tg.ErrEndGroup
 	}
@@ -100,7 +98,7 @@ func roomHandle(cb *tg.CallbackQuery) error {
 	if strings.HasPrefix(cb.DataString(), "croom:") {
 		realChatID, err := database.GetCPlayID(chatID)
 		if err != nil {
-			gologging.ErrorF(
+			logger.ErrorF(
 				"Failed to get chat ID for cplay ID %d: %v",
 				chatID,
 				err,
@@ -128,11 +126,6 @@ func roomHandle(cb *tg.CallbackQuery) error {
 		return tg.ErrEndGroup
 	}
 
-	// Flood control
-	if !checkFloodControl(cb, chatID, opt) {
-		return tg.ErrEndGroup
-	}
-
 	// Handle seek actions
 	if strings.HasPrefix(action, "seek") {
 		return handleSeekAction(cb, r, action, opt)
@@ -143,7 +136,7 @@ func roomHandle(cb *tg.CallbackQuery) error {
 		return handler(cb, r, chatID)
 	}
 
-	gologging.WarnF("Unknown callback type: %s", action)
+	logger.WarnF("Unknown callback type: %s", action)
 	cb.Answer(F(cb.ChannelID(), "unknown_action"), opt)
 	return tg.ErrEndGroup
 }
@@ -169,6 +162,10 @@ func checkAdminOrAuth(
 	chatID int64,
 	opt *tg.CallbackOptions,
 ) bool {
+	// Buttons of a private call belong to the user being called
+	if chatID == cb.SenderID {
+		return true
+	}
 	isAdmin, err := utils.IsChatAdmin(cb.Client, chatID, cb.SenderID)
 	if err != nil || !isAdmin {
 		cb.Answer(F(cb.ChannelID(), "only_admin_or_auth_cb"), opt)
@@ -177,25 +174,9 @@ func checkAdminOrAuth(
 	return true
 }
 
-func checkFloodControl(
-	cb *tg.CallbackQuery,
-	chatID int64,
-	opt *tg.CallbackOptions,
-) bool {
-	key := fmt.Sprintf("room:%d:%d", cb.Sender.ID, chatID)
-	if remaining := utils.GetFlood(key); remaining > 0 {
-		cb.Answer(F(cb.ChannelID(), "flood_seconds", locales.Arg{
-			"duration": int(remaining.Seconds()),
-		}), opt)
-		return false
-	}
-	utils.SetFlood(key, 5*time.Second)
-	return true
-}
-
 func editMessage(cb *tg.CallbackQuery, text string) {
 	if _, err := cb.Edit(text); err != nil {
-		gologging.ErrorF("Edit error: %v", err)
+		logger.ErrorF("Edit error: %v", err)
 	}
 }
 
@@ -216,7 +197,7 @@ func handlePauseAction(
 ) error {
 	opt := &tg.CallbackOptions{Alert: true}
 
-	gologging.InfoF("Callback → pause, chatID=%d", chatID)
+	logger.InfoF("Callback → pause, chat_id=%d", chatID)
 
 	if r.IsPaused() {
 		remaining := r.RemainingResumeDuration()
@@ -232,7 +213,7 @@ func handlePauseAction(
 	}
 
 	if _, err := r.Pause(); err != nil {
-		gologging.ErrorF("Pause failed: %v", err)
+		logger.ErrorF("Pause failed: %v", err)
 		cb.Answer(F(cb.ChannelID(), "room_pause_failed", locales.Arg{
 			"error": err.Error(),
 		}), opt)
@@ -258,7 +239,7 @@ func handleResumeAction(
 ) error {
 	opt := &tg.CallbackOptions{Alert: true}
 
-	gologging.InfoF("Callback → resume, chatID=%d", chatID)
+	logger.InfoF("Callback → resume, chat_id=%d", chatID)
 
 	if !r.IsPaused() {
 		cb.Answer(F(cb.ChannelID(), "cb_already_playing"), opt)
@@ -266,7 +247,7 @@ func handleResumeAction(
 	}
 
 	if _, err := r.Resume(); err != nil {
-		gologging.ErrorF("Resume failed: %v", err)
+		logger.ErrorF("Resume failed: %v", err)
 		cb.Answer(F(cb.ChannelID(), "cb_resume_failed"), opt)
 		return tg.ErrEndGroup
 	}
@@ -286,16 +267,16 @@ func handleReplayAction(
 ) error {
 	opt := &tg.CallbackOptions{Alert: true}
 
-	gologging.InfoF("Callback → replay, chatID=%d", chatID)
+	logger.InfoF("Callback → replay, chat_id=%d", chatID)
 
 	mystic, err := cb.Respond(F(cb.ChannelID(), "cb_replaying"))
 	if err != nil {
-		gologging.ErrorF("Failed to send replay message: %v", err)
+		logger.ErrorF("Failed to send replay message: %v", err)
 		return tg.ErrEndGroup
 	}
 
 	if err := r.Replay(); err != nil {
-		gologging.ErrorF("Replay failed: %v", err)
+		logger.ErrorF("Replay failed: %v", err)
 		utils.EOR(mystic, F(cb.ChannelID(), "replay_failed", locales.Arg{
 			"error": err.Error(),
 		}))
@@ -339,7 +320,7 @@ func handleSkipAction(
 ) error {
 	opt := &tg.CallbackOptions{Alert: true}
 
-	gologging.InfoF("Callback → skip, chatID=%d", chatID)
+	logger.InfoF("Callback → skip, chat_id=%d", chatID)
 
 	if len(r.Queue()) == 0 && r.Loop() == 0 {
 		r.Destroy()
@@ -354,12 +335,12 @@ func handleSkipAction(
 
 	mystic, err := cb.Respond(F(cb.ChannelID(), "stream_downloading_next"))
 	if err != nil {
-		gologging.ErrorF("Failed to send message: %v", err)
+		logger.ErrorF("Failed to send message: %v", err)
 	}
 
 	path, err := platforms.Download(context.Background(), t, mystic)
 	if err != nil {
-		gologging.ErrorF("Download failed for %s: %v", t.URL, err)
+		logger.ErrorF("Download failed for %s: %v", t.URL, err)
 		utils.EOR(mystic, F(cb.ChannelID(), "stream_download_fail", locales.Arg{
 			"error": err.Error(),
 		}))
@@ -368,7 +349,7 @@ func handleSkipAction(
 	}
 
 	if err := r.Play(t, path); err != nil {
-		gologging.ErrorF("Play error: %v", err)
+		logger.ErrorF("Play error: %v", err)
 		utils.EOR(mystic, F(cb.ChannelID(), "stream_play_fail"))
 		cb.Answer(F(cb.ChannelID(), "cb_skip_play_failed"), opt)
 		return tg.ErrEndGroup
@@ -411,7 +392,7 @@ func handleStopAction(
 ) error {
 	opt := &tg.CallbackOptions{Alert: true}
 
-	gologging.InfoF("Callback → stop, chatID=%d", chatID)
+	logger.InfoF("Callback → stop, chat_id=%d", chatID)
 
 	r.Destroy()
 
@@ -572,6 +553,6 @@ func updatePlaybackMessage(
 		ParseMode:   "HTML",
 		ReplyMarkup: core.GetPlayMarkup(cb.ChannelID(), r, false),
 	}); err != nil {
-		gologging.ErrorF("Edit error: %v", err)
+		logger.ErrorF("Edit error: %v", err)
 	}
 }
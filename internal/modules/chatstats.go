@@ -0,0 +1,242 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+const (
+	listenSampleInterval = time.Minute
+	chatStatsDays        = 30
+	chatStatsTopTracks   = 5
+	chatStatsTopHours    = 3
+)
+
+// Audience of the last sampling pass, shown in /stats.
+var liveListeners, liveRooms atomic.Int64
+
+// SampleListeners counts the listeners of every playing room once per
+// listenSampleInterval and stores them for /chatstats and /stats.
+func SampleListeners() {
+	ticker := time.NewTicker(listenSampleInterval)
+	defer ticker.Stop()
+
+	sem := make(chan struct{}, 20)
+
+	for now := range ticker.C {
+		var (
+			wg               sync.WaitGroup
+			listeners, rooms atomic.Int64
+		)
+		for _, chatID := range core.GetAllRoomIDs() {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(id int64) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				if n, ok := sampleRoom(id, now); ok {
+					listeners.Add(int64(n))
+					rooms.Add(1)
+				}
+			}(chatID)
+		}
+		wg.Wait()
+
+		liveListeners.Store(listeners.Load())
+		liveRooms.Store(rooms.Load())
+	}
+}
+
+// sampleRoom stores the listener count of a playing room and reports it.
+func sampleRoom(chatID int64, at time.Time) (int, bool) {
+	// A private call always has its one listener
+	if chatID > 0 {
+		return 0, false
+	}
+
+	r, ok := core.GetRoom(chatID, nil)
+	if !ok || !r.IsActiveChat() || r.IsPaused() {
+		return 0, false
+	}
+	track := r.Track()
+	if track == nil {
+		return 0, false
+	}
+
+	ass, err := core.Assistants.ForChat(chatID)
+	if err != nil {
+		return 0, false
+	}
+	listeners, err := ass.Listeners(chatID)
+	if err != nil {
+		logger.DebugF("Failed to count listeners chat_id=%d: %v", chatID, err)
+		return 0, false
+	}
+
+	database.AddListenSample(&database.ListenSample{
+		ChatID:    chatID,
+		TrackID:   track.ID,
+		Title:     track.Title,
+		Source:    string(track.Source),
+		Listeners: listeners,
+		Interval:  listenSampleInterval,
+		At:        at,
+	})
+	return listeners, true
+}
+
+func chatStatsHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+	now := time.Now()
+
+	days, err := database.GetListenDays(chatID, now.AddDate(0, 0, 1-chatStatsDays))
+	if err != nil {
+		m.Reply(F(chatID, "chatstats_fetch_fail", locales.Arg{
+			"error": err.Error(),
+		}))
+		return tg.ErrEndGroup
+	}
+	if len(days) == 0 {
+		m.Reply(F(chatID, "chatstats_empty"))
+		return tg.ErrEndGroup
+	}
+
+	var (
+		total, today float64
+		peak         int
+		hours        = make(map[string]float64)
+	)
+	todayKey := database.ListenDayKey(now)
+	for _, d := range days {
+		total += d.Minutes
+		if d.Day == todayKey {
+			today = d.Minutes
+		}
+		peak = max(peak, d.Peak)
+		for h, v := range d.Hours {
+			hours[h] += v
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(F(chatID, "chatstats_header", locales.Arg{
+		"days": chatStatsDays,
+	}) + "\n\n")
+	sb.WriteString(F(chatID, "chatstats_minutes", locales.Arg{
+		"total": int(total),
+		"today": int(today),
+	}) + "\n")
+	sb.WriteString(F(chatID, "chatstats_peak", locales.Arg{
+		"peak": peak,
+	}) + "\n")
+	if busiest := busiestHours(hours); busiest != "" {
+		sb.WriteString(F(chatID, "chatstats_hours", locales.Arg{
+			"hours": busiest,
+		}) + "\n")
+	}
+
+	tracks, err := database.GetTopTrackListens(chatID, chatStatsTopTracks)
+	if err == nil && len(tracks) > 0 {
+		sb.WriteString("\n" + F(chatID, "chatstats_top_header") + "\n")
+		for i, t := range tracks {
+			sb.WriteString(F(chatID, "chatstats_track", locales.Arg{
+				"index": i + 1,
+				"title": html.EscapeString(utils.ShortTitle(t.Title, 35)),
+				"peak":  t.Peak,
+				"avg":   fmt.Sprintf("%.1f", t.Average()),
+			}) + "\n")
+		}
+	}
+
+	m.Reply(sb.String())
+	return tg.ErrEndGroup
+}
+
+// busiestHours lists the UTC hours with the most listener time, busiest
+// first.
+func busiestHours(hours map[string]float64) string {
+	keys := make([]string, 0, len(hours))
+	for h, v := range hours {
+		if v > 0 {
+			keys = append(keys, h)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return hours[keys[i]] > hours[keys[j]]
+	})
+	if len(keys) > chatStatsTopHours {
+		keys = keys[:chatStatsTopHours]
+	}
+	for i, h := range keys {
+		keys[i] = h + ":00"
+	}
+	return strings.Join(keys, ", ")
+}
+
+func getListenStats(chatID int64) string {
+	var sb strings.Builder
+
+	sb.WriteString(F(chatID, "stats_listen_header") + "\n")
+	sb.WriteString(F(chatID, "stats_listen_now", locales.Arg{
+		"listeners": liveListeners.Load(),
+		"rooms":     liveRooms.Load(),
+	}) + "\n")
+
+	now := time.Now()
+	for _, period := range []struct {
+		key  string
+		days int
+	}{
+		{"stats_listen_today", 1},
+		{"stats_listen_month", chatStatsDays},
+	} {
+		totals, err := database.GetListenTotals(now.AddDate(0, 0, 1-period.days))
+		if err != nil {
+			sb.WriteString(F(chatID, "stats_listen_err", locales.Arg{
+				"error": err.Error(),
+			}) + "\n")
+			break
+		}
+		sb.WriteString(F(chatID, period.key, locales.Arg{
+			"minutes": int(totals.Minutes),
+			"chats":   totals.Chats,
+			"peak":    totals.Peak,
+		}) + "\n")
+	}
+
+	return sb.String()
+}
@@ -40,25 +40,46 @@ var AllCommands = BotCommands{
 		{"start", "Start the bot."},
 		{"help", "Show help menu."},
 		{"ping", "Check if the bot is alive."},
+		{"callme", "Get a private call playing a song."},
 		{"sudolist", "List sudo users."},
 	},
 	PrivateSudoCommands: []*telegram.BotCommand{
 		{"ac", "Show active voice chats."},
 		{"stats", "Show bot stats."},
+		{"globaltop", "Show the most played songs across all chats."},
+		{"assistants", "Show assistant load and move chats."},
 
 		{"logger", "Enable/disable logger channel."},
 		{"autoleave", "Enable/disable auto leave."},
+		{"loglevel", "Show or change log levels."},
+		{"langpack", "Upload, list or remove locale packs."},
+
+		{"blacklistuser", "Blacklist a user."},
+		{"unblacklistuser", "Remove a user from the blacklist."},
+		{"blacklistchat", "Blacklist a chat."},
+		{"unblacklistchat", "Remove a chat from the blacklist."},
+		{"blacklist", "List blacklisted users and chats."},
 	},
 	PrivateOwnerCommands: []*telegram.BotCommand{
 		{"addsudo", "Add a sudo user."},
 		{"delsudo", "Remove a sudo user."},
 		{"maintenance", "Enable/disable maintenance mode."},
+		{"apikey", "Manage control API keys."},
+		{"backup", "Export all bot data."},
+		{"restore", "Restore bot data from a backup."},
+		{"prune", "Remove dead chats and users from the served lists."},
+		{"addassistant", "Add an assistant from a session string."},
+		{"removeassistant", "Drain and remove an assistant."},
 	},
 	// Commands for group chats
 	GroupUserCommands: []*telegram.BotCommand{
 		{"play", "Play a song."},
 		{"queue", "Show the queue."},
 		{"position", "Show the current position of the song."},
+		{"chatstats", "Show listener stats of this chat."},
+		{"top", "Show the most played songs."},
+		{"topusers", "Show who requested the most songs."},
+		{"wrongsong", "Pick another YouTube match for a Spotify song."},
 
 		{"reload", "Reload the admin cache."},
 		{"authlist", "List authorized users."},
@@ -91,6 +112,10 @@ var AllCommands = BotCommands{
 		{"end", "Stop the song."},
 		{"addauth", "Add a user to the authorized list."},
 		{"delauth", "Remove a user from the authorized list."},
+		{"settings", "Open the chat settings panel."},
+		{"filter", "Block tracks by title, source or channel."},
+		{"record", "Record the voice chat."},
+		{"radio", "Get the web radio link for this chat."},
 		{"channelplay", "Set a channel as the play channel."},
 		{"cfplay", "Force play a song in the linked channel."},
 		{"cpause", "Pause the current song in the linked channel."},
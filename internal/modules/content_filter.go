@@ -0,0 +1,242 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/platforms"
+	"main/internal/utils"
+)
+
+const (
+	maxFilterRules   = 50
+	maxFilterPattern = 200
+)
+
+var youtubeChannelURLRegex = regexp.MustCompile(
+	`(?i)youtube\.com/channel/(UC[\w-]{22})`,
+)
+
+func handleFilter(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	args := strings.Fields(m.Args())
+	if len(args) == 0 {
+		return showFilters(m)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list", "ls":
+		return showFilters(m)
+	case "add":
+		return editFilter(m, args[1:], true)
+	case "remove", "rm", "del":
+		return editFilter(m, args[1:], false)
+	}
+
+	m.Reply(F(chatID, "filter_usage"))
+	return tg.ErrEndGroup
+}
+
+func showFilters(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	f, err := database.GetContentFilter(chatID)
+	if err != nil {
+		m.Reply(F(chatID, "filter_fail", locales.Arg{"error": err.Error()}))
+		return tg.ErrEndGroup
+	}
+
+	if f.IsEmpty() {
+		m.Reply(F(chatID, "filter_empty"))
+		return tg.ErrEndGroup
+	}
+
+	var sb strings.Builder
+	sb.WriteString(F(chatID, "filter_list_header"))
+
+	writeRules := func(kind string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		sb.WriteString("\n\n<b>" + F(chatID, "filter_rule_"+kind) + ":</b>")
+		for _, v := range values {
+			sb.WriteString("\n• <code>" + html.EscapeString(v) + "</code>")
+		}
+	}
+	writeRules(database.FilterKeyword, f.Keywords)
+	writeRules(database.FilterRegex, f.Patterns)
+	writeRules(database.FilterPlatform, f.Platforms)
+	writeRules(database.FilterChannel, f.Channels)
+	if f.MaxResolution > 0 {
+		writeRules(database.FilterResolution, []string{strconv.Itoa(f.MaxResolution) + "p"})
+	}
+
+	m.Reply(sb.String())
+	return tg.ErrEndGroup
+}
+
+func editFilter(m *tg.NewMessage, args []string, add bool) error {
+	chatID := m.ChannelID()
+
+	if len(args) == 0 {
+		m.Reply(F(chatID, "filter_usage"))
+		return tg.ErrEndGroup
+	}
+
+	kind := strings.ToLower(args[0])
+	value := strings.TrimSpace(strings.Join(args[1:], " "))
+	if value == "" && !(kind == database.FilterResolution && !add) {
+		m.Reply(F(chatID, "filter_usage"))
+		return tg.ErrEndGroup
+	}
+
+	f, err := database.GetContentFilter(chatID)
+	if err != nil {
+		m.Reply(F(chatID, "filter_fail", locales.Arg{"error": err.Error()}))
+		return tg.ErrEndGroup
+	}
+
+	var list *[]string
+	switch kind {
+	case database.FilterKeyword, "word":
+		kind, list = database.FilterKeyword, &f.Keywords
+	case database.FilterRegex, "pattern":
+		kind, list = database.FilterRegex, &f.Patterns
+		if add {
+			if len(value) > maxFilterPattern {
+				m.Reply(F(chatID, "filter_regex_too_long", locales.Arg{
+					"max": maxFilterPattern,
+				}))
+				return tg.ErrEndGroup
+			}
+			if _, err := regexp.Compile(value); err != nil {
+				m.Reply(F(chatID, "filter_regex_invalid", locales.Arg{
+					"error": html.EscapeString(err.Error()),
+				}))
+				return tg.ErrEndGroup
+			}
+		}
+	case database.FilterPlatform, "source":
+		kind, list = database.FilterPlatform, &f.Platforms
+		if add {
+			name, ok := findPlatformName(value)
+			if !ok {
+				m.Reply(F(chatID, "filter_platform_unknown", locales.Arg{
+					"platforms": strings.Join(platformNames(), ", "),
+				}))
+				return tg.ErrEndGroup
+			}
+			value = name
+		}
+	case database.FilterChannel:
+		list = &f.Channels
+		if match := youtubeChannelURLRegex.FindStringSubmatch(value); match != nil {
+			value = match[1]
+		}
+	case database.FilterResolution, "res":
+		kind = database.FilterResolution
+		if !add {
+			if f.MaxResolution == 0 {
+				m.Reply(F(chatID, "filter_not_found"))
+				return tg.ErrEndGroup
+			}
+			value = strconv.Itoa(f.MaxResolution) + "p"
+			f.MaxResolution = 0
+			break
+		}
+		height, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(value), "p"))
+		if err != nil || height < 144 || height > 2160 {
+			m.Reply(F(chatID, "filter_resolution_invalid"))
+			return tg.ErrEndGroup
+		}
+		f.MaxResolution = height
+		value = strconv.Itoa(height) + "p"
+	default:
+		m.Reply(F(chatID, "filter_usage"))
+		return tg.ErrEndGroup
+	}
+
+	if list != nil {
+		i := slices.IndexFunc(*list, func(v string) bool {
+			if kind == database.FilterRegex {
+				return v == value
+			}
+			return strings.EqualFold(v, value)
+		})
+
+		switch {
+		case add && i >= 0:
+			m.Reply(F(chatID, "filter_exists"))
+			return tg.ErrEndGroup
+		case add && len(*list) >= maxFilterRules:
+			m.Reply(F(chatID, "filter_too_many", locales.Arg{
+				"max": maxFilterRules,
+			}))
+			return tg.ErrEndGroup
+		case add:
+			*list = append(*list, value)
+		case i < 0:
+			m.Reply(F(chatID, "filter_not_found"))
+			return tg.ErrEndGroup
+		default:
+			*list = slices.Delete(*list, i, i+1)
+		}
+	}
+
+	if err := database.SetContentFilter(chatID, f); err != nil {
+		m.Reply(F(chatID, "filter_fail", locales.Arg{"error": err.Error()}))
+		return tg.ErrEndGroup
+	}
+
+	key := utils.IfElse(add, "filter_added", "filter_removed")
+	m.Reply(F(chatID, key, locales.Arg{
+		"rule":  F(chatID, "filter_rule_"+kind),
+		"value": html.EscapeString(value),
+	}))
+	return tg.ErrEndGroup
+}
+
+func platformNames() []string {
+	var names []string
+	for _, p := range platforms.GetOrderedPlatforms() {
+		names = append(names, string(p.Name()))
+	}
+	return names
+}
+
+func findPlatformName(name string) (string, bool) {
+	for _, n := range platformNames() {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	return "", false
+}
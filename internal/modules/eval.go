@@ -30,7 +30,6 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 	"github.com/traefik/yaegi/interp"
 	"github.com/traefik/yaegi/stdlib"
@@ -102,7 +101,7 @@ Examples:
 		},
 	}
 	if err := i.Use(symbols); err != nil {
-		gologging.ErrorF("failed to use custom symbols: %v", err)
+		logger.ErrorF("failed to use custom symbols: %v", err)
 	}
 	ctx := context.Background()
 
@@ -33,6 +33,7 @@ import (
 
 var (
 	superGroupFilter    = tg.Custom(filterSuperGroup)
+	callFilter          = tg.Custom(filterSuperGroupOrCall)
 	adminFilter         = tg.Custom(filterChatAdmins)
 	authFilter          = tg.Custom(filterAuthUsers)
 	ignoreChannelFilter = tg.Custom(filterChannel)
@@ -68,6 +69,17 @@ func filterSuperGroup(m *tg.NewMessage) bool {
 	return false
 }
 
+// filterSuperGroupOrCall also lets control commands through in the bot DM
+// of a user who has a /callme call running.
+func filterSuperGroupOrCall(m *tg.NewMessage) bool {
+	if m.IsPrivate() {
+		if _, ok := core.GetRoom(m.ChannelID(), nil); ok {
+			return true
+		}
+	}
+	return filterSuperGroup(m)
+}
+
 func filterChatAdmins(m *tg.NewMessage) bool {
 	isAdmin, err := utils.IsChatAdmin(m.Client, m.ChannelID(), m.SenderID())
 	if err != nil || !isAdmin {
@@ -78,6 +90,11 @@ func filterChatAdmins(m *tg.NewMessage) bool {
 }
 
 func filterAuthUsers(m *tg.NewMessage) bool {
+	// The user owns their private call
+	if m.ChannelID() == m.SenderID() {
+		return true
+	}
+
 	isAdmin, err := utils.IsChatAdmin(m.Client, m.ChannelID(), m.SenderID())
 	if err == nil && isAdmin {
 		return true
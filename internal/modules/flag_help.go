@@ -22,12 +22,46 @@ package modules
 
 import (
 	"html"
+	"slices"
 	"strings"
 
 	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/locales"
 )
 
-var helpTexts = map[string]string{}
+// Help texts live in the locale files as cmdhelp_<command>. helpAliases
+// maps alternative command names to the command whose help they share.
+var helpAliases = map[string]string{
+	"ac":          "active",
+	"activevc":    "active",
+	"activevoice": "active",
+	"gcast":       "broadcast",
+	"bcast":       "broadcast",
+	"bash":        "sh",
+	"shell":       "sh",
+	"eval":        "ev",
+	"maint":       "maintenance",
+	"playforce":   "fplay",
+	"fcplay":      "cfplay",
+	"cvplay":      "vcplay",
+	"stop":        "end",
+
+	"delassistant": "removeassistant",
+	"rmassistant":  "removeassistant",
+}
+
+// channelHelpCommands are the channel play variants; their help wraps the
+// help of the command without the "c" prefix.
+var channelHelpCommands = []string{
+	"cfplay", "vcplay", "fvcplay",
+	"cpause", "cresume", "cskip", "cstop",
+	"cmute", "cunmute", "cseek", "cseekback",
+	"cjump", "cremove", "cclear", "cmove",
+	"cspeed", "creplay", "cposition", "cshuffle",
+	"cloop", "cqueue", "creload",
+}
 
 func checkForHelpFlag(m *tg.NewMessage) bool {
 	text := strings.Fields(strings.ToLower(strings.TrimSpace(m.Text())))
@@ -40,26 +74,52 @@ func checkForHelpFlag(m *tg.NewMessage) bool {
 	return false
 }
 
+// helpText returns the help of cmd (with or without the leading slash) in
+// the chat's language.
+func helpText(chatID int64, cmd string) (string, bool) {
+	name := strings.ToLower(strings.TrimPrefix(cmd, "/"))
+	if alias, ok := helpAliases[name]; ok {
+		name = alias
+	}
+
+	lang := chatLanguage(chatID)
+	args := locales.Arg{
+		"support_chat":    config.SupportChat,
+		"max_auth_users":  config.MaxAuthUsers,
+		"autoleave_limit": limit,
+	}
+
+	if slices.Contains(channelHelpCommands, name) &&
+		locales.Exists(lang, "cmdhelp_"+name[1:]) {
+		return FWithLang(lang, "cmdhelp_channel_variant", locales.Arg{
+			"cmd":  "/" + name[1:],
+			"help": FWithLang(lang, "cmdhelp_"+name[1:], args),
+		}), true
+	}
+
+	if !locales.Exists(lang, "cmdhelp_"+name) {
+		return "", false
+	}
+	return FWithLang(lang, "cmdhelp_"+name, args), true
+}
+
 func showHelpFor(m *tg.NewMessage, cmd string) error {
-	help, ok := helpTexts[cmd]
+	chatID := m.ChannelID()
+
+	help, ok := helpText(chatID, cmd)
 	if !ok {
-		alt := strings.TrimPrefix(cmd, "/")
-		if h, ok := helpTexts[alt]; ok {
-			help = h
-		}
-	}
-	if help == "" {
-		_, err := m.Reply(
-			"⚠️ <i>No help found for command <code>" + html.EscapeString(
-				cmd,
-			) + "</code></i>",
-		)
+		_, err := m.Reply(F(chatID, "help_not_found", locales.Arg{
+			"cmd": html.EscapeString(cmd),
+		}))
 		if err != nil {
 			return err
 		}
 		return tg.ErrEndGroup
 	}
-	_, err := m.Reply("📘 <b>Help for</b> <code>" + cmd + "</code>:\n\n" + help)
+	_, err := m.Reply(F(chatID, "help_for", locales.Arg{
+		"cmd":  html.EscapeString(cmd),
+		"help": help,
+	}))
 	if err != nil {
 		return err
 	}
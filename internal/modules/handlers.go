@@ -21,10 +21,9 @@
 package modules
 
 import (
-	"fmt"
 	"log"
+	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -34,15 +33,17 @@ import (
 )
 
 type MsgHandlerDef struct {
-	Pattern string
-	Handler telegram.MessageHandler
-	Filters []telegram.Filter
+	Pattern   string
+	Handler   telegram.MessageHandler
+	Filters   []telegram.Filter
+	RateGroup rateGroup
 }
 
 type CbHandlerDef struct {
-	Pattern string
-	Handler telegram.CallbackHandler
-	Filters []telegram.Filter
+	Pattern   string
+	Handler   telegram.CallbackHandler
+	Filters   []telegram.Filter
+	RateGroup rateGroup
 }
 
 var handlers = []MsgHandlerDef{
@@ -84,6 +85,32 @@ var handlers = []MsgHandlerDef{
 		Filters: []telegram.Filter{ignoreChannelFilter},
 	},
 
+	{
+		Pattern: "(blacklistuser|bluser|gban)",
+		Handler: handleBlacklistUser,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "(unblacklistuser|unbluser|ungban)",
+		Handler: handleUnblacklistUser,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "(blacklistchat|blchat)",
+		Handler: handleBlacklistChat,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "(unblacklistchat|unblchat)",
+		Handler: handleUnblacklistChat,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "(blacklist|blacklisted)",
+		Handler: handleBlacklist,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+
 	{
 		Pattern: "(speedtest|spt)",
 		Handler: sptHandle,
@@ -101,11 +128,51 @@ var handlers = []MsgHandlerDef{
 		Handler: activeHandler,
 		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
 	},
+	{
+		Pattern: "globaltop",
+		Handler: globalTopHandler,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "assistants",
+		Handler: assistantsHandler,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "addassistant",
+		Handler: addAssistantHandler,
+		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "(removeassistant|delassistant|rmassistant)",
+		Handler: removeAssistantHandler,
+		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
+	},
 	{
 		Pattern: "(maintenance|maint)",
 		Handler: handleMaintenance,
 		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
 	},
+	{
+		Pattern: "(apikey|apikeys)",
+		Handler: apiKeyHandler,
+		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "backup",
+		Handler: backupHandler,
+		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "restore",
+		Handler: restoreHandler,
+		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "prune",
+		Handler: pruneHandler,
+		Filters: []telegram.Filter{ownerFilter, ignoreChannelFilter},
+	},
 	{
 		Pattern: "logger",
 		Handler: handleLogger,
@@ -121,6 +188,16 @@ var handlers = []MsgHandlerDef{
 		Handler: logsHandler,
 		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
 	},
+	{
+		Pattern: "(loglevel|loglevels)",
+		Handler: logLevelHandler,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
+	{
+		Pattern: "(langpack|langpacks)",
+		Handler: langPackHandler,
+		Filters: []telegram.Filter{sudoOnlyFilter, ignoreChannelFilter},
+	},
 
 	{
 		Pattern: "help",
@@ -148,218 +225,313 @@ var handlers = []MsgHandlerDef{
 		Filters: []telegram.Filter{ignoreChannelFilter},
 	},
 	{
-		Pattern: "(lang|language)",
-		Handler: langHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(lang|language)",
+		Handler:   langHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateAdmin,
+	},
+	{
+		Pattern:   "callme",
+		Handler:   callMeHandler,
+		Filters:   []telegram.Filter{ignoreChannelFilter},
+		RateGroup: ratePlay,
 	},
 
 	// SuperGroup & Admin Filters
 
 	{
-		Pattern: "stream",
-		Handler: streamHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Pattern:   "stream",
+		Handler:   streamHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "streamstop",
-		Handler: streamStopHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "streamstop",
+		Handler:   streamStopHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
+	},
+	{
+		Pattern:   "chatstats",
+		Handler:   chatStatsHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: rateControl,
+	},
+	{
+		Pattern:   "top",
+		Handler:   topHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: rateControl,
+	},
+	{
+		Pattern:   "topusers",
+		Handler:   topUsersHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: rateControl,
 	},
 	{
 		Pattern: "streamstatus",
 		Handler: streamStatusHandler,
 		Filters: []telegram.Filter{superGroupFilter},
 	},
-	{Pattern: "(rtmp|setrtmp)", Handler: setRTMPHandler},
+	{
+		Pattern:   "(rtmp|setrtmp)",
+		Handler:   setRTMPHandler,
+		RateGroup: rateAdmin,
+	},
+	{
+		Pattern:   "radio",
+		Handler:   radioHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
+	},
 
 	// play/cplay/vplay/fplay commands
 	{
-		Pattern: "play",
-		Handler: playHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Pattern:   "play",
+		Handler:   playHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "(fplay|playforce)",
-		Handler: fplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(fplay|playforce)",
+		Handler:   fplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "cplay",
-		Handler: cplayHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Pattern:   "cplay",
+		Handler:   cplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "(cfplay|fcplay|cplayforce)",
-		Handler: cfplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(cfplay|fcplay|cplayforce)",
+		Handler:   cfplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "vplay",
-		Handler: vplayHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Pattern:   "vplay",
+		Handler:   vplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "(fvplay|vfplay|vplayforce)",
-		Handler: fvplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(fvplay|vfplay|vplayforce)",
+		Handler:   fvplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "(vcplay|cvplay)",
-		Handler: vcplayHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Pattern:   "(vcplay|cvplay)",
+		Handler:   vcplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "(fvcplay|fvcpay|vcplayforce)",
-		Handler: fvcplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(fvcplay|fvcpay|vcplayforce)",
+		Handler:   fvcplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
 	},
 
 	{
-		Pattern: "(speed|setspeed|speedup)",
-		Handler: speedHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(speed|setspeed|speedup)",
+		Handler:   speedHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "skip",
-		Handler: skipHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "skip",
+		Handler:   skipHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "pause",
-		Handler: pauseHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "pause",
+		Handler:   pauseHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "resume",
-		Handler: resumeHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "resume",
+		Handler:   resumeHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "replay",
-		Handler: replayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "replay",
+		Handler:   replayHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "mute",
-		Handler: muteHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "wrongsong",
+		Handler:   wrongSongHandler,
+		Filters:   []telegram.Filter{callFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "unmute",
-		Handler: unmuteHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "mute",
+		Handler:   muteHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "seek",
-		Handler: seekHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "unmute",
+		Handler:   unmuteHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "seekback",
-		Handler: seekbackHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "seek",
+		Handler:   seekHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "jump",
-		Handler: jumpHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "seekback",
+		Handler:   seekbackHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
+	},
+	{
+		Pattern:   "jump",
+		Handler:   jumpHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
 		Pattern: "position",
 		Handler: positionHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Filters: []telegram.Filter{callFilter},
 	},
 	{
 		Pattern: "queue",
 		Handler: queueHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Filters: []telegram.Filter{callFilter},
 	},
 	{
-		Pattern: "clear",
-		Handler: clearHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "clear",
+		Handler:   clearHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "remove",
-		Handler: removeHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "remove",
+		Handler:   removeHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "move",
-		Handler: moveHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "move",
+		Handler:   moveHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "shuffle",
-		Handler: shuffleHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "shuffle",
+		Handler:   shuffleHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "(loop|setloop)",
-		Handler: loopHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(loop|setloop)",
+		Handler:   loopHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "(end|stop)",
-		Handler: stopHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(end|stop)",
+		Handler:   stopHandler,
+		Filters:   []telegram.Filter{callFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "reload",
-		Handler: reloadHandler,
-		Filters: []telegram.Filter{superGroupFilter},
+		Pattern:   "reload",
+		Handler:   reloadHandler,
+		Filters:   []telegram.Filter{superGroupFilter},
+		RateGroup: rateAdmin,
 	},
 	{
-		Pattern: "addauth",
-		Handler: addAuthHandler,
-		Filters: []telegram.Filter{superGroupFilter, adminFilter},
+		Pattern:   "addauth",
+		Handler:   addAuthHandler,
+		Filters:   []telegram.Filter{superGroupFilter, adminFilter},
+		RateGroup: rateAdmin,
 	},
 	{
-		Pattern: "delauth",
-		Handler: delAuthHandler,
-		Filters: []telegram.Filter{superGroupFilter, adminFilter},
+		Pattern:   "delauth",
+		Handler:   delAuthHandler,
+		Filters:   []telegram.Filter{superGroupFilter, adminFilter},
+		RateGroup: rateAdmin,
 	},
 	{
 		Pattern: "authlist",
 		Handler: authListHandler,
 		Filters: []telegram.Filter{superGroupFilter},
 	},
+	{
+		Pattern:   "settings",
+		Handler:   settingsHandler,
+		Filters:   []telegram.Filter{superGroupFilter, adminFilter},
+		RateGroup: rateAdmin,
+	},
+	{
+		Pattern:   "record",
+		Handler:   recordHandler,
+		Filters:   []telegram.Filter{superGroupFilter, adminFilter},
+		RateGroup: rateAdmin,
+	},
+	{
+		Pattern:   "(filter|filters)",
+		Handler:   handleFilter,
+		Filters:   []telegram.Filter{superGroupFilter, adminFilter},
+		RateGroup: rateAdmin,
+	},
 
 	// CPlay commands
 	{
-		Pattern: "(cplay|cvplay)",
-		Handler: cplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(cplay|cvplay)",
+		Handler:   cplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "(cfplay|fcplay|cforceplay)",
-		Handler: cfplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(cfplay|fcplay|cforceplay)",
+		Handler:   cfplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: ratePlay,
 	},
 	{
-		Pattern: "cpause",
-		Handler: cpauseHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cpause",
+		Handler:   cpauseHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cresume",
-		Handler: cresumeHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cresume",
+		Handler:   cresumeHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cmute",
-		Handler: cmuteHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cmute",
+		Handler:   cmuteHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cunmute",
-		Handler: cunmuteHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cunmute",
+		Handler:   cunmuteHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "(cstop|cend)",
-		Handler: cstopHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(cstop|cend)",
+		Handler:   cstopHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
 		Pattern: "cqueue",
@@ -367,59 +539,70 @@ var handlers = []MsgHandlerDef{
 		Filters: []telegram.Filter{superGroupFilter, authFilter},
 	},
 	{
-		Pattern: "cskip",
-		Handler: cskipHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cskip",
+		Handler:   cskipHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "(cloop|csetloop)",
-		Handler: cloopHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(cloop|csetloop)",
+		Handler:   cloopHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cseek",
-		Handler: cseekHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cseek",
+		Handler:   cseekHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cseekback",
-		Handler: cseekbackHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cseekback",
+		Handler:   cseekbackHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cjump",
-		Handler: cjumpHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cjump",
+		Handler:   cjumpHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cremove",
-		Handler: cremoveHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cremove",
+		Handler:   cremoveHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cclear",
-		Handler: cclearHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cclear",
+		Handler:   cclearHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "cmove",
-		Handler: cmoveHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cmove",
+		Handler:   cmoveHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "channelplay",
-		Handler: channelPlayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "channelplay",
+		Handler:   channelPlayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateAdmin,
 	},
 	{
-		Pattern: "(cspeed|csetspeed|cspeedup)",
-		Handler: cspeedHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "(cspeed|csetspeed|cspeedup)",
+		Handler:   cspeedHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "creplay",
-		Handler: creplayHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "creplay",
+		Handler:   creplayHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
 		Pattern: "cposition",
@@ -427,14 +610,16 @@ var handlers = []MsgHandlerDef{
 		Filters: []telegram.Filter{superGroupFilter, authFilter},
 	},
 	{
-		Pattern: "cshuffle",
-		Handler: cshuffleHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "cshuffle",
+		Handler:   cshuffleHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateControl,
 	},
 	{
-		Pattern: "creload",
-		Handler: creloadHandler,
-		Filters: []telegram.Filter{superGroupFilter, authFilter},
+		Pattern:   "creload",
+		Handler:   creloadHandler,
+		Filters:   []telegram.Filter{superGroupFilter, authFilter},
+		RateGroup: rateAdmin,
 	},
 }
 
@@ -442,13 +627,18 @@ var cbHandlers = []CbHandlerDef{
 	{Pattern: "start", Handler: startCB},
 	{Pattern: "help_cb", Handler: helpCB},
 	{Pattern: "^lang:[a-z]", Handler: langCallbackHandler},
+	{Pattern: `^settings:\w+$`, Handler: settingsCB, RateGroup: rateAdmin},
 	{Pattern: `^help:(.+)`, Handler: helpCallbackHandler},
 
 	{Pattern: "^close$", Handler: closeHandler},
 	{Pattern: "^cancel$", Handler: cancelHandler},
 	{Pattern: "^bcast_cancel$", Handler: broadcastCancelCB},
+	{Pattern: "^restore:(merge|replace|cancel)$", Handler: restoreCB},
+	{Pattern: "^prune:(confirm|cancel)$", Handler: pruneCB},
+	{Pattern: "^wrongsong:", Handler: wrongSongCB, RateGroup: rateControl},
+	{Pattern: "^record:stop$", Handler: recordCB, RateGroup: rateAdmin},
 
-	{Pattern: `^room:(\w+)$`, Handler: roomHandle},
+	{Pattern: `^room:(\w+)$`, Handler: roomHandle, RateGroup: rateControl},
 	{Pattern: "progress", Handler: emptyCBHandler},
 }
 
@@ -458,13 +648,15 @@ func Init(bot *telegram.Client, assistants *core.AssistantManager) {
 		a.Client.UpdatesGetState()
 	})
 
+	loadLocalePacks()
+
 	for _, h := range handlers {
-		bot.AddCommandHandler(h.Pattern, SafeMessageHandler(h.Handler), h.Filters...).
+		bot.AddCommandHandler(h.Pattern, SafeMessageHandler(rateLimitMessages(h.RateGroup, h.Handler)), h.Filters...).
 			SetGroup(100)
 	}
 
 	for _, h := range cbHandlers {
-		bot.AddCallbackHandler(h.Pattern, SafeCallbackHandler(h.Handler), h.Filters...).
+		bot.AddCallbackHandler(h.Pattern, SafeCallbackHandler(rateLimitCallbacks(h.RateGroup, h.Handler)), h.Filters...).
 			SetGroup(90)
 	}
 
@@ -477,9 +669,21 @@ func Init(bot *telegram.Client, assistants *core.AssistantManager) {
 
 	assistants.ForEach(func(a *core.Assistant) {
 		a.Ntg.OnStreamEnd(ntgOnStreamEnd)
+		a.Ntg.OnParticipantsChange(onParticipantsChange)
 	})
+	assistants.OnAdd(func(a *core.Assistant) {
+		a.Client.UpdatesGetState()
+		a.Ntg.OnStreamEnd(ntgOnStreamEnd)
+		a.Ntg.OnParticipantsChange(onParticipantsChange)
+	})
+
+	core.OnRecordingDone(onRecordingDone)
+	core.OnPlayEnded(onPlayEnded)
 
 	go MonitorRooms()
+	go SampleListeners()
+	go RunBroadcasts()
+	go assistants.MonitorHealth(30 * time.Second)
 
 	if is, _ := database.GetAutoLeave(); is {
 		go startAutoLeave()
@@ -488,30 +692,6 @@ func Init(bot *telegram.Client, assistants *core.AssistantManager) {
 	if config.SetCmds && config.OwnerID != 0 {
 		go setBotCommands(bot)
 	}
-
-	cplayCommands := []string{
-		"/cfplay", "/vcplay", "/fvcplay",
-		"/cpause", "/cresume", "/cskip", "/cstop",
-		"/cmute", "/cunmute", "/cseek", "/cseekback",
-		"/cjump", "/cremove", "/cclear", "/cmove",
-		"/cspeed", "/creplay", "/cposition", "/cshuffle",
-		"/cloop", "/cqueue", "/creload",
-	}
-
-	for _, cmd := range cplayCommands {
-		baseCmd := "/" + cmd[2:] // Remove 'c' prefix
-		if baseHelp, exists := helpTexts[baseCmd]; exists {
-			helpTexts[cmd] = fmt.Sprintf(`<i>Channel play variant of %s</i>
-
-<b>⚙️ Requires:</b>
-First configure channel using: <code>/channelplay --set [channel_id]</code>
-
-%s
-
-<b>💡 Note:</b>
-This command affects the linked channel's voice chat, not the current group.`, baseCmd, baseHelp)
-		}
-	}
 }
 
 func ntgOnStreamEnd(
@@ -525,12 +705,12 @@ func ntgOnStreamEnd(
 func setBotCommands(bot *telegram.Client) {
 	// Set commands for normal users in private chats
 	if _, err := bot.BotsSetBotCommands(&telegram.BotCommandScopeUsers{}, "", AllCommands.PrivateUserCommands); err != nil {
-		gologging.Error("Failed to set PrivateUserCommands " + err.Error())
+		logger.Error("Failed to set PrivateUserCommands " + err.Error())
 	}
 
 	// Set commands for normal users in group chats
 	if _, err := bot.BotsSetBotCommands(&telegram.BotCommandScopeChats{}, "", AllCommands.GroupUserCommands); err != nil {
-		gologging.Error("Failed to set GroupUserCommands " + err.Error())
+		logger.Error("Failed to set GroupUserCommands " + err.Error())
 	}
 
 	// Set commands for chat admins
@@ -539,7 +719,7 @@ func setBotCommands(bot *telegram.Client) {
 		"",
 		append(AllCommands.GroupUserCommands, AllCommands.GroupAdminCommands...),
 	); err != nil {
-		gologging.Error("Failed to set GroupAdminCommands " + err.Error())
+		logger.Error("Failed to set GroupAdminCommands " + err.Error())
 	}
 
 	// Set commands for sudo users in their private chat
@@ -555,7 +735,7 @@ func setBotCommands(bot *telegram.Client) {
 				"",
 				sudoCommands,
 			); err != nil {
-				gologging.Error("Failed to set PrivateSudoCommands " + err.Error())
+				logger.Error("Failed to set PrivateSudoCommands " + err.Error())
 			}
 		}
 	}
@@ -567,6 +747,6 @@ func setBotCommands(bot *telegram.Client) {
 	if _, err := bot.BotsSetBotCommands(&telegram.BotCommandScopePeer{
 		Peer: &telegram.InputPeerUser{UserID: config.OwnerID, AccessHash: 0},
 	}, "", ownerCommands); err != nil {
-		gologging.Error("Failed to set PrivateOwnerCommands " + err.Error())
+		logger.Error("Failed to set PrivateOwnerCommands " + err.Error())
 	}
 }
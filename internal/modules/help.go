@@ -21,33 +21,13 @@
 package modules
 
 import (
-	"fmt"
 	"strings"
 
 	tg "github.com/amarnathcjd/gogram/telegram"
 
-	"main/internal/config"
 	"main/internal/core"
 )
 
-func init() {
-	helpTexts["/help"] = fmt.Sprintf(`ℹ️ <b>Help Command</b>
-<i>Displays general bot help or detailed information about a specific command.</i>
-
-<u>Usage:</u>
-<code>/help</code> — Show the main help menu.  
-<code>/help &lt;command&gt;</code> — Show help for a specific command.
-
-<b>💡 Tip:</b> You can view help for any command directly by adding a <code>-h</code> or <code>--help</code> flag, e.g. <code>/play -h</code>
-
-<b>⚠️ Note:</b> Some commands are <b>restricted</b> to specific contexts (like <b>Groups</b>, <b>Admins</b>, <b>Sudoers</b>, or the <b>Owner</b>).  
-If you try using <code>-h</code> or <code>--help</code> inside a restricted chat or PM, the bot may not respond.  
-To still view help for those commands, use the global format instead:
-<code>/help &lt;command&gt;</code>
-
-For more info, visit our <a href="%s">Support Chat</a>.`, config.SupportChat)
-}
-
 func helpHandler(m *tg.NewMessage) error {
 	args := strings.Fields(m.Text())
 	if len(args) > 1 {
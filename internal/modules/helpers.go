@@ -29,7 +29,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -40,7 +39,10 @@ import (
 	"main/internal/utils"
 )
 
-var downloadCancels = make(map[int64]context.CancelFunc)
+var (
+	logger          = config.GetLogger("modules")
+	downloadCancels = make(map[int64]context.CancelFunc)
+)
 
 func getEffectiveRoom(m *tg.NewMessage, cplay bool) (*core.RoomState, error) {
 	chatID := m.ChannelID()
@@ -67,7 +69,7 @@ func sendPlayLogs(m *tg.NewMessage, track *state.Track, queued bool) {
 	}
 
 	if is, err := database.IsLoggerEnabled(); err != nil {
-		gologging.Error("Failed to get IsLoggerEnabled: " + err.Error())
+		logger.Error("Failed to get IsLoggerEnabled: " + err.Error())
 		return
 	} else if !is {
 		return
@@ -87,7 +89,7 @@ func sendPlayLogs(m *tg.NewMessage, track *state.Track, queued bool) {
 
 	// Header
 	sb.WriteString("🎵 ")
-	if m.Channel.Username != "" {
+	if m.Channel != nil && m.Channel.Username != "" {
 		fmt.Fprintf(&sb, "<b><a href=\"%s\">%s</a></b>\n\n", m.Link(), header)
 	} else {
 		fmt.Fprintf(&sb, "<b><u>%s</u></b>\n\n", header)
@@ -115,9 +117,12 @@ func sendPlayLogs(m *tg.NewMessage, track *state.Track, queued bool) {
 
 	// Group
 	fmt.Fprintf(&sb, "<b>%s</b> ", F(chatID, "logger_group"))
-	if m.Channel.Username != "" {
+	switch {
+	case m.Channel == nil:
+		sb.WriteString(F(chatID, "logger_private_call"))
+	case m.Channel.Username != "":
 		fmt.Fprintf(&sb, "@%s", m.Channel.Username)
-	} else {
+	default:
 		sb.WriteString(m.Channel.Title)
 	}
 	fmt.Fprintf(&sb, " (%d)\n", m.ChannelID())
@@ -150,21 +155,21 @@ func sendPlayLogs(m *tg.NewMessage, track *state.Track, queued bool) {
 	}
 
 	if err != nil {
-		gologging.Error("Failed to send logger msg: " + err.Error())
+		logger.Error("Failed to send logger msg: " + err.Error())
 	}
 }
 
 func F(chatID int64, key string, values ...locales.Arg) string {
+	return FWithLang(chatLanguage(chatID), key, values...)
+}
+
+func chatLanguage(chatID int64) string {
 	lang, err := database.GetChatLanguage(chatID)
 	if err != nil {
-		gologging.Error(
-			"Failed to get language for " + utils.IntToStr(
-				chatID,
-			) + " Got error " + err.Error(),
-		)
+		logger.ErrorF("Failed to get language chat_id=%d: %v", chatID, err)
 		lang = config.DefaultLang
 	}
-	return FWithLang(lang, key, values...)
+	return lang
 }
 
 func FWithLang(lang, key string, values ...locales.Arg) string {
@@ -179,7 +184,7 @@ func isLogger() (l bool) {
 	var err error
 	l, err = database.IsLoggerEnabled()
 	if err != nil {
-		gologging.Error("Failed to get IsLoggerEnabled, Err: " + err.Error())
+		logger.Error("Failed to get IsLoggerEnabled, Err: " + err.Error())
 	}
 	return l
 }
@@ -188,6 +193,27 @@ func SafeCallbackHandler(
 	handler func(*tg.CallbackQuery) error,
 ) func(*tg.CallbackQuery) error {
 	return func(cb *tg.CallbackQuery) (err error) {
+		if cb.Sender.ID != config.OwnerID &&
+			database.IsBlacklistedUser(cb.Sender.ID) {
+			cb.Answer(
+				F(cb.ChannelID(), "blacklist_user_blocked"),
+				&tg.CallbackOptions{Alert: true},
+			)
+			return tg.ErrEndGroup
+		}
+
+		if chatID := cb.ChannelID(); cb.Sender.ID != config.OwnerID &&
+			cb.ChatType() != tg.EntityUser &&
+			database.IsBlacklistedChat(chatID) &&
+			!database.IsSudoWithoutError(cb.Sender.ID) {
+			cb.Answer(
+				F(chatID, "blacklist_chat_added_leave"),
+				&tg.CallbackOptions{Alert: true},
+			)
+			go leaveBlacklistedChat(cb.Client, chatID)
+			return tg.ErrEndGroup
+		}
+
 		if is, _ := database.IsMaintenance(); is {
 			if cb.Sender.ID != config.OwnerID {
 				if ok, _ := database.IsSudo(cb.Sender.ID); !ok {
@@ -220,16 +246,18 @@ func SafeMessageHandler(
 	handler func(*tg.NewMessage) error,
 ) func(*tg.NewMessage) error {
 	return func(m *tg.NewMessage) (err error) {
-		gologging.Info(
-			"Handling message from " + fmt.Sprint(
-				m.SenderID(),
-			) + " in chat " + fmt.Sprint(
-				m.ChannelID(),
-			),
+		logger.InfoF(
+			"Handling message from %d chat_id=%d",
+			m.SenderID(),
+			m.ChannelID(),
 		)
 
+		if blocked := checkBlacklisted(m); blocked {
+			return tg.ErrEndGroup
+		}
+
 		if is, _ := database.IsMaintenance(); is {
-			gologging.Debug("Maintenance mode active")
+			logger.Debug("Maintenance mode active")
 			if m.SenderID() != config.OwnerID {
 				if ok, _ := database.IsSudo(m.SenderID()); !ok {
 					if m.ChatType() == tg.EntityUser ||
@@ -246,7 +274,7 @@ func SafeMessageHandler(
 							locales.Arg{"reason": reason},
 						)
 						m.Reply(msg)
-						gologging.Info(
+						logger.Info(
 							"Sent maintenance notice to " + fmt.Sprint(
 								m.SenderID(),
 							),
@@ -257,9 +285,11 @@ func SafeMessageHandler(
 			}
 		}
 
+		defer autoDeleteCommand(m)
+
 		defer func() {
 			if r := recover(); r != nil {
-				gologging.Error("Recovered from panic: " + fmt.Sprint(r))
+				logger.Error("Recovered from panic: " + fmt.Sprint(r))
 				handlePanic(r, m, true)
 				err = fmt.Errorf("internal panic occurred")
 			}
@@ -267,23 +297,23 @@ func SafeMessageHandler(
 
 		if checkForHelpFlag(m) {
 			cmd := getCommand(m)
-			gologging.Debug("Help flag detected for command " + cmd)
+			logger.Debug("Help flag detected for command " + cmd)
 			err = showHelpFor(m, cmd)
 		} else {
 			cmd := getCommand(m)
-			gologging.Debug("Executing handler for command " + cmd)
+			logger.Debug("Executing handler for command " + cmd)
 			err = handler(m)
 		}
 
 		if err != nil {
 			if errors.Is(err, tg.ErrEndGroup) {
-				gologging.Debug("Handler exited early (ErrEndGroup)")
+				logger.Debug("Handler exited early (ErrEndGroup)")
 				return err
 			}
-			gologging.Error("Handler error: " + err.Error())
+			logger.Error("Handler error: " + err.Error())
 			handlePanic(err, m, false)
 		} else {
-			gologging.Info("Handler completed successfully for command " + getCommand(m))
+			logger.Info("Handler completed successfully for command " + getCommand(m))
 		}
 
 		return err
@@ -323,7 +353,7 @@ func handlePanic(r, ctx interface{}, isPanic bool) {
 	}
 
 	if isPanic {
-		gologging.ErrorF(
+		logger.ErrorF(
 			logMsg,
 			handlerType,
 			userMention,
@@ -333,7 +363,7 @@ func handlePanic(r, ctx interface{}, isPanic bool) {
 			stack,
 		)
 	} else {
-		gologging.ErrorF(logMsg, handlerType, userMention, chatInfo, messageInfo, r)
+		logger.ErrorF(logMsg, handlerType, userMention, chatInfo, messageInfo, r)
 	}
 
 	if config.LoggerID != 0 && client != nil {
@@ -352,9 +382,9 @@ func handlePanic(r, ctx interface{}, isPanic bool) {
 			short = fmt.Sprintf(shortMsg, handlerType, userMention, chatInfo, messageInfo, errorMessage)
 		}
 
-		gologging.Error(short)
+		logger.Error(short)
 		if _, sendErr := client.SendMessage(config.LoggerID, short, &tg.SendOptions{ParseMode: "HTML"}); sendErr != nil {
-			gologging.ErrorF(
+			logger.ErrorF(
 				"Failed to send panic message to log chat: %v",
 				sendErr,
 			)
@@ -373,8 +403,8 @@ func warnAndLeave(client *tg.Client, chatID int64) {
 		},
 	)
 	if err != nil {
-		gologging.ErrorF(
-			"Failed to send supergroup conversion message to chat %d: %v",
+		logger.ErrorF(
+			"Failed to send supergroup conversion message chat_id=%d: %v",
 			chatID,
 			err,
 		)
@@ -384,8 +414,8 @@ func warnAndLeave(client *tg.Client, chatID int64) {
 	go func() {
 		time.Sleep(1 * time.Second)
 		if err := client.LeaveChannel(chatID); err != nil {
-			gologging.ErrorF(
-				"Failed to leave non-supergroup chatID=%d: %v",
+			logger.ErrorF(
+				"Failed to leave non-supergroup chat_id=%d: %v",
 				chatID,
 				err,
 			)
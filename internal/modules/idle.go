@@ -0,0 +1,228 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"sync"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/ubot"
+)
+
+// idleGrace is how long a voice chat may be empty before the room pauses,
+// so a /play sent before joining the voice chat does not pause right away.
+const idleGrace = 15 * time.Second
+
+// idleRoom is a room whose voice chat has no listeners.
+type idleRoom struct {
+	notifyID   int64
+	autoPaused bool
+	timer      *time.Timer
+	notice     *tg.NewMessage
+}
+
+var (
+	idleRooms   = make(map[int64]*idleRoom)
+	idleRoomsMu sync.Mutex
+)
+
+func onParticipantsChange(_ *ubot.Context, chatID int64) {
+	checkListeners(chatID)
+}
+
+// checkListeners starts the idle countdown of a room whose voice chat
+// emptied, or resumes it when a listener came back.
+func checkListeners(chatID int64) {
+	// Private calls end with the call itself
+	if chatID > 0 {
+		return
+	}
+
+	r, ok := core.GetRoom(chatID, nil)
+	if !ok || !r.IsActiveChat() {
+		clearIdle(chatID)
+		return
+	}
+
+	notifyID := chatID
+	if r.IsCPlay() {
+		if cid, err := database.GetChatIDFromCPlayID(chatID); err == nil {
+			notifyID = cid
+		}
+	}
+	if prefs, _ := database.GetChatPrefs(notifyID); prefs.KeepPlayingAlone {
+		clearIdle(chatID)
+		return
+	}
+
+	ass, err := core.Assistants.ForChat(chatID)
+	if err != nil {
+		return
+	}
+	listeners, err := ass.Listeners(chatID)
+	if err != nil {
+		logger.DebugF("Failed to count listeners chat_id=%d: %v", chatID, err)
+		return
+	}
+
+	if listeners > 0 {
+		resumeIdle(chatID, r)
+		return
+	}
+
+	idleRoomsMu.Lock()
+	defer idleRoomsMu.Unlock()
+	if _, idle := idleRooms[chatID]; idle {
+		return
+	}
+	idleRooms[chatID] = &idleRoom{
+		notifyID: notifyID,
+		timer:    time.AfterFunc(idleGrace, func() { pauseIdle(chatID) }),
+	}
+}
+
+// pauseIdle pauses a room still without listeners after idleGrace and
+// schedules it to leave.
+func pauseIdle(chatID int64) {
+	r, ok := core.GetRoom(chatID, nil)
+	if !ok || !r.IsActiveChat() {
+		clearIdle(chatID)
+		return
+	}
+
+	idleRoomsMu.Lock()
+	st, ok := idleRooms[chatID]
+	idleRoomsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	autoPaused := false
+	if !r.IsPaused() {
+		if _, err := r.Pause(); err != nil {
+			logger.ErrorF("Failed to auto-pause chat_id=%d: %v", chatID, err)
+		} else {
+			autoPaused = true
+		}
+	}
+	notice, _ := core.Bot.SendMessage(st.notifyID, F(st.notifyID, "idle_paused", locales.Arg{
+		"timeout": formatDuration(config.IdleLeaveTimeout),
+	}))
+
+	timeout := time.Duration(config.IdleLeaveTimeout) * time.Second
+	idleRoomsMu.Lock()
+	current := idleRooms[chatID] == st
+	if current {
+		st.autoPaused = autoPaused
+		st.notice = notice
+		st.timer = time.AfterFunc(timeout, func() { leaveIdle(chatID) })
+	}
+	idleRoomsMu.Unlock()
+
+	// A listener came back while pausing, after resumeIdle ran
+	if !current {
+		if autoPaused {
+			r.Resume()
+		}
+		deleteNotice(&idleRoom{notice: notice})
+	}
+}
+
+// leaveIdle stops a room nobody came back to and leaves the voice chat.
+func leaveIdle(chatID int64) {
+	idleRoomsMu.Lock()
+	st, ok := idleRooms[chatID]
+	delete(idleRooms, chatID)
+	idleRoomsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	// The room may have ended on its own meanwhile
+	if r, ok := core.GetRoom(chatID, nil); !ok || !r.IsActiveChat() {
+		deleteNotice(st)
+		return
+	}
+
+	// Someone may have joined without an update reaching us
+	if ass, err := core.Assistants.ForChat(chatID); err == nil {
+		if n, err := ass.Listeners(chatID); err == nil && n > 0 {
+			if r, ok := core.GetRoom(chatID, nil); ok && st.autoPaused {
+				r.Resume()
+			}
+			deleteNotice(st)
+			return
+		}
+	}
+
+	logger.InfoF("Leaving voice chat without listeners chat_id=%d", chatID)
+	core.DeleteRoom(chatID)
+
+	text := F(st.notifyID, "idle_left")
+	if st.notice != nil {
+		if _, err := st.notice.Edit(text); err == nil {
+			return
+		}
+	}
+	core.Bot.SendMessage(st.notifyID, text)
+}
+
+// resumeIdle undoes the idle countdown once a listener is back.
+func resumeIdle(chatID int64, r *core.RoomState) {
+	idleRoomsMu.Lock()
+	st, ok := idleRooms[chatID]
+	delete(idleRooms, chatID)
+	idleRoomsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	st.timer.Stop()
+	if st.autoPaused && r.IsPaused() {
+		if _, err := r.Resume(); err != nil {
+			logger.ErrorF("Failed to auto-resume chat_id=%d: %v", chatID, err)
+		}
+	}
+	deleteNotice(st)
+}
+
+func clearIdle(chatID int64) {
+	idleRoomsMu.Lock()
+	st, ok := idleRooms[chatID]
+	delete(idleRooms, chatID)
+	idleRoomsMu.Unlock()
+	if ok {
+		st.timer.Stop()
+		deleteNotice(st)
+	}
+}
+
+func deleteNotice(st *idleRoom) {
+	if st.notice != nil {
+		st.notice.Delete()
+	}
+}
@@ -0,0 +1,258 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"bytes"
+	"html"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+const (
+	maxLocalePackKB = 512
+	// reportKeysLimit caps how many key names a pack report lists.
+	reportKeysLimit = 15
+)
+
+// loadLocalePacks installs the packs stored in the database, drops packs
+// that are no longer stored and logs how complete every language is.
+func loadLocalePacks() {
+	packs, err := database.GetLocalePacks()
+	if err != nil {
+		logger.WarnF("Failed to load locale packs: %v", err)
+	} else {
+		stored := make(map[string]bool, len(packs))
+		for _, p := range packs {
+			if err := locales.LoadPack(p.Lang, []byte(p.Data)); err != nil {
+				logger.WarnF("Skipping locale pack %s: %v", p.Lang, err)
+				continue
+			}
+			stored[p.Lang] = true
+		}
+		for _, lang := range locales.Packs() {
+			if !stored[lang] {
+				locales.RemovePack(lang)
+			}
+		}
+		if len(stored) > 0 {
+			logger.InfoF("Loaded %d locale packs.", len(stored))
+		}
+	}
+
+	for _, r := range locales.Validate() {
+		if r.OK() {
+			continue
+		}
+		logger.WarnF(
+			"Locale %s: %d missing, %d unused, %d with unknown placeholders",
+			r.Lang, len(r.Missing), len(r.Unused), len(r.Placeholders),
+		)
+		if len(r.Unused) > 0 {
+			logger.DebugF("Locale %s unused keys: %s", r.Lang, strings.Join(r.Unused, ", "))
+		}
+		if len(r.Placeholders) > 0 {
+			logger.DebugF(
+				"Locale %s unknown placeholders in: %s",
+				r.Lang,
+				strings.Join(r.Placeholders, ", "),
+			)
+		}
+	}
+}
+
+func langPackHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+	args := strings.Fields(m.Args())
+
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "list":
+			return langPackList(m)
+		case "remove", "rm", "del", "delete":
+			if len(args) < 2 {
+				break
+			}
+			return langPackRemove(m, args[1])
+		}
+	}
+
+	if !m.IsReply() {
+		m.Reply(F(chatID, "langpack_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+	reply, err := m.GetReplyMessage()
+	if err != nil || reply.Document() == nil {
+		m.Reply(F(chatID, "langpack_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+	if reply.Document().Size > maxLocalePackKB<<10 {
+		m.Reply(F(chatID, "langpack_too_large", locales.Arg{
+			"max": maxLocalePackKB,
+		}))
+		return tg.ErrEndGroup
+	}
+
+	// The language comes from the argument or the file name (pt-BR.yml).
+	lang := ""
+	if len(args) > 0 {
+		lang = args[0]
+	} else {
+		name := reply.File.Name
+		lang = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if !locales.ValidLangCode(lang) {
+		m.Reply(F(chatID, "langpack_invalid_lang", locales.Arg{
+			"lang": html.EscapeString(lang),
+			"cmd":  getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	mystic, _ := m.Reply(F(chatID, "langpack_checking"))
+
+	var buf bytes.Buffer
+	if _, err := reply.Download(&tg.DownloadOptions{Buffer: &buf}); err != nil {
+		utils.EOR(mystic, F(chatID, "langpack_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	keys, err := locales.ParsePack(buf.Bytes())
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "langpack_invalid", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	err = database.SaveLocalePack(&database.LocalePack{
+		Lang:      lang,
+		Data:      buf.String(),
+		UpdatedBy: m.SenderID(),
+		UpdatedAt: time.Now(),
+	})
+	if err == nil {
+		err = locales.LoadPack(lang, buf.Bytes())
+	}
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "langpack_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	logger.InfoF("Locale pack %s (%d keys) uploaded by %d", lang, keys, m.SenderID())
+
+	r := locales.ValidateLang(lang)
+	var b strings.Builder
+	b.WriteString(F(chatID, "langpack_loaded", locales.Arg{
+		"lang":         html.EscapeString(lang),
+		"count":        keys,
+		"missing":      len(r.Missing),
+		"unused":       len(r.Unused),
+		"placeholders": len(r.Placeholders),
+	}))
+	writeKeyList(&b, chatID, "langpack_unused_keys", r.Unused)
+	writeKeyList(&b, chatID, "langpack_placeholder_keys", r.Placeholders)
+	utils.EOR(mystic, b.String())
+	return tg.ErrEndGroup
+}
+
+func langPackList(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	packs := locales.Packs()
+	if len(packs) == 0 {
+		m.Reply(F(chatID, "langpack_list_empty"))
+		return tg.ErrEndGroup
+	}
+
+	var b strings.Builder
+	b.WriteString(F(chatID, "langpack_list_header"))
+	for _, lang := range packs {
+		r := locales.ValidateLang(lang)
+		b.WriteString("\n")
+		b.WriteString(F(chatID, "langpack_list_item", locales.Arg{
+			"lang":    lang,
+			"name":    html.EscapeString(locales.Get(lang, "name", nil)),
+			"missing": len(r.Missing),
+			"unused":  len(r.Unused),
+		}))
+	}
+	m.Reply(b.String())
+	return tg.ErrEndGroup
+}
+
+func langPackRemove(m *tg.NewMessage, lang string) error {
+	chatID := m.ChannelID()
+
+	deleted, err := database.DeleteLocalePack(lang)
+	if err != nil {
+		m.Reply(F(chatID, "langpack_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+	removed := locales.RemovePack(lang)
+	if !deleted && !removed {
+		m.Reply(F(chatID, "langpack_not_found", locales.Arg{
+			"lang": html.EscapeString(lang),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	logger.InfoF("Locale pack %s removed by %d", lang, m.SenderID())
+	m.Reply(F(chatID, "langpack_removed", locales.Arg{
+		"lang": html.EscapeString(lang),
+	}))
+	return tg.ErrEndGroup
+}
+
+func writeKeyList(b *strings.Builder, chatID int64, header string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	b.WriteString("\n\n")
+	b.WriteString(F(chatID, header))
+	for i, key := range keys {
+		if i == reportKeysLimit {
+			b.WriteString("\n")
+			b.WriteString(F(chatID, "langpack_keys_more", locales.Arg{
+				"count": len(keys) - reportKeysLimit,
+			}))
+			break
+		}
+		b.WriteString("\n— <code>" + html.EscapeString(key) + "</code>")
+	}
+}
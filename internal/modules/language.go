@@ -23,7 +23,6 @@ package modules
 import (
 	"strings"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -88,7 +87,7 @@ func langCallbackHandler(cb *telegram.CallbackQuery) error {
 	langName := locales.Get(lang, "name", nil)
 
 	if err := database.SetChatLanguage(chatID, lang); err != nil {
-		gologging.ErrorF("SetChatLanguage error: %v", err)
+		logger.ErrorF("SetChatLanguage error: %v", err)
 		cb.Answer(F(chatID, "lang_fail"), opt)
 		return telegram.ErrEndGroup
 	}
@@ -0,0 +1,82 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"html"
+	"strings"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/locales"
+)
+
+func logLevelHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+	args := strings.Fields(m.Args())
+
+	if len(args) == 0 {
+		var b strings.Builder
+		b.WriteString(F(chatID, "loglevel_header"))
+		for _, name := range config.LoggerNames {
+			b.WriteString("\n")
+			b.WriteString(F(chatID, "loglevel_item", locales.Arg{
+				"logger": name,
+				"level":  config.LogLevelOf(name),
+			}))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(F(chatID, "loglevel_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		m.Reply(b.String())
+		return tg.ErrEndGroup
+	}
+
+	if len(args) != 2 {
+		m.Reply(F(chatID, "loglevel_usage", locales.Arg{
+			"cmd": getCommand(m),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	names := []string{args[0]}
+	if strings.EqualFold(args[0], "all") {
+		names = config.LoggerNames
+	}
+
+	for _, name := range names {
+		if err := config.SetLogLevel(name, args[1]); err != nil {
+			m.Reply(F(chatID, "loglevel_fail", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+			return tg.ErrEndGroup
+		}
+	}
+
+	logger.InfoF("Log level of %s set to %s by %d", args[0], args[1], m.SenderID())
+	m.Reply(F(chatID, "loglevel_updated", locales.Arg{
+		"logger": html.EscapeString(args[0]),
+		"level":  strings.ToLower(args[1]),
+	}))
+	return tg.ErrEndGroup
+}
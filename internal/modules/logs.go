@@ -31,7 +31,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -45,31 +44,6 @@ const (
 
 var logClearMutex sync.Mutex
 
-func init() {
-	helpTexts["/logs"] = `<i>Download or view bot logs.</i>
-
-<u>Usage:</u>
-<b>/logs</b> — Send current log file or show content
-<b>/logs [n]</b> — Get last N lines from recent logs
-<b>/logs -old [n]</b> — Get first N lines from oldest logs
-<b>/logs -clear</b> — Clear current log file
-
-<b>📋 Examples:</b>
-• <code>/logs</code> — Get full current log
-• <code>/logs 50</code> — Last 50 lines from recent
-• <code>/logs 100</code> — Last 100 lines from recent
-• <code>/logs -old 50</code> — First 50 lines from oldest
-• <code>/logs -clear</code> — Delete current log
-
-<b>🔒 Restrictions:</b>
-• <b>Sudo users only</b>
-
-<b>⚠️ Notes:</b>
-• If content < 2000 chars, shows as code preview
-• Otherwise sends as file
-• N can be any positive number (default: 50)`
-}
-
 func logsHandler(m *tg.NewMessage) error {
 	chatID := m.ChannelID()
 	text := strings.TrimSpace(m.Text())
@@ -331,11 +305,11 @@ func handleLogsClear(m *tg.NewMessage, logFile string) error {
 		m.Reply(F(chatID, "logs_clear_failed", locales.Arg{
 			"error": err.Error(),
 		}))
-		gologging.ErrorF("Failed to clear log file: %v", err)
+		logger.ErrorF("Failed to clear log file: %v", err)
 		return tg.ErrEndGroup
 	}
 
-	gologging.InfoF(
+	logger.InfoF(
 		"Log file cleared by user %d (was %.2f MB)",
 		m.SenderID(),
 		fileSizeMB,
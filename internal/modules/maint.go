@@ -17,7 +17,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -26,33 +25,6 @@ import (
 	"main/internal/utils"
 )
 
-func init() {
-	helpTexts["/maintenance"] = `<i>Toggle maintenance mode.</i>
-
-<u>Usage:</u>
-<b>/maintenance</b> — Show current status
-<b>/maintenance on [reason]</b> — Enable maintenance
-<b>/maintenance off</b> — Disable maintenance
-
-<b>⚙️ Behavior When Active:</b>
-• Stops all active rooms
-• Blocks non-owner/sudo commands
-• Shows maintenance message to users
-
-<b>🔒 Restrictions:</b>
-• <b>Owner only</b> command
-
-<b>💡 Examples:</b>
-<code>/maintenance on Server upgrade</code>
-<code>/maintenance off</code>
-
-<b>⚠️ Notes:</b>
-• Owner and sudoers can still use bot
-• All rooms are destroyed when enabled
-• Users see maintenance message with reason`
-	helpTexts["/maint"] = helpTexts["/maintenance"]
-}
-
 var maintCancel = struct {
 	sync.Mutex
 	cancel bool
@@ -128,7 +100,7 @@ func handleMaintenance(m *tg.NewMessage) error {
 
 	// apply new state
 	database.SetMaintenance(enable, reason)
-	gologging.InfoF(
+	logger.InfoF(
 		"User %d set maintenance: %v (reason: %s)",
 		m.SenderID(),
 		enable,
@@ -150,8 +122,8 @@ func handleMaintenance(m *tg.NewMessage) error {
 				maintCancel.Unlock()
 				ass, err := core.Assistants.ForChat(id)
 				if err != nil {
-					gologging.ErrorF(
-						"Failed to get Assistant for %d: %v",
+					logger.ErrorF(
+						"Failed to get assistant chat_id=%d: %v",
 						id,
 						err,
 					)
@@ -23,13 +23,17 @@ package modules
 import (
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
 	"main/internal/database"
+	"main/internal/health"
 )
 
+// roomsHeartbeat beats once per pass over the rooms. A pass blocks while
+// the room workers are stuck, so it stops beating when they hang.
+var roomsHeartbeat = health.NewHeartbeat("rooms", 2*time.Minute)
+
 func MonitorRooms() {
 	ticker := time.NewTicker(4 * time.Second)
 	defer ticker.Stop()
@@ -43,8 +47,8 @@ func MonitorRooms() {
 				defer func() { <-sem }()
 				ass, err := core.Assistants.ForChat(id)
 				if err != nil {
-					gologging.ErrorF(
-						"Failed to get Assistant for %d: %v",
+					logger.ErrorF(
+						"Failed to get assistant chat_id=%d: %v",
 						id,
 						err,
 					)
@@ -53,7 +57,7 @@ func MonitorRooms() {
 
 				r, ok := core.GetRoom(id, ass)
 				if !ok {
-					gologging.DebugF(
+					logger.DebugF(
 						"Room not exists for %d returning..",
 						chatID,
 					)
@@ -70,7 +74,7 @@ func MonitorRooms() {
 				}
 
 				if r.IsPaused() {
-					gologging.DebugF("Room paused for %d returning..", chatID)
+					logger.DebugF("Room paused for %d returning..", chatID)
 
 					return
 				}
@@ -78,7 +82,7 @@ func MonitorRooms() {
 				r.Parse()
 				mystic := r.GetMystic()
 				if mystic == nil {
-					gologging.DebugF("mystic is nil for %d returning..", chatID)
+					logger.DebugF("mystic is nil for %d returning..", chatID)
 
 					return
 				}
@@ -97,5 +101,6 @@ func MonitorRooms() {
 				mystic.Edit(mystic.Text(), opts)
 			}(chatID)
 		}
+		roomsHeartbeat.Beat()
 	}
 }
@@ -29,7 +29,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
@@ -50,119 +49,6 @@ type playOpts struct {
 
 const playMaxRetries = 3
 
-func init() {
-	helpTexts["/play"] = `<i>Play a song in the voice chat from YouTube, Spotify, or other sources.</i>
-
-<u>Usage:</u>
-<b>/play [query/URL]</b> — Search and play a song
-<b>/play [reply to audio/video]</b> — Play replied media
-
-<b>🎵 Supported Sources:</b>
-• YouTube (videos, playlists)
-• Spotify (tracks, albums, playlists)
-• SoundCloud
-• Direct audio/video links
-
-<b>⚙️ Features:</b>
-• Queue support - adds to end if already playing
-• Auto-join voice chat if not present
-• Duration limit check
-• Multiple track support (playlists)
-
-<b>💡 Examples:</b>
-<code>/play never gonna give you up</code>
-<code>/play https://youtu.be/dQw4w9WgXcQ</code>
-<code>/play https://open.spotify.com/track/...</code>
-
-<b>⚠️ Notes:</b>
-• Bot must have proper permissions in voice chat
-• Tracks exceeding duration limit will be skipped
-• Use <code>/queue</code> to view upcoming tracks
-• Use <code>/fplay</code> to force play (skip queue)`
-
-	helpTexts["/fplay"] = `<i>Force play a song, skipping the current queue.</i>
-
-<u>Usage:</u>
-<b>/fplay [query/URL]</b> — Force play immediately
-<b>/fplay [reply to audio/video]</b> — Force play replied media
-
-<b>🎵 Behavior:</b>
-• Stops current playback
-• Clears queue
-• Starts playing immediately
-
-<b>🔒 Restrictions:</b>
-• Only <b>chat admins</b> or <b>authorized users</b> can use this
-
-<b>💡 Example:</b>
-<code>/fplay urgent announcement track</code>
-
-<b>⚠️ Note:</b>
-This command is useful for urgent playback needs but will disrupt the current queue.`
-
-	helpTexts["/vplay"] = `<i>Play video content in voice chat (video mode).</i>
-
-<u>Usage:</u>
-<b>/vplay [query/URL]</b> — Play video
-<b>/vplay [reply to video]</b> — Play replied video
-
-<b>📹 Features:</b>
-• Full video playback support
-• Audio + Video streaming
-• Same queue system as audio
-
-<b>⚠️ Notes:</b>
-• Requires video streaming permissions
-• Use <code>/fvplay</code> for force video play`
-
-	helpTexts["/fvplay"] = `<i>Force play video content, skipping queue.</i>
-
-<u>Usage:</u>
-<b>/fvplay [query/URL]</b> — Force play video immediately
-
-<b>🔒 Restrictions:</b>
-• Admin/auth only command
-
-<b>💡 Use Case:</b>
-Immediate video playback when something urgent needs to be shown.`
-
-	helpTexts["/cplay"] = `<i>Play in linked channel's voice chat.</i>
-
-<u>Usage:</u>
-<b>/cplay [query]</b> — Play in linked channel
-
-<b>⚙️ Setup Required:</b>
-First use <code>/channelplay --set [channel_id]</code>
-
-<b>⚠️ Note:</b>
-All c* commands work the same as regular commands but affect the linked channel.`
-
-	helpTexts["/channelplay"] = `<i>Configure linked channel for channel play mode.</i>
-
-<u>Usage:</u>
-<b>/channelplay --set [channel_id]</b> — Set linked channel
-
-<b>⚙️ Behavior:</b>
-• Links a channel to current group
-• All <code>c*</code> commands affect linked channel
-• Channel must be accessible by bot
-
-<b>🔒 Restrictions:</b>
-• Only <b>chat admins</b> can configure
-
-<b>💡 Examples:</b>
-<code>/channelplay --set -1001234567890</code>
-
-<b>⚠️ Notes:</b>
-• Get channel ID using forward + @userinfobot
-• Bot must be admin in linked channel
-• Use <code>/cplay</code> after setup`
-
-	helpTexts["/playforce"] = helpTexts["/fplay"]
-	helpTexts["/fcplay"] = helpTexts["/cfplay"]
-	helpTexts["/cvplay"] = helpTexts["/vcplay"]
-}
-
 func channelPlayHandler(m *telegram.NewMessage) error {
 	m.Reply(F(m.ChannelID(), "channel_play_depreciated"))
 	return telegram.ErrEndGroup
@@ -244,7 +130,7 @@ func cplayHandler(m *telegram.NewMessage) error {
 			},
 		)
 		if err != nil || fullChat == nil {
-			gologging.ErrorF(
+			logger.ErrorF(
 				"Failed to get full channel for cplay ID %d: %v",
 				cplayID, err,
 			)
@@ -256,8 +142,8 @@ func cplayHandler(m *telegram.NewMessage) error {
 		}
 
 		if err := database.SetCPlayID(m.ChannelID(), cplayID); err != nil {
-			gologging.ErrorF(
-				"Failed to set cplay ID for chat %d: %v",
+			logger.ErrorF(
+				"Failed to set cplay ID chat_id=%d: %v",
 				m.ChannelID(), err,
 			)
 			m.Reply(
@@ -281,7 +167,15 @@ func cplayHandler(m *telegram.NewMessage) error {
 func handlePlay(m *telegram.NewMessage, opts *playOpts) error {
 	mention := utils.MentionHTML(m.Sender)
 
-	r, replyMsg, err := prepareRoomAndSearchMessage(m, opts.CPlay)
+	prefs, err := database.GetChatPrefs(m.ChannelID())
+	if err != nil {
+		logger.ErrorF("Failed to get chat prefs chat_id=%d: %v", m.ChannelID(), err)
+	}
+	if !checkPlayAllowed(m, prefs, opts) {
+		return telegram.ErrEndGroup
+	}
+
+	r, replyMsg, err := prepareRoomAndSearchMessage(m, opts.CPlay, prefs)
 	if err != nil {
 		return telegram.ErrEndGroup
 	}
@@ -291,12 +185,13 @@ func handlePlay(m *telegram.NewMessage, opts *playOpts) error {
 		replyMsg,
 		r,
 		opts.Video,
+		prefs.SearchPlatform,
 	)
 	if err != nil {
 		return telegram.ErrEndGroup
 	}
 
-	tracks, availableSlots, err := filterAndTrimTracks(replyMsg, r, tracks)
+	tracks, availableSlots, err := filterAndTrimTracks(replyMsg, r, tracks, prefs)
 	if err != nil {
 		return telegram.ErrEndGroup
 	}
@@ -311,9 +206,37 @@ func handlePlay(m *telegram.NewMessage, opts *playOpts) error {
 	return telegram.ErrEndGroup
 }
 
+// checkPlayAllowed enforces the play mode and video options of /settings.
+func checkPlayAllowed(
+	m *telegram.NewMessage,
+	prefs database.ChatPrefs,
+	opts *playOpts,
+) bool {
+	chatID := m.ChannelID()
+
+	if prefs.PlayMode == database.PlayModeAdmins && !opts.Force {
+		// Force commands are already behind authFilter.
+		isAdmin, err := utils.IsChatAdmin(m.Client, chatID, m.SenderID())
+		if err != nil || !isAdmin {
+			if isAuth, _ := database.IsAuthUser(chatID, m.SenderID()); !isAuth {
+				m.Reply(F(chatID, "play_admins_only"))
+				return false
+			}
+		}
+	}
+
+	if opts.Video && prefs.VideoDisabled {
+		m.Reply(F(chatID, "play_video_disabled"))
+		return false
+	}
+
+	return true
+}
+
 func prepareRoomAndSearchMessage(
 	m *telegram.NewMessage,
 	cplay bool,
+	prefs database.ChatPrefs,
 ) (*core.RoomState, *telegram.NewMessage, error) {
 	r, err := getEffectiveRoom(m, cplay)
 	if err != nil {
@@ -325,9 +248,10 @@ func prepareRoomAndSearchMessage(
 	r.SetCPlay(cplay)
 	r.Parse()
 
-	if len(r.Queue()) >= config.QueueLimit {
+	queueLimit := prefs.MaxQueue(config.QueueLimit)
+	if len(r.Queue()) >= queueLimit {
 		m.Reply(F(chatID, "queue_limit_reached", locales.Arg{
-			"limit": config.QueueLimit,
+			"limit": queueLimit,
 		}))
 		return nil, nil, fmt.Errorf("queue limit reached")
 	}
@@ -357,7 +281,7 @@ func prepareRoomAndSearchMessage(
 
 	replyMsg, err := m.Reply(searchStr)
 	if err != nil {
-		gologging.ErrorF("Failed to send searching message: %v", err)
+		logger.ErrorF("Failed to send searching message: %v", err)
 		return nil, nil, err
 	}
 
@@ -369,8 +293,9 @@ func fetchTracksAndCheckStatus(
 	replyMsg *telegram.NewMessage,
 	r *core.RoomState,
 	video bool,
+	search string,
 ) ([]*state.Track, bool, error) {
-	tracks, err := safeGetTracks(m, replyMsg, m.ChannelID(), video)
+	tracks, err := safeGetTracks(m, replyMsg, m.ChannelID(), video, search)
 	if err != nil {
 		utils.EOR(replyMsg, err.Error())
 		return nil, false, err
@@ -384,14 +309,14 @@ func fetchTracksAndCheckStatus(
 	isActive := r.IsActiveChat()
 	cs, err := core.GetChatState(r.ChatID())
 	if err != nil {
-		gologging.ErrorF("Error getting chat state: %v", err)
+		logger.ErrorF("Error getting chat state: %v", err)
 		utils.EOR(replyMsg, getErrorMessage(m.ChannelID(), err))
 		return nil, false, err
 	}
 
 	activeVC, err := cs.IsActiveVC()
 	if err != nil {
-		gologging.ErrorF("Error checking voicechat state: %v", err)
+		logger.ErrorF("Error checking voicechat state: %v", err)
 		utils.EOR(replyMsg, getErrorMessage(m.ChannelID(), err))
 		return nil, false, err
 	}
@@ -403,31 +328,39 @@ func fetchTracksAndCheckStatus(
 
 	banned, err := cs.IsAssistantBanned()
 	if err != nil {
-		gologging.ErrorF("Error checking assistant banned state: %v", err)
+		logger.ErrorF("Error checking assistant banned state: %v", err)
 		utils.EOR(replyMsg, getErrorMessage(m.ChannelID(), err))
 		return nil, false, err
 	}
 
 	if banned {
-		utils.EOR(replyMsg,
-			F(m.ChannelID(), "err_assistant_banned", locales.Arg{
-				"user": utils.MentionHTML(cs.Assistant.User),
-				"id":   utils.IntToStr(cs.Assistant.User.ID),
-			}),
-		)
-		return nil, false, fmt.Errorf("assistant banned")
+		// Another assistant may still be allowed in; the chat state follows
+		// the move, so the presence check below is for the new one.
+		ass, err := core.Assistants.Reassign(r.ChatID(), "banned")
+		if err != nil {
+			utils.EOR(replyMsg,
+				F(m.ChannelID(), "err_assistant_banned", locales.Arg{
+					"user": utils.MentionHTML(cs.Assistant.User),
+					"id":   utils.IntToStr(cs.Assistant.User.ID),
+				}),
+			)
+			return nil, false, fmt.Errorf("assistant banned")
+		}
+		utils.EOR(replyMsg, F(m.ChannelID(), "assistant_switched", locales.Arg{
+			"user": utils.MentionHTML(ass.User),
+		}))
 	}
 
 	present, err := cs.IsAssistantPresent()
 	if err != nil {
-		gologging.ErrorF("Error checking assistant presence: %v", err)
+		logger.ErrorF("Error checking assistant presence: %v", err)
 		utils.EOR(replyMsg, getErrorMessage(m.ChannelID(), err))
 		return nil, false, err
 	}
 
 	if !present {
 		if err := cs.TryJoin(); err != nil {
-			gologging.ErrorF("Error joining assistant: %v", err)
+			logger.ErrorF("Error joining assistant: %v", err)
 			utils.EOR(replyMsg, getErrorMessage(m.ChannelID(), err))
 			return nil, false, err
 		}
@@ -440,14 +373,40 @@ func filterAndTrimTracks(
 	replyMsg *telegram.NewMessage,
 	r *core.RoomState,
 	tracks []*state.Track,
+	prefs database.ChatPrefs,
 ) ([]*state.Track, int, error) {
 	chatID := replyMsg.ChannelID()
+	durationLimit := prefs.MaxDuration(config.DurationLimit)
+
+	allowed, blocked := database.FilterTracks(chatID, prefs, tracks)
+
+	// CASE: the only track was blocked, say which rule did it
+	if len(tracks) == 1 && len(blocked) == 1 {
+		utils.EOR(replyMsg, F(chatID, "play_track_filtered", locales.Arg{
+			"title": html.EscapeString(utils.ShortTitle(blocked[0].Track.Title, 35)),
+			"rule":  F(chatID, "filter_rule_"+blocked[0].Rule),
+			"value": html.EscapeString(blocked[0].Value),
+		}))
+		return nil, 0, fmt.Errorf("single track blocked by filter")
+	}
 
 	var filteredTracks []*state.Track
 	var skippedTracks []string
+	var blockedTracks []string
+
+	for _, b := range blocked {
+		blockedTracks = append(blockedTracks, F(
+			chatID,
+			"play_filtered_item",
+			locales.Arg{
+				"title": html.EscapeString(utils.ShortTitle(b.Track.Title, 35)),
+				"rule":  F(chatID, "filter_rule_"+b.Rule),
+			},
+		))
+	}
 
-	for _, track := range tracks {
-		if track.Duration > config.DurationLimit {
+	for _, track := range allowed {
+		if track.Duration > durationLimit {
 			skippedTracks = append(
 				skippedTracks,
 				html.EscapeString(utils.ShortTitle(track.Title, 35)),
@@ -457,6 +416,25 @@ func filterAndTrimTracks(
 		filteredTracks = append(filteredTracks, track)
 	}
 
+	// Some tracks were blocked by the chat's /filter rules
+	if len(blockedTracks) > 0 {
+		var b strings.Builder
+		b.WriteString(F(chatID, "play_filtered_header", locales.Arg{
+			"count": len(blockedTracks),
+		}))
+		for i, item := range blockedTracks {
+			if i == 5 {
+				b.WriteString("\n" + F(chatID, "play_multiple_tracks_too_long_more", locales.Arg{
+					"remaining": len(blockedTracks) - i,
+				}))
+				break
+			}
+			b.WriteString("\n" + item)
+		}
+		utils.EOR(replyMsg, b.String())
+		time.Sleep(1 * time.Second)
+	}
+
 	// Some tracks were skipped due to duration limit
 	if len(skippedTracks) > 0 {
 
@@ -465,7 +443,7 @@ func filterAndTrimTracks(
 			utils.EOR(
 				replyMsg,
 				F(chatID, "play_single_track_too_long", locales.Arg{
-					"limit_mins": formatDuration(config.DurationLimit),
+					"limit_mins": formatDuration(durationLimit),
 					"title":      skippedTracks[0],
 				}),
 			)
@@ -478,7 +456,7 @@ func filterAndTrimTracks(
 		b.WriteString(
 			F(chatID, "play_multiple_tracks_too_long_header", locales.Arg{
 				"count":      len(skippedTracks),
-				"limit_mins": config.DurationLimit / 60,
+				"limit_mins": durationLimit / 60,
 			}),
 		)
 		b.WriteString("\n")
@@ -507,15 +485,20 @@ func filterAndTrimTracks(
 
 	// CASE: everything was skipped
 	if len(tracks) == 0 {
-		utils.EOR(replyMsg, F(chatID, "play_all_tracks_skipped"))
+		key := utils.IfElse(
+			len(skippedTracks) > 0,
+			"play_all_tracks_skipped",
+			"play_all_tracks_filtered",
+		)
+		utils.EOR(replyMsg, F(chatID, key))
 		return nil, 0, fmt.Errorf("all tracks skipped")
 	}
 
 	// Respect queue limit
-	availableSlots := config.QueueLimit - len(r.Queue())
+	availableSlots := prefs.MaxQueue(config.QueueLimit) - len(r.Queue())
 	if availableSlots < len(tracks) {
 		tracks = tracks[:availableSlots]
-		gologging.WarnF(
+		logger.WarnF(
 			"Queue full — adding only %d tracks out of requested.",
 			availableSlots,
 		)
@@ -537,6 +520,7 @@ func playTracksAndRespond(
 
 	for i, track := range tracks {
 		track.Requester = mention
+		track.RequesterID = m.SenderID()
 		title := html.EscapeString(utils.ShortTitle(track.Title, 25))
 		var filePath string
 
@@ -582,7 +566,7 @@ func playTracksAndRespond(
 			}
 
 			filePath = path
-			gologging.InfoF("Downloaded track to %s", filePath)
+			logger.InfoF("Downloaded track to %s", filePath)
 		}
 
 		// 🔁 play with retry
@@ -695,7 +679,7 @@ func playTrackWithRetry(
 		err := r.Play(track, filePath, force)
 		if err == nil {
 			if attempt > 1 {
-				gologging.Info(
+				logger.Info(
 					"Successfully played after retry attempt " + utils.IntToStr(
 						attempt,
 					),
@@ -706,7 +690,8 @@ func playTrackWithRetry(
 
 		// FloodWait
 		if wait := telegram.GetFloodWait(err); wait > 0 {
-			gologging.Error(
+			floodWaits.Inc("play")
+			logger.Error(
 				"FloodWait detected (" + strconv.Itoa(
 					wait,
 				) + "s). Retrying... (attempt " + utils.IntToStr(
@@ -729,8 +714,14 @@ func playTrackWithRetry(
 			return telegram.ErrEndGroup
 		}
 
+		if errors.Is(err, core.ErrCallDeclined) {
+			r.Destroy()
+			utils.EOR(replyMsg, F(replyMsg.ChannelID(), "callme_declined"))
+			return telegram.ErrEndGroup
+		}
+
 		if tg.MatchError(err, "GROUPCALL_INVALID") {
-			gologging.Error("GROUPCALL_INVALID err occurred. Returning...")
+			logger.Error("GROUPCALL_INVALID err occurred. Returning...")
 			r.Destroy()
 			utils.EOR(replyMsg, F(replyMsg.ChannelID(), "play_unable"))
 			return telegram.ErrEndGroup
@@ -738,7 +729,7 @@ func playTrackWithRetry(
 
 		// INTERDC_X_CALL_ERROR → retry
 		if tg.MatchError(err, "INTERDC_X_CALL_ERROR") {
-			gologging.Error(
+			logger.Error(
 				"INTERDC_X_CALL_ERROR occurred. Retrying... (attempt " + utils.IntToStr(
 					attempt,
 				) + ")",
@@ -749,7 +740,7 @@ func playTrackWithRetry(
 
 		// Last attempt failed
 		if attempt == playMaxRetries {
-			gologging.Error(
+			logger.Error(
 				"❌ Failed to play after " + utils.IntToStr(
 					playMaxRetries,
 				) + " attempts. Error: " + err.Error(),
@@ -765,7 +756,7 @@ func playTrackWithRetry(
 			return err
 		}
 
-		gologging.Error(
+		logger.Error(
 			"Unexpected error occurred. Retrying... (attempt " + utils.IntToStr(
 				attempt,
 			) + "): " + err.Error(),
@@ -782,7 +773,7 @@ var errMessageMap = map[error]msgFn{
 		return F(chatID, "err_admin_permission_required")
 	},
 	core.ErrAssistantGetFailed: func(chatID int64, e error) string {
-		gologging.Error(e)
+		logger.Error(e)
 		return F(chatID, "err_assistant_get_failed", locales.Arg{
 			"error": e.Error(),
 		})
@@ -842,6 +833,7 @@ func safeGetTracks(
 	m, replyMsg *telegram.NewMessage,
 	chatID int64,
 	video bool,
+	search string,
 ) (tracks []*state.Track, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -850,7 +842,7 @@ func safeGetTracks(
 		}
 	}()
 
-	tracks, err = platforms.GetTracks(m, video)
+	tracks, err = platforms.GetTracks(m, video, search)
 	return tracks, err
 }
 
@@ -0,0 +1,267 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+const (
+	pruneConfirmTTL = 30 * time.Minute
+	pruneProbeDelay = 100 * time.Millisecond
+)
+
+// pruneCheck is the outcome of probing every served chat and user, kept
+// until the owner confirms the removal.
+type pruneCheck struct {
+	dead    map[core.SendFailure][]int64
+	kept    int
+	checked int
+	at      time.Time
+}
+
+func (p *pruneCheck) deadIDs() []int64 {
+	var ids []int64
+	for _, list := range p.dead {
+		ids = append(ids, list...)
+	}
+	return ids
+}
+
+var (
+	pruneMu      sync.Mutex
+	pruneRunning bool
+	pendingPrune *pruneCheck
+)
+
+func pruneHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	pruneMu.Lock()
+	if pruneRunning {
+		pruneMu.Unlock()
+		m.Reply(F(chatID, "prune_running"))
+		return tg.ErrEndGroup
+	}
+	pruneRunning = true
+	pruneMu.Unlock()
+
+	var targets []int64
+	chats, err := database.GetServed()
+	if err == nil {
+		targets = append(targets, chats...)
+		var users []int64
+		users, err = database.GetServed(true)
+		targets = append(targets, users...)
+	}
+	if err != nil {
+		pruneMu.Lock()
+		pruneRunning = false
+		pruneMu.Unlock()
+		m.Reply(F(chatID, "prune_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	mystic, err := m.Reply(F(chatID, "prune_checking", locales.Arg{
+		"done":  0,
+		"total": len(targets),
+	}))
+	if err != nil {
+		pruneMu.Lock()
+		pruneRunning = false
+		pruneMu.Unlock()
+		logger.ErrorF("Failed to send prune status: %v", err)
+		return tg.ErrEndGroup
+	}
+	go runPruneCheck(mystic, chatID, targets)
+	return tg.ErrEndGroup
+}
+
+// runPruneCheck probes every target and shows what /prune would remove.
+// Nothing is removed before the owner confirms.
+func runPruneCheck(mystic *tg.NewMessage, chatID int64, targets []int64) {
+	defer func() {
+		pruneMu.Lock()
+		pruneRunning = false
+		pruneMu.Unlock()
+	}()
+
+	check := &pruneCheck{dead: make(map[core.SendFailure][]int64)}
+	lastEdit := time.Now()
+
+	for i, id := range targets {
+		err := probeServed(id)
+		if err != nil {
+			if kind := core.ClassifySendError(err); kind.Permanent() {
+				check.dead[kind] = append(check.dead[kind], id)
+			} else {
+				check.kept++
+			}
+		}
+		check.checked++
+
+		if time.Since(lastEdit) > 5*time.Second {
+			lastEdit = time.Now()
+			utils.EOR(mystic, F(chatID, "prune_checking", locales.Arg{
+				"done":  i + 1,
+				"total": len(targets),
+			}))
+		}
+		time.Sleep(pruneProbeDelay)
+	}
+	check.at = time.Now()
+
+	dead := check.deadIDs()
+	text := formatPruneReport(check, chatID)
+	if len(dead) == 0 {
+		utils.EOR(mystic, text+"\n\n"+F(chatID, "prune_nothing"))
+		return
+	}
+
+	pruneMu.Lock()
+	pendingPrune = check
+	pruneMu.Unlock()
+
+	kb := tg.NewKeyboard().
+		AddRow(
+			tg.Button.Data(F(chatID, "PRUNE_CONFIRM_BTN", locales.Arg{
+				"count": len(dead),
+			}), "prune:confirm"),
+		).
+		AddRow(
+			tg.Button.Data(F(chatID, "CLOSE_BTN"), "prune:cancel"),
+		).
+		Build()
+	mystic.Edit(text, &tg.SendOptions{ReplyMarkup: kb})
+}
+
+// probeServed sends an invisible cancel action, which fails the same way a
+// message would for a peer that blocked, kicked or lost the bot.
+func probeServed(id int64) error {
+	_, err := core.Bot.SendAction(id, "cancel")
+	if wait := tg.GetFloodWait(err); wait > 0 {
+		floodWaits.Inc("prune")
+		time.Sleep(time.Duration(wait) * time.Second)
+		_, err = core.Bot.SendAction(id, "cancel")
+	}
+	return err
+}
+
+func formatPruneReport(check *pruneCheck, chatID int64) string {
+	var sb strings.Builder
+	sb.WriteString(F(chatID, "prune_report_header", locales.Arg{
+		"checked": check.checked,
+	}) + "\n\n")
+
+	kinds := []core.SendFailure{core.SendBlocked, core.SendKicked, core.SendDeleted}
+	for _, kind := range kinds {
+		ids := check.dead[kind]
+		chats := 0
+		for _, id := range ids {
+			if id < 0 {
+				chats++
+			}
+		}
+		sb.WriteString(F(chatID, "prune_report_"+string(kind), locales.Arg{
+			"count": len(ids),
+			"chats": chats,
+			"users": len(ids) - chats,
+		}) + "\n")
+	}
+	if check.kept > 0 {
+		sb.WriteString("\n" + F(chatID, "prune_report_kept", locales.Arg{
+			"count": check.kept,
+		}))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func pruneCB(cb *tg.CallbackQuery) error {
+	chatID := cb.ChannelID()
+	opt := &tg.CallbackOptions{Alert: true}
+
+	if cb.SenderID != config.OwnerID {
+		cb.Answer(F(chatID, "prune_owner_only"), opt)
+		return tg.ErrEndGroup
+	}
+
+	action := strings.TrimPrefix(cb.DataString(), "prune:")
+
+	pruneMu.Lock()
+	check := pendingPrune
+	pendingPrune = nil
+	pruneMu.Unlock()
+
+	if action == "cancel" {
+		cb.Answer("")
+		cb.Edit(F(chatID, "prune_cancelled"))
+		return tg.ErrEndGroup
+	}
+
+	if check == nil || time.Since(check.at) > pruneConfirmTTL {
+		cb.Answer(F(chatID, "prune_expired"), opt)
+		cb.Edit(F(chatID, "prune_expired"))
+		return tg.ErrEndGroup
+	}
+
+	// Peers can come back while the report waits for confirmation, so
+	// only the ones that still fail permanently are removed.
+	dead := check.deadIDs()
+	cb.Answer("")
+	cb.Edit(F(chatID, "prune_rechecking", locales.Arg{"count": len(dead)}))
+
+	var still []int64
+	for _, id := range dead {
+		if err := probeServed(id); err != nil &&
+			core.ClassifySendError(err).Permanent() {
+			still = append(still, id)
+		}
+		time.Sleep(pruneProbeDelay)
+	}
+
+	removed, err := database.PruneServed(still)
+	if err != nil {
+		logger.ErrorF("Prune failed: %v", err)
+		cb.Edit(F(chatID, "prune_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	logger.InfoF("Pruned %d dead served chats and users", removed)
+	cb.Edit(F(chatID, "prune_done", locales.Arg{
+		"count": removed,
+	}))
+	return tg.ErrEndGroup
+}
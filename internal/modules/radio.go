@@ -0,0 +1,70 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"strings"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/locales"
+	"main/internal/radio"
+	"main/internal/utils"
+)
+
+func radioHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	if !config.RadioEnabled || config.HTTPPort <= 0 {
+		m.Reply(F(chatID, "radio_disabled"))
+		return tg.ErrEndGroup
+	}
+
+	args := strings.Fields(m.Text())
+	if len(args) > 1 && strings.EqualFold(args[1], "reset") {
+		isAdmin, err := utils.IsChatAdmin(m.Client, chatID, m.SenderID())
+		if err != nil || !isAdmin {
+			m.Reply(F(chatID, "only_admin"))
+			return tg.ErrEndGroup
+		}
+
+		if _, err := radio.ResetToken(chatID); err != nil {
+			m.Reply(F(chatID, "radio_token_fail"))
+			return tg.ErrEndGroup
+		}
+		m.Reply(F(chatID, "radio_reset_done"))
+		return tg.ErrEndGroup
+	}
+
+	token, err := radio.Token(chatID)
+	if err != nil {
+		m.Reply(F(chatID, "radio_token_fail"))
+		return tg.ErrEndGroup
+	}
+
+	m.Reply(F(chatID, "radio_link", locales.Arg{
+		"url":      radio.StreamURL(chatID, token, "mp3"),
+		"opus_url": radio.StreamURL(chatID, token, "opus"),
+		"cmd":      getCommand(m),
+	}))
+	return tg.ErrEndGroup
+}
@@ -0,0 +1,142 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+// rateGroup selects the buckets a handler draws from. Handlers without a
+// group use rateGeneral.
+type rateGroup string
+
+const (
+	rateGeneral rateGroup = ""
+	ratePlay    rateGroup = "play"
+	rateControl rateGroup = "control"
+	rateAdmin   rateGroup = "admin"
+)
+
+// rateLimits are the per-user, per-chat and global buckets of a group.
+type rateLimits struct {
+	user   utils.Limit
+	chat   utils.Limit
+	global utils.Limit
+}
+
+var rateGroupLimits = map[rateGroup]rateLimits{
+	rateGeneral: {
+		user:   utils.Limit{Burst: 6, Per: 15 * time.Second},
+		chat:   utils.Limit{Burst: 20, Per: 15 * time.Second},
+		global: utils.Limit{Burst: 300, Per: 10 * time.Second},
+	},
+	ratePlay: {
+		user:   utils.Limit{Burst: 3, Per: 30 * time.Second},
+		chat:   utils.Limit{Burst: 8, Per: 30 * time.Second},
+		global: utils.Limit{Burst: 60, Per: 10 * time.Second},
+	},
+	rateControl: {
+		user:   utils.Limit{Burst: 5, Per: 10 * time.Second},
+		chat:   utils.Limit{Burst: 12, Per: 10 * time.Second},
+		global: utils.Limit{Burst: 200, Per: 10 * time.Second},
+	},
+	rateAdmin: {
+		user: utils.Limit{Burst: 3, Per: 30 * time.Second},
+		chat: utils.Limit{Burst: 6, Per: 30 * time.Second},
+	},
+}
+
+// limiter holds the buckets of the handler middleware and the cooldowns
+// of individual commands.
+var limiter = utils.NewRateLimiter(5 * time.Minute)
+
+func rateLimitMessages(group rateGroup, handler tg.MessageHandler) tg.MessageHandler {
+	return func(m *tg.NewMessage) error {
+		ok, wait, warn, key := allowRate(group, m.SenderID(), m.ChannelID())
+		if ok {
+			return handler(m)
+		}
+		if warn {
+			m.Reply(rateLimitText(m.ChannelID(), key, wait))
+		}
+		return tg.ErrEndGroup
+	}
+}
+
+func rateLimitCallbacks(group rateGroup, handler tg.CallbackHandler) tg.CallbackHandler {
+	return func(cb *tg.CallbackQuery) error {
+		ok, wait, _, key := allowRate(group, cb.SenderID, cb.ChannelID())
+		if ok {
+			return handler(cb)
+		}
+		// Answering is required anyway, so callbacks are always told.
+		cb.Answer(rateLimitText(cb.ChannelID(), key, wait))
+		return tg.ErrEndGroup
+	}
+}
+
+// allowRate draws from the group's buckets. The owner and sudoers are not
+// limited.
+func allowRate(group rateGroup, userID, chatID int64) (bool, time.Duration, bool, string) {
+	if userID == config.OwnerID || database.IsSudoWithoutError(userID) {
+		return true, 0, false, ""
+	}
+
+	limits := rateGroupLimits[group]
+	name := string(group)
+	if group == rateGeneral {
+		name = "general"
+	}
+
+	return limiter.Allow(
+		utils.RateKey{
+			Key:   name + ":u:" + strconv.FormatInt(userID, 10),
+			Limit: limits.user,
+		},
+		utils.RateKey{
+			Key:   name + ":c:" + strconv.FormatInt(chatID, 10),
+			Limit: limits.chat,
+		},
+		utils.RateKey{Key: name + ":g", Limit: limits.global},
+	)
+}
+
+func rateLimitText(chatID int64, key string, wait time.Duration) string {
+	seconds := int(math.Ceil(wait.Seconds()))
+	switch {
+	case strings.Contains(key, ":u:"):
+		return F(chatID, "flood_seconds", locales.Arg{"duration": seconds})
+	case strings.Contains(key, ":c:"):
+		return F(chatID, "ratelimit_chat", locales.Arg{"duration": seconds})
+	default:
+		return F(chatID, "ratelimit_global", locales.Arg{"duration": seconds})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+var (
+	// recordIndicators holds the "recording" message of every chat being
+	// recorded; it is edited with the result once the upload is done.
+	recordIndicators   = make(map[int64]*tg.NewMessage)
+	recordIndicatorsMu sync.Mutex
+)
+
+func recordHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+	args := strings.Fields(strings.ToLower(m.Args()))
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "start", "on":
+			return handleRecordStart(m, args[1:])
+		case "stop", "off", "end":
+			if err := core.StopRecording(chatID); err != nil {
+				m.Reply(F(chatID, "record_not_running"))
+				return tg.ErrEndGroup
+			}
+			m.Reply(F(chatID, "record_stopping"))
+			return tg.ErrEndGroup
+		}
+	}
+
+	m.Reply(F(chatID, "record_usage", locales.Arg{
+		"cmd": getCommand(m),
+	}))
+	return tg.ErrEndGroup
+}
+
+func handleRecordStart(m *tg.NewMessage, opts []string) error {
+	chatID := m.ChannelID()
+
+	var video, toDM bool
+	for _, opt := range opts {
+		switch opt {
+		case "video", "-video", "--video":
+			video = true
+		case "dm", "pm", "-dm", "--dm":
+			toDM = true
+		default:
+			m.Reply(F(chatID, "record_usage", locales.Arg{
+				"cmd": getCommand(m),
+			}))
+			return tg.ErrEndGroup
+		}
+	}
+
+	if _, ok := core.GetRecording(chatID); ok {
+		m.Reply(F(chatID, "record_already_running"))
+		return tg.ErrEndGroup
+	}
+
+	cs, err := core.GetChatState(chatID)
+	if err != nil {
+		m.Reply(getErrorMessage(chatID, err))
+		return tg.ErrEndGroup
+	}
+	if activeVC, err := cs.IsActiveVC(); err != nil || !activeVC {
+		m.Reply(F(chatID, "err_no_active_voicechat"))
+		return tg.ErrEndGroup
+	}
+	if present, err := cs.IsAssistantPresent(); err != nil || !present {
+		if err := cs.TryJoin(); err != nil {
+			m.Reply(getErrorMessage(chatID, err))
+			return tg.ErrEndGroup
+		}
+	}
+
+	if _, err := core.StartRecording(
+		chatID, cs.Assistant, m.SenderID(), video, toDM,
+	); err != nil {
+		if errors.Is(err, core.ErrAlreadyRecording) {
+			m.Reply(F(chatID, "record_already_running"))
+		} else {
+			logger.ErrorF("Failed to start recording chat_id=%d: %v", chatID, err)
+			m.Reply(F(chatID, "record_start_failed", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+		}
+		return tg.ErrEndGroup
+	}
+
+	kb := tg.NewKeyboard().AddRow(
+		tg.Button.Data(F(chatID, "RECORD_STOP_BTN"), "record:stop"),
+	)
+	indicator, err := m.Reply(F(chatID, "record_started", locales.Arg{
+		"user":     utils.MentionHTML(m.Sender),
+		"mode":     F(chatID, utils.IfElse(video, "record_mode_video", "record_mode_audio")),
+		"max_time": formatDuration(config.RecordMaxDuration),
+		"max_size": config.RecordMaxSizeMB,
+		"target":   F(chatID, utils.IfElse(toDM, "record_target_dm", "record_target_chat")),
+	}), &tg.SendOptions{ReplyMarkup: kb.Build()})
+	if err == nil {
+		recordIndicatorsMu.Lock()
+		recordIndicators[chatID] = indicator
+		recordIndicatorsMu.Unlock()
+	}
+	return tg.ErrEndGroup
+}
+
+func recordCB(cb *tg.CallbackQuery) error {
+	chatID := cb.ChannelID()
+	opt := &tg.CallbackOptions{Alert: true}
+
+	if !checkAdminOrAuth(cb, chatID, opt) {
+		return tg.ErrEndGroup
+	}
+	if err := core.StopRecording(chatID); err != nil {
+		cb.Answer(F(chatID, "record_not_running"), opt)
+		return tg.ErrEndGroup
+	}
+	cb.Answer(F(chatID, "record_stopping"))
+	return tg.ErrEndGroup
+}
+
+// onRecordingDone uploads a finished recording to the chat, or to the DM of
+// the admin who started it when asked to, and reports on the indicator.
+func onRecordingDone(rec *core.Recording) {
+	defer rec.Cleanup()
+	chatID := rec.ChatID
+
+	recordIndicatorsMu.Lock()
+	indicator := recordIndicators[chatID]
+	delete(recordIndicators, chatID)
+	recordIndicatorsMu.Unlock()
+
+	report := func(text string) {
+		if indicator != nil {
+			if _, err := indicator.Edit(text); err == nil {
+				return
+			}
+		}
+		core.Bot.SendMessage(chatID, text)
+	}
+
+	if rec.Err != nil {
+		logger.ErrorF("Recording failed chat_id=%d: %v", chatID, rec.Err)
+		report(F(chatID, "record_failed", locales.Arg{
+			"error": html.EscapeString(rec.Err.Error()),
+		}))
+		return
+	}
+
+	duration := formatDuration(int(rec.Duration().Round(time.Second).Seconds()))
+	var size int64
+	for _, part := range rec.Parts {
+		if fi, err := os.Stat(part); err == nil {
+			size += fi.Size()
+		}
+	}
+
+	target := chatID
+	if rec.ToDM {
+		target = rec.By
+	}
+
+	for i, part := range rec.Parts {
+		caption := F(chatID, "record_caption", locales.Arg{
+			"duration": duration,
+			"part":     i + 1,
+			"parts":    len(rec.Parts),
+			"count":    len(rec.Parts),
+		})
+		_, err := core.Bot.SendMedia(target, part, &tg.MediaOptions{
+			Caption: caption,
+		})
+		if err != nil && target != chatID && i == 0 {
+			// The admin never started the bot, post it in the chat instead
+			logger.WarnF("Failed to send recording to %d: %v", target, err)
+			target = chatID
+			_, err = core.Bot.SendMedia(target, part, &tg.MediaOptions{
+				Caption: caption,
+			})
+		}
+		if err != nil {
+			logger.ErrorF("Failed to upload recording chat_id=%d: %v", chatID, err)
+			report(F(chatID, "record_upload_failed", locales.Arg{
+				"error": html.EscapeString(err.Error()),
+			}))
+			return
+		}
+	}
+
+	report(F(chatID, "record_done", locales.Arg{
+		"reason":   F(chatID, "record_reason_"+rec.Reason),
+		"duration": duration,
+		"size":     fmt.Sprintf("%.1f MB", float64(size)/1024/1024),
+		"parts": F(chatID, "record_parts", locales.Arg{
+			"count": len(rec.Parts),
+		}),
+		"target": F(chatID, utils.IfElse(target == chatID, "record_target_chat", "record_target_dm")),
+	}))
+}
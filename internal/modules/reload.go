@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -31,33 +30,6 @@ import (
 	"main/internal/utils"
 )
 
-func init() {
-	helpTexts["/reload"] = `<i>Reload admin cache and refresh voice chat state.</i>
-
-<u>Usage:</u>
-<b>/reload</b> — Refresh all cached data
-
-<b>🔄 What Gets Reloaded:</b>
-• Chat admin list
-• Voice chat status
-• Assistant presence status
-• Assistant ban status
-
-<b>🔒 Flood Protection:</b>
-• Regular users: 5 minute cooldown
-• Admins: 2 minute cooldown
-
-<b>💡 When to Use:</b>
-• After promoting/demoting admins
-• Voice chat issues
-• Permission problems
-• Bot behaving incorrectly
-
-<b>⚠️ Notes:</b>
-• May reset room state if admin permissions required
-`
-}
-
 func reloadHandler(m *telegram.NewMessage) error {
 	return handleReload(m, false)
 }
@@ -76,10 +48,15 @@ func handleReload(m *telegram.NewMessage, cplay bool) error {
 	chatID := m.ChannelID()
 	actualChatID := r.ChatID()
 	userID := m.SenderID()
-	floodKey := fmt.Sprintf("reload:%d%d", actualChatID, userID)
+	floodKey := fmt.Sprintf("reload:%d:%d", actualChatID, userID)
+
+	// Admins (as last cached) get a shorter cooldown
 	floodDuration := 5 * time.Minute
+	if ok, _ := utils.IsChatAdmin(m.Client, actualChatID, userID); ok {
+		floodDuration = 2 * time.Minute
+	}
 
-	if remaining := utils.GetFlood(floodKey); remaining > 0 {
+	if remaining := limiter.Cooldown(floodKey, floodDuration); remaining > 0 {
 		_, err := m.Reply(F(
 			chatID,
 			"flood_minutes",
@@ -116,11 +93,6 @@ func handleReload(m *telegram.NewMessage, cplay bool) error {
 		}
 	}
 
-	if isAdmin {
-		floodDuration = 2 * time.Minute
-	}
-	utils.SetFlood(floodKey, floodDuration)
-
 	cs, err := core.GetChatState(actualChatID)
 	if err != nil {
 		summary += F(chatID, "reload_assistant_fail", locales.Arg{
@@ -181,8 +153,8 @@ func handleReload(m *telegram.NewMessage, cplay bool) error {
 	if isAdmin {
 		ass, err := core.Assistants.ForChat(actualChatID)
 		if err != nil {
-			gologging.ErrorF(
-				"Failed to get Assistant for %d: %v",
+			logger.ErrorF(
+				"Failed to get assistant chat_id=%d: %v",
 				actualChatID,
 				err,
 			)
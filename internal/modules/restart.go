@@ -25,7 +25,6 @@ import (
 	"path/filepath"
 	"syscall"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -33,31 +32,12 @@ import (
 	"main/internal/utils"
 )
 
-func init() {
-	helpTexts["/restart"] = `<i>Restart the bot process.</i>
-
-<u>Usage:</u>
-<b>/restart</b> — Restart bot
-
-<b>⚙️ Behavior:</b>
-• Stops all active rooms
-• Notifies all active chats
-• Restarts bot process
-• Clears download cache
-
-<b>🔒 Restrictions:</b>
-• <b>Owner only</b> command
-
-<b>⚠️ Warning:</b>
-All playback will be interrupted. Bot will be offline for a few seconds.`
-}
-
 func handleRestart(m *tg.NewMessage) error {
 	chatID := m.ChannelID()
 
 	mystic, err := m.Reply(F(chatID, "restart"))
 	if err != nil {
-		gologging.Error("Failed to send restart message: " + err.Error())
+		logger.Error("Failed to send restart message: " + err.Error())
 	}
 
 	exePath, err := os.Executable()
@@ -79,7 +59,7 @@ func handleRestart(m *tg.NewMessage) error {
 	for _, id := range core.GetAllRoomIDs() {
 		ass, err := core.Assistants.ForChat(id)
 		if err != nil {
-			gologging.ErrorF("Failed to get Assistant for %d: %v", id, err)
+			logger.ErrorF("Failed to get assistant chat_id=%d: %v", id, err)
 			continue
 		}
 
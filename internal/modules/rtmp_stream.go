@@ -9,7 +9,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -23,96 +22,6 @@ var (
 	rtmpStreamsMu sync.RWMutex
 )
 
-func init() {
-	helpTexts["stream"] = `<i>Start RTMP live streaming to configured server.</i>
-
-<u>Usage:</u>
-<b>/stream &lt;query/URL&gt;</b> — Start streaming a track
-<b>/stream [reply to audio/video]</b> — Stream replied media
-
-<b>🎥 Features:</b>
-• Live streaming to your RTMP server
-• Supports audio and video
-• Queue support (like /play)
-• Real-time status monitoring
-
-<b>⚙️ Setup Required:</b>
-Before using this command, an admin must configure RTMP:
-1. Open bot's private chat (DM)
-2. Send: <code>/setrtmp &lt;chat_id&gt; &lt;rtmp_url&gt;</code>
-
-<b>📝 Example Setup:</b>
-In bot DM:
-<code>/setrtmp -1001234567890 rtmps://dc5-1.rtmp.t.me/s/123:key</code>
-
-Then in your chat:
-<code>/stream never gonna give you up</code>
-
-<b>⚠️ Important Notes:</b>
-• RTMP streams have ~15-30s buffering delay
-• Setup ONLY works in bot DM (for security)
-• Use <code>/streamstop</code> to end stream
-• Only admin/auth users can control streams
-• We do NOT use Telegram's RTMP API - you provide your own server`
-
-	helpTexts["streamstop"] = `<i>Stop current RTMP stream.</i>
-
-<u>Usage:</u>
-<b>/streamstop</b> — Stop the active stream
-
-<b>⚠️ Note:</b>
-Only admin/auth users can stop streams.`
-
-	helpTexts["streamstatus"] = `<i>Check current RTMP stream status.</i>
-
-<u>Usage:</u>
-<b>/streamstatus</b> — Show stream information
-
-<b>📊 Shows:</b>
-• Stream state (playing/stopped)
-• Current position
-• RTMP server (masked for security)
-• Configuration status`
-
-	helpTexts["setrtmp"] = `<i>Configure RTMP streaming server (DM only).</i>
-
-<u>Usage:</u>
-<b>/setrtmp &lt;chat_id&gt; &lt;rtmp_url&gt;</b> — Set RTMP for a chat
-
-<b>🔒 Security:</b>
-• <b>This command ONLY works in DM</b> (private chat with bot)
-• NEVER share RTMP credentials in groups
-• Credentials are stored securely in database
-• We do NOT use Telegram's RTMP API
-
-<b>📋 URL Format:</b>
-<code>rtmp://server/app/streamkey</code>
-or
-<code>rtmps://server/s/streamkey</code>
-
-<b>📝 Examples:</b>
-
-<b>Telegram Voice Chat:</b>
-1. Start voice chat in your channel
-2. Telegram gives you: <code>rtmps://dc5-1.rtmp.t.me/s/123:key</code>
-3. In bot DM send: <code>/setrtmp -1001234567890 rtmps://dc5-1.rtmp.t.me/s/123:key</code>
-
-<b>Custom RTMP Server:</b>
-<code>/setrtmp -1001234567890 rtmp://live.example.com/stream/mykey</code>
-
-<b>🔍 Getting Chat ID:</b>
-• Forward message from chat to @userinfobot
-• Or use <code>/id</code> command in the chat
-
-<b>⚠️ Requirements:</b>
-• You must be admin in target chat
-• Bot must be member of target chat
-• Command only works in bot's private chat
-
-<b>💡 Why DM only?</b>
-RTMP stream keys are like passwords. Configuring in DM prevents accidental exposure in group chats.`
-}
-
 // Get or create RTMP stream for chat
 func getOrCreateRTMPStream(chatID int64) (*tg.RTMPStream, error) {
 	rtmpStreamsMu.Lock()
@@ -139,7 +48,7 @@ func getOrCreateRTMPStream(chatID int64) (*tg.RTMPStream, error) {
 	stream.SetKey(key)
 
 	stream.OnError(func(err error) {
-		gologging.ErrorF("RTMP error in chat %d: %v", chatID, err)
+		logger.ErrorF("RTMP error chat_id=%d: %v", chatID, err)
 		core.Bot.SendMessage(
 			chatID,
 			"⚠️ RTMP stream encountered an error. Check logs for details.",
@@ -202,11 +111,12 @@ func handleStream(m *tg.NewMessage, force bool) error {
 
 	replyMsg, err := m.Reply(searchStr)
 	if err != nil {
-		gologging.ErrorF("Failed to send searching message: %v", err)
+		logger.ErrorF("Failed to send searching message: %v", err)
 		return tg.ErrEndGroup
 	}
 
-	tracks, err := safeGetTracks(m, replyMsg, chatID, false)
+	prefs, _ := database.GetChatPrefs(chatID)
+	tracks, err := safeGetTracks(m, replyMsg, chatID, false, prefs.SearchPlatform)
 	if err != nil {
 		utils.EOR(replyMsg, err.Error())
 		return tg.ErrEndGroup
@@ -220,6 +130,7 @@ func handleStream(m *tg.NewMessage, force bool) error {
 	track := tracks[0]
 	mention := utils.MentionHTML(m.Sender)
 	track.Requester = mention
+	track.RequesterID = m.SenderID()
 
 	// Download track
 	downloadingText := F(chatID, "play_downloading_song", locales.Arg{
@@ -0,0 +1,211 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	state "main/internal/core/models"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/platforms"
+	"main/internal/utils"
+)
+
+// Choices offered by the panel; only the ones below the global limit are
+// shown since a chat can only tighten them.
+var (
+	queueLimitChoices    = []int{5, 10, 25, 50, 100}
+	durationLimitChoices = []int{5 * 60, 10 * 60, 30 * 60, 60 * 60, 2 * 60 * 60, 3 * 60 * 60}
+)
+
+func settingsHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	prefs, err := database.GetChatPrefs(chatID)
+	if err != nil {
+		m.Reply(F(chatID, "settings_fail"))
+		return tg.ErrEndGroup
+	}
+
+	m.Reply(
+		F(chatID, "settings_panel"),
+		&tg.SendOptions{ReplyMarkup: settingsMarkup(chatID, prefs)},
+	)
+	return tg.ErrEndGroup
+}
+
+func settingsCB(cb *tg.CallbackQuery) error {
+	chatID := cb.ChannelID()
+	opt := &tg.CallbackOptions{Alert: true}
+
+	if isAdmin, err := utils.IsChatAdmin(cb.Client, chatID, cb.SenderID); err != nil ||
+		!isAdmin {
+		cb.Answer(F(chatID, "only_admin_or_auth_cb"), opt)
+		return tg.ErrEndGroup
+	}
+
+	prefs, err := database.GetChatPrefs(chatID)
+	if err != nil {
+		cb.Answer(F(chatID, "settings_fail"), opt)
+		return tg.ErrEndGroup
+	}
+
+	switch strings.TrimPrefix(cb.DataString(), "settings:") {
+	case "play_mode":
+		prefs.PlayMode = utils.IfElse(
+			prefs.PlayMode == database.PlayModeAdmins,
+			database.PlayModeEveryone,
+			database.PlayModeAdmins,
+		)
+	case "queue":
+		prefs.QueueLimit = nextLimit(prefs.QueueLimit, queueLimitChoices, config.QueueLimit)
+	case "duration":
+		prefs.DurationLimit = nextLimit(
+			prefs.DurationLimit,
+			durationLimitChoices,
+			config.DurationLimit,
+		)
+	case "video":
+		prefs.VideoDisabled = !prefs.VideoDisabled
+	case "video_mode":
+		prefs.VideoMode = nextChoice(prefs.VideoMode, state.VideoModes)
+	case "search":
+		prefs.SearchPlatform = nextChoice(prefs.SearchPlatform, platforms.SearchPlatforms)
+		if prefs.SearchPlatform == platforms.SearchYouTube {
+			prefs.SearchPlatform = ""
+		}
+	case "autodel":
+		prefs.AutoDeleteCmds = !prefs.AutoDeleteCmds
+	case "np_cleanup":
+		prefs.KeepNowPlaying = !prefs.KeepNowPlaying
+	case "idle":
+		prefs.KeepPlayingAlone = !prefs.KeepPlayingAlone
+	case "lang":
+		current, _ := database.GetChatLanguage(chatID)
+		next := nextChoice(current, locales.GetAvailableLanguages())
+		if err := database.SetChatLanguage(chatID, next); err != nil {
+			logger.ErrorF("SetChatLanguage error: %v", err)
+			cb.Answer(F(chatID, "settings_fail"), opt)
+			return tg.ErrEndGroup
+		}
+	default:
+		cb.Answer("")
+		return tg.ErrEndGroup
+	}
+
+	if err := database.SetChatPrefs(chatID, prefs); err != nil {
+		logger.ErrorF("Failed to save chat prefs chat_id=%d: %v", chatID, err)
+		cb.Answer(F(chatID, "settings_fail"), opt)
+		return tg.ErrEndGroup
+	}
+
+	cb.Answer(F(chatID, "settings_saved"))
+	cb.Edit(
+		F(chatID, "settings_panel"),
+		&tg.SendOptions{ReplyMarkup: settingsMarkup(chatID, prefs)},
+	)
+	return tg.ErrEndGroup
+}
+
+func settingsMarkup(chatID int64, p database.ChatPrefs) *tg.ReplyInlineMarkup {
+	onOff := func(on bool) string {
+		return F(chatID, utils.IfElse(on, "settings_on", "settings_off"))
+	}
+	limit := func(value, global int, format func(int) string) string {
+		if value <= 0 || value >= global {
+			return F(chatID, "settings_default", locales.Arg{"value": format(global)})
+		}
+		return format(value)
+	}
+	row := func(label, value, key string) tg.KeyboardButton {
+		return tg.Button.Data(F(chatID, label)+": "+value, "settings:"+key)
+	}
+
+	lang, _ := database.GetChatLanguage(chatID)
+	search := utils.IfElse(p.SearchPlatform == "", platforms.SearchYouTube, p.SearchPlatform)
+
+	return tg.NewKeyboard().
+		AddRow(row(
+			"settings_play_mode",
+			F(chatID, utils.IfElse(
+				p.PlayMode == database.PlayModeAdmins,
+				"settings_admins",
+				"settings_everyone",
+			)),
+			"play_mode",
+		)).
+		AddRow(row(
+			"settings_queue_limit",
+			limit(p.QueueLimit, config.QueueLimit, strconv.Itoa),
+			"queue",
+		)).
+		AddRow(row(
+			"settings_duration_limit",
+			limit(p.DurationLimit, config.DurationLimit, formatDuration),
+			"duration",
+		)).
+		AddRow(row("settings_video", onOff(!p.VideoDisabled), "video")).
+		AddRow(row(
+			"settings_video_mode",
+			F(chatID, "settings_video_mode_"+utils.IfElse(p.VideoMode == state.VideoCamera, "camera", string(p.VideoMode))),
+			"video_mode",
+		)).
+		AddRow(row("settings_language", locales.Get(lang, "name", nil), "lang")).
+		AddRow(row("settings_search", F(chatID, "settings_search_"+search), "search")).
+		AddRow(row("settings_autodel", onOff(p.AutoDeleteCmds), "autodel")).
+		AddRow(row("settings_np_cleanup", onOff(!p.KeepNowPlaying), "np_cleanup")).
+		AddRow(row("settings_idle", onOff(!p.KeepPlayingAlone), "idle")).
+		AddRow(tg.Button.Data(F(chatID, "CLOSE_BTN"), "close")).
+		Build()
+}
+
+// nextLimit cycles default (0) -> each choice below global -> default.
+func nextLimit(current int, choices []int, global int) int {
+	opts := []int{0}
+	for _, c := range choices {
+		if c < global {
+			opts = append(opts, c)
+		}
+	}
+	return nextChoice(current, opts)
+}
+
+func nextChoice[T comparable](current T, opts []T) T {
+	i := slices.Index(opts, current)
+	return opts[(i+1)%len(opts)]
+}
+
+// autoDeleteCommand removes a handled command message in chats that turned
+// it on in /settings.
+func autoDeleteCommand(m *tg.NewMessage) {
+	if m.ChatType() == tg.EntityUser {
+		return
+	}
+	if prefs, err := database.GetChatPrefs(m.ChannelID()); err == nil && prefs.AutoDeleteCmds {
+		m.Delete()
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"context"
 	"html"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -33,26 +32,6 @@ import (
 	"main/internal/utils"
 )
 
-func init() {
-	helpTexts["/skip"] = `<i>Skip the currently playing track and play the next in queue.</i>
-
-<u>Usage:</u>
-<b>/skip</b> — Skip current track
-
-<b>⚙️ Behavior:</b>
-• Downloads next track in queue
-• Starts playback automatically
-• If queue is empty and loop is 0, stops playback
-
-<b>🔒 Restrictions:</b>
-• Only <b>chat admins</b> or <b>authorized users</b> can use this
-
-<b>⚠️ Notes:</b>
-• Cannot be undone
-• If no tracks in queue, playback stops
-• Loop count affects skip behavior`
-}
-
 func skipHandler(m *telegram.NewMessage) error {
 	return handleSkip(m, false)
 }
@@ -91,7 +70,7 @@ func handleSkip(m *telegram.NewMessage, cplay bool) error {
 		F(chatID, "stream_downloading_next"),
 	)
 	if err != nil {
-		gologging.ErrorF("[skip.go] err: %v", err)
+		logger.ErrorF("[skip.go] err: %v", err)
 	}
 
 	path, err := platforms.Download(context.Background(), t, mystic)
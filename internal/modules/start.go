@@ -21,7 +21,6 @@
 package modules
 
 import (
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -31,10 +30,6 @@ import (
 	"main/internal/utils"
 )
 
-func init() {
-	helpTexts["/start"] = `<i>Start the bot and show main menu.</i>`
-}
-
 func startHandler(m *tg.NewMessage) error {
 	if m.ChatType() != tg.EntityUser {
 		database.AddServed(m.ChannelID())
@@ -48,7 +43,7 @@ func startHandler(m *tg.NewMessage) error {
 	database.AddServed(m.ChannelID(), true)
 
 	if arg != "" {
-		gologging.Info(
+		logger.Info(
 			"Got Start parameter: " + arg + " in ChatID: " + utils.IntToStr(
 				m.ChannelID(),
 			),
@@ -57,7 +52,7 @@ func startHandler(m *tg.NewMessage) error {
 
 	switch arg {
 	case "pm_help":
-		gologging.Info("User requested help via start param")
+		logger.Info("User requested help via start param")
 		helpHandler(m)
 
 	default:
@@ -75,7 +70,7 @@ func startHandler(m *tg.NewMessage) error {
 			ReplyMarkup: core.GetStartMarkup(m.ChannelID()),
 		})
 		if err != nil {
-			gologging.Error(
+			logger.Error(
 				"[start] InputMediaWebPage Reply failed: " + err.Error(),
 			)
 
@@ -85,7 +80,7 @@ func startHandler(m *tg.NewMessage) error {
 				ReplyMarkup: core.GetStartMarkup(m.ChannelID()),
 			})
 			if err != nil {
-				gologging.Error(
+				logger.Error(
 					"[start] URL media reply failed: " + err.Error(),
 				)
 
@@ -110,7 +105,7 @@ func startHandler(m *tg.NewMessage) error {
 		})
 		_, err := m.Client.SendMessage(config.LoggerID, msg)
 		if err != nil {
-			gologging.Error(
+			logger.Error(
 				"Failed to send logger_bot_started msg, Err: " + err.Error(),
 			)
 		}
@@ -36,23 +36,6 @@ import (
 	"main/internal/locales"
 )
 
-func init() {
-	helpTexts["/stats"] = `<i>View detailed bot statistics.</i>
-
-<u>Usage:</u>
-<b>/stats</b> — Show statistics
-
-<b>📊 Information Shown:</b>
-• System stats (OS, CPU, RAM, disk)
-• Go runtime stats (memory, GC)
-• Server resources
-• Served chats count
-• Served users count
-
-<b>🔒 Restrictions:</b>
-• <b>Sudo users</b> only`
-}
-
 func statsHandler(m *telegram.NewMessage) error {
 	var sb strings.Builder
 	sb.Grow(512)
@@ -61,7 +44,8 @@ func statsHandler(m *telegram.NewMessage) error {
 	sb.WriteString(getSystemStats(chatID))
 	sb.WriteString(getGoMemStats(chatID))
 	sb.WriteString(getServerStats(chatID))
-	sb.WriteString(getServedStats(chatID))
+	sb.WriteString(getServedStats(chatID) + "\n")
+	sb.WriteString(getListenStats(chatID))
 
 	m.Reply(sb.String())
 	return telegram.ErrEndGroup
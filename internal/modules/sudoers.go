@@ -26,7 +26,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -82,7 +81,7 @@ func handleAddSudo(m *telegram.NewMessage) error {
 		m.Reply(F(chatID, "sudo_fetch_user_fail", locales.Arg{
 			"error": err.Error(),
 		}))
-		gologging.Error("Failed to get user: " + err.Error())
+		logger.Error("Failed to get user: " + err.Error())
 		return telegram.ErrEndGroup
 	}
 
@@ -142,7 +141,7 @@ func handleAddSudo(m *telegram.NewMessage) error {
 			"",
 			sudoCommands,
 		); err != nil {
-			gologging.Error("Failed to set PrivateSudoCommands " + err.Error())
+			logger.Error("Failed to set PrivateSudoCommands " + err.Error())
 		}
 	}
 
@@ -221,7 +220,7 @@ func handleDelSudo(m *telegram.NewMessage) error {
 		},
 		"",
 	); err != nil {
-		gologging.Error("Failed to reset sudo commands: " + err.Error())
+		logger.Error("Failed to reset sudo commands: " + err.Error())
 	}
 
 	// Delete from DB
@@ -244,14 +243,13 @@ func handleDelSudo(m *telegram.NewMessage) error {
 func handleGetSudoers(m *telegram.NewMessage) error {
 	chatID := m.ChannelID()
 
-	floodKey := fmt.Sprintf("sudoers:%d%d", chatID, m.SenderID())
-	if remaining := utils.GetFlood(floodKey); remaining > 0 {
+	floodKey := fmt.Sprintf("sudoers:%d:%d", chatID, m.SenderID())
+	if remaining := limiter.Cooldown(floodKey, 30*time.Second); remaining > 0 {
 		m.Reply(F(chatID, "flood_seconds", locales.Arg{
-			"duration": int(remaining.Seconds()),
+			"duration": int(remaining.Seconds()) + 1,
 		}))
 		return telegram.ErrEndGroup
 	}
-	utils.SetFlood(floodKey, 30*time.Second)
 
 	// "⏳ Fetching sudoers list..."
 	mystic, _ := m.Reply(F(chatID, "sudo_list_fetching"))
@@ -0,0 +1,170 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/utils"
+)
+
+const topLimit = 10
+
+// topPeriods maps the /top periods to how far back they reach; 0 covers
+// the whole history.
+var topPeriods = map[string]time.Duration{
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"all":   0,
+}
+
+// onPlayEnded logs every finished, skipped or stopped play in the history.
+func onPlayEnded(p *core.PlayRecord) {
+	t := p.Track
+	entry := &database.PlayEntry{
+		ChatID:      p.ChatID,
+		TrackID:     t.ID,
+		Title:       t.Title,
+		Source:      string(t.Source),
+		RequesterID: t.RequesterID,
+		Duration:    t.Duration,
+		Played:      int(p.Played().Seconds()),
+		Skipped:     !p.Finished,
+		StartedAt:   p.StartedAt,
+	}
+	if config.PlayHistoryDays > 0 {
+		entry.ExpiresAt = p.StartedAt.AddDate(0, 0, config.PlayHistoryDays)
+	}
+	database.AddPlay(entry)
+}
+
+// parseTopPeriod reads the optional week/month/all argument, week by
+// default.
+func parseTopPeriod(m *tg.NewMessage) (string, time.Time, bool) {
+	period := strings.ToLower(strings.TrimSpace(m.Args()))
+	if period == "" {
+		period = "week"
+	}
+	d, ok := topPeriods[period]
+	if !ok {
+		return "", time.Time{}, false
+	}
+	if d == 0 {
+		return period, time.Time{}, true
+	}
+	return period, time.Now().Add(-d), true
+}
+
+func topHandler(m *tg.NewMessage) error {
+	return handleTopTracks(m, m.ChannelID())
+}
+
+func globalTopHandler(m *tg.NewMessage) error {
+	return handleTopTracks(m, 0)
+}
+
+// handleTopTracks lists the most played tracks of chatID, or of every
+// chat when chatID is 0.
+func handleTopTracks(m *tg.NewMessage, chatID int64) error {
+	replyID := m.ChannelID()
+	period, since, ok := parseTopPeriod(m)
+	if !ok {
+		m.Reply(F(replyID, "top_usage", locales.Arg{"cmd": getCommand(m)}))
+		return tg.ErrEndGroup
+	}
+
+	tracks, err := database.GetTopTracks(chatID, since, topLimit)
+	if err != nil {
+		m.Reply(F(replyID, "top_fetch_fail", locales.Arg{"error": err.Error()}))
+		return tg.ErrEndGroup
+	}
+	if len(tracks) == 0 {
+		m.Reply(F(replyID, "top_empty"))
+		return tg.ErrEndGroup
+	}
+
+	header := utils.IfElse(chatID == 0, "globaltop_header", "top_header")
+
+	var sb strings.Builder
+	sb.WriteString(F(replyID, header, locales.Arg{
+		"period": F(replyID, "top_period_"+period),
+	}) + "\n\n")
+	for i, t := range tracks {
+		sb.WriteString(F(replyID, "top_track", locales.Arg{
+			"index": i + 1,
+			"title": html.EscapeString(utils.ShortTitle(t.Title, 35)),
+			"plays": t.Plays,
+			"skips": t.Skips,
+		}) + "\n")
+	}
+
+	m.Reply(sb.String())
+	return tg.ErrEndGroup
+}
+
+func topUsersHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+	period, since, ok := parseTopPeriod(m)
+	if !ok {
+		m.Reply(F(chatID, "top_usage", locales.Arg{"cmd": getCommand(m)}))
+		return tg.ErrEndGroup
+	}
+
+	users, err := database.GetTopRequesters(chatID, since, topLimit)
+	if err != nil {
+		m.Reply(F(chatID, "top_fetch_fail", locales.Arg{"error": err.Error()}))
+		return tg.ErrEndGroup
+	}
+	if len(users) == 0 {
+		m.Reply(F(chatID, "top_empty"))
+		return tg.ErrEndGroup
+	}
+
+	var sb strings.Builder
+	sb.WriteString(F(chatID, "topusers_header", locales.Arg{
+		"period": F(chatID, "top_period_"+period),
+	}) + "\n\n")
+	for i, u := range users {
+		// Plain names, a mention would notify everyone on the list
+		name := fmt.Sprintf("<code>%d</code>", u.UserID)
+		if user, err := m.Client.GetUser(u.UserID); err == nil {
+			name = html.EscapeString(strings.TrimSpace(user.FirstName + " " + user.LastName))
+		}
+		sb.WriteString(F(chatID, "topusers_user", locales.Arg{
+			"index":  i + 1,
+			"user":   name,
+			"plays":  u.Plays,
+			"played": formatDuration(int(u.Played)),
+		}) + "\n")
+	}
+
+	m.Reply(sb.String())
+	return tg.ErrEndGroup
+}
@@ -24,7 +24,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/config"
@@ -57,13 +56,18 @@ func handleParticipantUpdate(p *telegram.ParticipantUpdate) error {
 
 	s, err := core.GetChatState(chatID)
 	if err != nil {
-		gologging.Error("Failed to get chat state: " + err.Error())
+		logger.Error("Failed to get chat state: " + err.Error())
 	}
 
 	if err == nil && p.UserID() == s.Assistant.User.ID {
 		handleAssistantState(p, s, chatID)
 	}
 
+	// Any assistant, not only the current one, may be let back in
+	if isBanLifted(p) {
+		core.Assistants.ClearBan(chatID, p.UserID())
+	}
+
 	if p.UserID() == core.BUser.ID {
 		handleBotState(p, chatID)
 	}
@@ -116,14 +120,14 @@ func handleBotState(p *telegram.ParticipantUpdate, chatID int64) {
 		return
 	}
 
-	gologging.Debug(
+	logger.Debug(
 		"Bot " + action + " from chatID " + utils.IntToStr(chatID) +
 			": " + utils.IntToStr(p.ActorID()),
 	)
 
 	ass, aErr := core.Assistants.ForChat(chatID)
 	if aErr != nil {
-		gologging.ErrorF("Failed to get Assistant for %d: %v", chatID, aErr)
+		logger.ErrorF("Failed to get assistant chat_id=%d: %v", chatID, aErr)
 	}
 	core.DeleteRoom(chatID)
 	core.DeleteChatState(chatID)
@@ -159,12 +163,12 @@ func handleBotState(p *telegram.ParticipantUpdate, chatID int64) {
 
 		_, err := p.Client.SendMessage(config.LoggerID, msg)
 		if err != nil {
-			gologging.Error(
+			logger.Error(
 				"Failed to send logger_bot_removed msg, Error: " + err.Error(),
 			)
 		}
 	}
-	gologging.Debug("Bot left chat " + utils.IntToStr(chatID))
+	logger.DebugF("Bot left chat_id=%d", chatID)
 
 	return
 }
@@ -200,15 +204,24 @@ func handleAssistantRestriction(
 	chatID int64,
 	s *core.ChatState,
 ) {
-	gologging.Debug("Assistant restricted in chatID " + utils.IntToStr(chatID))
+	logger.Debug("Assistant restricted in chatID " + utils.IntToStr(chatID))
 
 	s.SetAssistantPresent(false)
-	core.DeleteRoom(chatID)
 
 	ok, err := p.Unban()
 	if err != nil || !ok {
 		s.SetAssistantBanned(true)
 
+		// Move the chat, and a playing room with it, to an assistant that
+		// is still allowed in.
+		if ass, err := core.Assistants.Reassign(chatID, "restricted"); err == nil {
+			p.Client.SendMessage(chatID, F(chatID, "assistant_switched", locales.Arg{
+				"user": utils.MentionHTML(ass.User),
+			}))
+			return
+		}
+		core.DeleteRoom(chatID)
+
 		if !shouldIgnoreParticipant(p) {
 			_, sendErr := s.Assistant.Client.SendMessage(
 				chatID,
@@ -219,13 +232,15 @@ func handleAssistantRestriction(
 			)
 
 			if sendErr != nil {
-				gologging.Error(
+				logger.Error(
 					"Failed to send assistant restricted warning in ChatID: " +
 						utils.IntToStr(chatID) + " Error: " + sendErr.Error(),
 				)
 			}
 		}
+		return
 	}
+	core.DeleteRoom(chatID)
 }
 
 func handleAssistantFallback(
@@ -239,7 +254,7 @@ func handleAssistantFallback(
 			s.SetAssistantPresent(false)
 			s.SetAssistantBanned(false)
 		} else {
-			gologging.Error("Error getting assistant membership; ChatId: " +
+			logger.Error("Error getting assistant membership; ChatId: " +
 				utils.IntToStr(chatID) + ", Error: " + err.Error())
 		}
 		return
@@ -317,7 +332,7 @@ func handleGroupCallAction(
 	core.DeleteRoom(chatID)
 	s, err := core.GetChatState(chatID)
 	if err != nil {
-		gologging.Error("Failed to get chat state: " + err.Error())
+		logger.Error("Failed to get chat state: " + err.Error())
 		return telegram.ErrEndGroup
 	}
 
@@ -325,12 +340,12 @@ func handleGroupCallAction(
 		// Voice chat started
 		s.SetVoiceChatActive(true)
 		m.Respond(F(chatID, "voicechat_started"))
-		gologging.Debug("Voice chat started in " + utils.IntToStr(chatID))
+		logger.Debug("Voice chat started in " + utils.IntToStr(chatID))
 	} else {
 		// Voice chat ended
 		s.SetVoiceChatActive(false)
 		m.Respond(F(chatID, "voicechat_ended"))
-		gologging.Debug("Voice chat ended in " + utils.IntToStr(chatID))
+		logger.Debug("Voice chat ended in " + utils.IntToStr(chatID))
 	}
 
 	return telegram.ErrEndGroup
@@ -343,7 +358,7 @@ func handleAddUserAction(
 	isMaintenance bool,
 ) error {
 	for _, uid := range action.Users {
-		gologging.Debug(
+		logger.Debug(
 			"User added to chatID " + utils.IntToStr(
 				chatID,
 			) + ": " + utils.IntToStr(
@@ -356,6 +371,13 @@ func handleAddUserAction(
 			continue
 		}
 
+		// Blacklisted chats are left right away, whoever added the bot
+		if database.IsBlacklistedChat(chatID) {
+			m.Respond(F(chatID, "blacklist_chat_added_leave"))
+			go leaveBlacklistedChat(m.Client, chatID)
+			return telegram.ErrEndGroup
+		}
+
 		// Bot added during maintenance
 		// Only owner + sudo can add the bot during maintenance
 		if isMaintenance && m.SenderID() != config.OwnerID {
@@ -376,7 +398,7 @@ func handleAddUserAction(
 				m.Reply(msg)
 				m.Client.LeaveChannel(chatID)
 
-				gologging.Debug(
+				logger.Debug(
 					"Bot left chatID " + utils.IntToStr(
 						chatID,
 					) + " due to maintenance",
@@ -416,19 +438,28 @@ func handleAddUserAction(
 
 			_, err := m.Client.SendMessage(config.LoggerID, msg)
 			if err != nil {
-				gologging.Error(
+				logger.Error(
 					"Failed to send logger_bot_added msg, Error: " + err.Error(),
 				)
 			}
 		}
 
-		gologging.Debug("Bot added to chat: " + utils.IntToStr(chatID))
+		logger.Debug("Bot added to chat: " + utils.IntToStr(chatID))
 		database.AddServed(chatID)
 		return telegram.ErrEndGroup
 	}
 	return telegram.ErrEndGroup
 }
 
+func isBanLifted(p *telegram.ParticipantUpdate) bool {
+	if p.IsJoined() {
+		return true
+	}
+	_, wasBanned := p.Old.(*telegram.ChannelParticipantBanned)
+	_, isBanned := p.New.(*telegram.ChannelParticipantBanned)
+	return wasBanned && !isBanned
+}
+
 func isRestricted(newParticipant telegram.ChannelParticipant) bool {
 	_, left := newParticipant.(*telegram.ChannelParticipantLeft)
 	_, banned := newParticipant.(*telegram.ChannelParticipantBanned)
@@ -0,0 +1,165 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package modules
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+
+	state "main/internal/core/models"
+	"main/internal/database"
+	"main/internal/locales"
+	"main/internal/platforms"
+	"main/internal/utils"
+)
+
+func wrongSongHandler(m *tg.NewMessage) error {
+	chatID := m.ChannelID()
+
+	r, err := getEffectiveRoom(m, false)
+	if err != nil {
+		m.Reply(err.Error())
+		return tg.ErrEndGroup
+	}
+	if !r.IsActiveChat() {
+		m.Reply(F(chatID, "room_no_active"))
+		return tg.ErrEndGroup
+	}
+
+	t := r.Track()
+	if t == nil || t.Source != platforms.PlatformSpotify {
+		m.Reply(F(chatID, "wrongsong_not_spotify"))
+		return tg.ErrEndGroup
+	}
+	if !canRepick(m.Client, chatID, m.SenderID(), t) {
+		m.Reply(F(chatID, "only_admin_or_auth"))
+		return tg.ErrEndGroup
+	}
+
+	mystic, _ := m.Reply(F(chatID, "wrongsong_searching"))
+
+	matches, err := platforms.SpotifyMatches(t)
+	if err != nil {
+		utils.EOR(mystic, F(chatID, "wrongsong_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+	if len(matches) < 2 {
+		utils.EOR(mystic, F(chatID, "wrongsong_no_alternatives"))
+		return tg.ErrEndGroup
+	}
+
+	kb := tg.NewKeyboard()
+	for _, alt := range matches[1:] {
+		label := utils.ShortTitle(alt.Title, 30) + " · " + formatDuration(alt.Duration)
+		if alt.Channel != "" {
+			label += " · " + utils.ShortTitle(alt.Channel, 15)
+		}
+		kb.AddRow(tg.Button.Data(label, "wrongsong:"+t.ID+":"+alt.ID))
+	}
+	kb.AddRow(tg.Button.Data(F(chatID, "CLOSE_BTN"), "close"))
+
+	current := matches[0]
+	utils.EOR(mystic, F(chatID, "wrongsong_pick", locales.Arg{
+		"title":   html.EscapeString(utils.ShortTitle(t.Title, 25)),
+		"match":   html.EscapeString(utils.ShortTitle(current.Title, 40)),
+		"url":     current.URL,
+		"channel": html.EscapeString(current.Channel),
+	}), &tg.SendOptions{ReplyMarkup: kb.Build()})
+	return tg.ErrEndGroup
+}
+
+// wrongSongCB switches the playing Spotify track to the picked YouTube
+// video and remembers the pick for later plays of the same track.
+func wrongSongCB(cb *tg.CallbackQuery) error {
+	chatID := cb.ChannelID()
+	opt := &tg.CallbackOptions{Alert: true}
+
+	parts := strings.Split(cb.DataString(), ":")
+	if len(parts) != 3 {
+		cb.Answer(F(chatID, "invalid_request"), opt)
+		return tg.ErrEndGroup
+	}
+	spotifyID, youtubeID := parts[1], parts[2]
+
+	r, err := getRoomForCallback(chatID)
+	if err != nil {
+		cb.Answer(F(chatID, "room_not_active_cb"), opt)
+		return tg.ErrEndGroup
+	}
+
+	t := r.Track()
+	if t == nil || t.ID != spotifyID {
+		cb.Answer(F(chatID, "wrongsong_stale"), opt)
+		return tg.ErrEndGroup
+	}
+	if !canRepick(cb.Client, chatID, cb.SenderID, t) {
+		cb.Answer(F(chatID, "only_admin_or_auth_cb"), opt)
+		return tg.ErrEndGroup
+	}
+
+	if !platforms.SetSpotifyMatch(spotifyID, youtubeID, cb.SenderID) {
+		cb.Answer(F(chatID, "wrongsong_stale"), opt)
+		return tg.ErrEndGroup
+	}
+
+	cb.Answer("")
+	editMessage(cb, F(chatID, "wrongsong_switching"))
+
+	path, err := platforms.Download(context.Background(), t, nil)
+	if err != nil {
+		logger.ErrorF("Wrongsong download failed for %s: %v", youtubeID, err)
+		editMessage(cb, F(chatID, "stream_download_fail", locales.Arg{
+			"error": html.EscapeString(err.Error()),
+		}))
+		return tg.ErrEndGroup
+	}
+
+	if err := r.Play(t, path, true); err != nil {
+		logger.ErrorF("Wrongsong play failed chat_id=%d: %v", chatID, err)
+		editMessage(cb, F(chatID, "stream_play_fail"))
+		return tg.ErrEndGroup
+	}
+
+	logger.InfoF("Spotify track_id=%s rematched to %s chat_id=%d", spotifyID, youtubeID, chatID)
+	editMessage(cb, F(chatID, "wrongsong_switched", locales.Arg{
+		"title": html.EscapeString(utils.ShortTitle(t.Title, 25)),
+		"user":  utils.MentionHTML(cb.Sender),
+	}))
+	return tg.ErrEndGroup
+}
+
+// canRepick allows the requester of the track besides admins and auth
+// users.
+func canRepick(c *tg.Client, chatID, userID int64, t *state.Track) bool {
+	if t.RequesterID != 0 && t.RequesterID == userID {
+		return true
+	}
+	if isAdmin, err := utils.IsChatAdmin(c, chatID, userID); err == nil && isAdmin {
+		return true
+	}
+	isAuth, err := database.IsAuthUser(chatID, userID)
+	return err == nil && isAuth
+}
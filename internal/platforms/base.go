@@ -30,14 +30,18 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
+	"main/internal/config"
 	state "main/internal/core/models"
+	"main/internal/metrics"
 	"main/internal/utils"
 )
 
+var logger = config.GetLogger(config.DefaultLogger)
+
 // PlatformRegistry manages all registered platforms
 type PlatformRegistry struct {
 	platforms []platformEntry
@@ -53,6 +57,23 @@ var registry = &PlatformRegistry{
 	platforms: make([]platformEntry, 0),
 }
 
+var (
+	downloadsInFlight = metrics.NewGauge(
+		"yukki_downloads_in_flight",
+		"Track downloads currently running.",
+	)
+	downloadDuration = metrics.NewHistogram(
+		"yukki_download_duration_seconds",
+		"Time taken by track downloads, including failed ones.",
+		metrics.DefaultBuckets,
+	)
+	downloadFailures = metrics.NewCounter(
+		"yukki_download_failures_total",
+		"Failed download attempts, by platform.",
+		"platform",
+	)
+)
+
 // Register adds a platform to the registry with given priority
 // Higher priority = checked first for URL validation
 func Register(priority int, platform state.Platform) {
@@ -95,10 +116,23 @@ func FindPlatform(url string) state.Platform {
 	return nil
 }
 
+// Search platforms for plain text queries, chosen per chat in /settings.
+const (
+	SearchYouTube    = "youtube"
+	SearchSoundCloud = "soundcloud"
+)
+
+var SearchPlatforms = []string{SearchYouTube, SearchSoundCloud}
+
 // GetTracks extracts tracks from the given query
 // Automatically detects the appropriate platform
-func GetTracks(m *telegram.NewMessage, video bool) ([]*state.Track, error) {
-	gologging.Debug("GetTracks called")
+// Plain text queries are searched on the search platform, YouTube if empty.
+func GetTracks(
+	m *telegram.NewMessage,
+	video bool,
+	search string,
+) ([]*state.Track, error) {
+	logger.Debug("GetTracks called")
 
 	urls, _ := utils.ExtractURLs(m)
 	query := m.Args()
@@ -110,33 +144,33 @@ func GetTracks(m *telegram.NewMessage, video bool) ([]*state.Track, error) {
 	if len(urls) > 0 {
 
 		for _, url := range urls {
-			gologging.Info("Processing URL: " + url)
+			logger.Info("Processing URL: " + url)
 
 			platform := FindPlatform(url)
 			if platform == nil {
 				errMsg := "No platform found for URL: " + url
-				gologging.Error(errMsg)
+				logger.Error(errMsg)
 				errorsL = append(errorsL, errMsg)
 				continue
 			}
 
-			gologging.Debug("Found platform: " + string(platform.Name()))
+			logger.Debug("Found platform: " + string(platform.Name()))
 
 			tracks, err := platform.GetTracks(url, video)
 			if err != nil {
 				errMsg := string(platform.Name()) + ": " + err.Error()
-				gologging.Error(errMsg)
+				logger.Error(errMsg)
 				errorsL = append(errorsL, errMsg)
 				continue
 			}
 
-			gologging.Info("Tracks found: " + strconv.Itoa(len(tracks)))
+			logger.Info("Tracks found: " + strconv.Itoa(len(tracks)))
 			allTracks = append(allTracks, tracks...)
 		}
 
 		// If we have tracks from URLs, return them
 		if len(allTracks) > 0 {
-			gologging.Info("Returning tracks from URLs")
+			logger.Info("Returning tracks from URLs")
 			return allTracks, nil
 		}
 
@@ -147,30 +181,41 @@ func GetTracks(m *telegram.NewMessage, video bool) ([]*state.Track, error) {
 
 	}
 
-	// If no URLs but have query, search YouTube
+	// If no URLs but have query, search the chat's search platform
 	if query != "" {
-		gologging.Info("No URLs found, searching YouTube with query: " + query)
+		if search == SearchSoundCloud {
+			logger.Info("No URLs found, searching SoundCloud with query: " + query)
+
+			tracks, err := searchSoundCloud(query)
+			if err == nil && len(tracks) > 0 {
+				logger.Info("SoundCloud track found, returning first result")
+				return updateVideoFlag(tracks[:1], false), nil
+			}
+			logger.Warn("SoundCloud search found nothing, falling back to YouTube")
+		}
+
+		logger.Info("No URLs found, searching YouTube with query: " + query)
 
 		yt := &YouTubePlatform{}
 		tracks, err := yt.GetTracks(query, video)
 		if err != nil {
-			gologging.Error("YouTube search failed: " + err.Error())
+			logger.Error("YouTube search failed: " + err.Error())
 			return nil, err
 		}
 
 		if len(tracks) > 0 {
-			gologging.Info("YouTube track found, returning first result")
+			logger.Info("YouTube track found, returning first result")
 			return []*state.Track{tracks[0]}, nil
 		}
 	}
 
 	// Handle reply messages
 	if m.IsReply() {
-		gologging.Debug("Message is a reply, checking media")
+		logger.Debug("Message is a reply, checking media")
 
 		rmsg, err := m.GetReplyMessage()
 		if err != nil {
-			gologging.Error("Failed to get replied message: " + err.Error())
+			logger.Error("Failed to get replied message: " + err.Error())
 			return nil, errors.New(
 				"failed to get replied message: " + err.Error(),
 			)
@@ -178,7 +223,7 @@ func GetTracks(m *telegram.NewMessage, video bool) ([]*state.Track, error) {
 
 		if !(rmsg.IsMedia() &&
 			(rmsg.Audio() != nil || rmsg.Video() != nil || rmsg.Voice() != nil || rmsg.Document() != nil)) {
-			gologging.Info("Reply does not contain valid media")
+			logger.Info("Reply does not contain valid media")
 			return nil, errors.New("⚠️ Reply with a valid media (audio/video)")
 		}
 
@@ -201,23 +246,23 @@ func GetTracks(m *telegram.NewMessage, video bool) ([]*state.Track, error) {
 		}
 
 		if !isAudio && !isVideo {
-			gologging.Info("Replied media is neither audio nor video")
+			logger.Info("Replied media is neither audio nor video")
 			return nil, errors.New("⚠️ Reply with a valid media (audio/video)")
 		}
 
 		t, err := tg.GetTracksByMessage(rmsg)
 		if err != nil {
 			errMsg := "Failed to get track from reply: " + err.Error()
-			gologging.Error(errMsg)
+			logger.Error(errMsg)
 			errorsL = append(errorsL, errMsg)
 		} else {
 			t.Video = isVideo
 
 			if isVideo {
-				gologging.Debug("Reply media is video, preparing thumbnail")
+				logger.Debug("Reply media is video, preparing thumbnail")
 
 				if err := os.MkdirAll("cache", os.ModePerm); err != nil {
-					gologging.Error("Failed to create cache folder: " + err.Error())
+					logger.Error("Failed to create cache folder: " + err.Error())
 					return []*state.Track{t}, nil
 				}
 
@@ -230,26 +275,53 @@ func GetTracks(m *telegram.NewMessage, video bool) ([]*state.Track, error) {
 					if err == nil {
 						if _, err := os.Stat(path); err == nil {
 							t.Artwork = path
-							gologging.Debug("Thumbnail saved: " + path)
+							logger.Debug("Thumbnail saved: " + path)
 						}
 					}
 				}
 			}
 
-			gologging.Info("Returning track from reply message")
+			logger.Info("Returning track from reply message")
 			return []*state.Track{t}, nil
 		}
 	}
 
 	if len(errorsL) > 0 {
-		gologging.Error("Returning aggregated errors")
+		logger.Error("Returning aggregated errors")
 		return nil, formatErrors(errorsL)
 	}
 
-	gologging.Info("No tracks found")
+	logger.Info("No tracks found")
 	return nil, errors.New("no tracks found")
 }
 
+// GetTracksByQuery resolves a URL or a YouTube search query without a
+// Telegram message, for callers outside of chat commands.
+func GetTracksByQuery(query string, video bool) ([]*state.Track, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("empty query")
+	}
+
+	if strings.HasPrefix(query, "http://") || strings.HasPrefix(query, "https://") {
+		platform := FindPlatform(query)
+		if platform == nil {
+			return nil, errors.New("No supported platform for given URL(s)")
+		}
+		return platform.GetTracks(query, video)
+	}
+
+	yt := &YouTubePlatform{}
+	tracks, err := yt.GetTracks(query, video)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, errors.New("no tracks found")
+	}
+	return tracks[:1], nil
+}
+
 // Download attempts to download a track using available downloaders
 func Download(
 	ctx context.Context,
@@ -258,6 +330,13 @@ func Download(
 ) (string, error) {
 	var errs []string
 
+	downloadsInFlight.Inc()
+	defer downloadsInFlight.Dec()
+	start := time.Now()
+	defer func() {
+		downloadDuration.Observe(time.Since(start).Seconds())
+	}()
+
 	for _, p := range GetOrderedPlatforms() {
 		if !p.IsDownloadSupported(track.Source) {
 			continue
@@ -275,6 +354,11 @@ func Download(
 			return "", err
 		}
 
+		downloadFailures.Inc(string(p.Name()))
+		logger.WarnF(
+			"Download attempt failed platform=%s track_id=%s: %v",
+			p.Name(), track.ID, err,
+		)
 		errs = append(errs, string(p.Name())+": "+err.Error())
 	}
 
@@ -23,7 +23,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 	"resty.dev/v3"
 
@@ -141,7 +140,7 @@ func (d *DirectStreamPlatform) Download(
 ) (string, error) {
 	// For direct streams, we don't download - just return the URL
 	// The streaming system will handle it directly
-	gologging.InfoF(
+	logger.InfoF(
 		"DirectStream: Returning URL for direct streaming: %s",
 		track.URL,
 	)
@@ -321,7 +320,7 @@ func (d *DirectStreamPlatform) enrichMetadata(
 		}
 	}
 
-	gologging.InfoF(
+	logger.InfoF(
 		"DirectStream metadata: %s (audio: %v, video: %v, size: %d, duration: %d)",
 		track.Title,
 		info.IsAudio,
@@ -33,7 +33,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	state "main/internal/core/models"
@@ -74,28 +73,28 @@ func (s *SoundCloudPlatform) GetTracks(
 	query = strings.TrimSpace(query)
 	if query == "" {
 		err := errors.New("empty query")
-		gologging.Error("SoundCloud: " + err.Error())
+		logger.Error("SoundCloud: " + err.Error())
 		return nil, err
 	}
 
 	cacheKey := "soundcloud:" + strings.ToLower(query)
 	if cached, ok := soundcloudCache.Get(cacheKey); ok {
-		gologging.Debug("SoundCloud: Using cached tracks")
+		logger.Debug("SoundCloud: Using cached tracks")
 		return cached, nil
 	}
 
-	gologging.InfoF("SoundCloud: Fetching metadata for %s", query)
+	logger.InfoF("SoundCloud: Fetching metadata for %s", query)
 
 	info, err := s.extractMetadata(query)
 	if err != nil {
-		gologging.ErrorF("SoundCloud: Failed to extract metadata: %v", err)
+		logger.ErrorF("SoundCloud: Failed to extract metadata: %v", err)
 		return nil, fmt.Errorf("failed to extract metadata: %w", err)
 	}
 
 	var tracks []*state.Track
 
 	if len(info.Entries) > 0 {
-		gologging.InfoF(
+		logger.InfoF(
 			"SoundCloud: Found playlist with %d tracks",
 			len(info.Entries),
 		)
@@ -110,7 +109,7 @@ func (s *SoundCloudPlatform) GetTracks(
 
 	if len(tracks) > 0 {
 		soundcloudCache.Set(cacheKey, tracks)
-		gologging.InfoF(
+		logger.InfoF(
 			"SoundCloud: Successfully extracted %d track(s)",
 			len(tracks),
 		)
@@ -119,6 +118,12 @@ func (s *SoundCloudPlatform) GetTracks(
 	return tracks, nil
 }
 
+// searchSoundCloud returns the best SoundCloud match for a text query.
+func searchSoundCloud(query string) ([]*state.Track, error) {
+	sc := &SoundCloudPlatform{name: PlatformSoundCloud}
+	return sc.GetTracks("scsearch1:"+query, false)
+}
+
 func (s *SoundCloudPlatform) IsDownloadSupported(
 	source state.PlatformName,
 ) bool {
@@ -131,14 +136,14 @@ func (s *SoundCloudPlatform) Download(
 	_ *telegram.NewMessage,
 ) (string, error) {
 	if path, err := checkDownloadedFile(track.ID); err == nil {
-		gologging.InfoF("SoundCloud: Using cached file for %s", track.ID)
+		logger.InfoF("SoundCloud: Using cached file for %s", track.ID)
 		return path, nil
 	}
 
-	gologging.InfoF("SoundCloud: Downloading %s", track.Title)
+	logger.InfoF("SoundCloud: Downloading %s", track.Title)
 
 	if err := ensureDownloadsDir(); err != nil {
-		gologging.ErrorF(
+		logger.ErrorF(
 			"SoundCloud: Failed to create downloads directory: %v",
 			err,
 		)
@@ -172,7 +177,7 @@ func (s *SoundCloudPlatform) Download(
 	if err := cmd.Run(); err != nil {
 		outStr := stdout.String()
 		errStr := stderr.String()
-		gologging.ErrorF(
+		logger.ErrorF(
 			"SoundCloud: yt-dlp download failed for %s: %v\nSTDOUT:\n%s\nSTDERR:\n%s",
 			track.URL,
 			err,
@@ -196,11 +201,11 @@ func (s *SoundCloudPlatform) Download(
 	}
 
 	if _, err := os.Stat(filePath); err != nil {
-		gologging.ErrorF("SoundCloud: Downloaded file not found: %v", err)
+		logger.ErrorF("SoundCloud: Downloaded file not found: %v", err)
 		return "", fmt.Errorf("downloaded file not found: %w", err)
 	}
 
-	gologging.InfoF("SoundCloud: Successfully downloaded %s", track.Title)
+	logger.InfoF("SoundCloud: Successfully downloaded %s", track.Title)
 	return filePath, nil
 }
 
@@ -221,7 +226,7 @@ func (s *SoundCloudPlatform) extractMetadata(url string) (*ytdlpInfo, error) {
 
 	if err := cmd.Run(); err != nil {
 		errStr := stderr.String()
-		gologging.ErrorF(
+		logger.ErrorF(
 			"SoundCloud: yt-dlp metadata extraction failed: %v\n%s",
 			err,
 			errStr,
@@ -239,7 +244,7 @@ func (s *SoundCloudPlatform) extractMetadata(url string) (*ytdlpInfo, error) {
 		for _, line := range lines {
 			var entry ytdlpInfo
 			if err := json.Unmarshal([]byte(line), &entry); err != nil {
-				gologging.ErrorF(
+				logger.ErrorF(
 					"SoundCloud: Failed to parse entry JSON: %v",
 					err,
 				)
@@ -250,7 +255,7 @@ func (s *SoundCloudPlatform) extractMetadata(url string) (*ytdlpInfo, error) {
 
 		if len(info.Entries) == 0 {
 			err := errors.New("no valid entries found in playlist")
-			gologging.Error("SoundCloud: " + err.Error())
+			logger.Error("SoundCloud: " + err.Error())
 			return nil, err
 		}
 
@@ -259,7 +264,7 @@ func (s *SoundCloudPlatform) extractMetadata(url string) (*ytdlpInfo, error) {
 
 	var info ytdlpInfo
 	if err := json.Unmarshal([]byte(output), &info); err != nil {
-		gologging.ErrorF("SoundCloud: Failed to parse JSON: %v", err)
+		logger.ErrorF("SoundCloud: Failed to parse JSON: %v", err)
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
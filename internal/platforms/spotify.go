@@ -29,7 +29,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
@@ -148,64 +147,19 @@ func (s *SpotifyPlatform) Download(
 	track *state.Track,
 	mystic *telegram.NewMessage,
 ) (string, error) {
-	yt := &YouTubePlatform{}
-
-	clean := cleanTitle(track.Title)
-	trimmed := trimTitleLen(clean, 25, 40)
-
-	var queries []string
-
-	if clean != "" {
-		queries = append(queries, clean)
-	}
-
-	if clean != "" && trimmed != "" && trimmed != clean {
-		queries = append(queries, clean+" "+trimmed)
-	}
-
-	if trimTitleLen(track.Title, 25, 40) != "" {
-		queries = append(queries, trimTitleLen(track.Title, 25, 40))
-	}
-
-	var ytTrack *state.Track
-
-	for i, q := range queries {
-		gologging.DebugF(
-			"[Spotify→YouTube] Search attempt %d: %q",
-			i+1,
-			q,
-		)
-
-		ytTracks, err := yt.VideoSearch(q, true)
-		if err != nil || len(ytTracks) == 0 {
-			gologging.DebugF(
-				"[Spotify→YouTube] No result for %q (err=%v)",
-				q,
-				err,
-			)
-			continue
-		}
-
-		ytTrack = ytTracks[0]
-		ytTrack.Video = track.Video
-
-		gologging.DebugF(
-			"[Spotify→YouTube] Match found using %q → %s",
-			q,
-			ytTrack.URL,
-		)
-		break
+	matches, err := SpotifyMatches(track)
+	if err != nil {
+		return "", err
 	}
 
-	if ytTrack == nil {
-		return "", errors.New("failed to find track on YouTube")
-	}
+	ytTrack := *matches[0]
+	ytTrack.Video = track.Video
 
 	for _, p := range GetOrderedPlatforms() {
 		if p.IsDownloadSupported(PlatformYouTube) {
-			path, err := p.Download(ctx, ytTrack, mystic)
+			path, err := p.Download(ctx, &ytTrack, mystic)
 			if err == nil {
-				gologging.InfoF(
+				logger.InfoF(
 					"Downloaded Spotify track '%s' from YouTube: %s",
 					track.Title,
 					ytTrack.URL,
@@ -213,7 +167,7 @@ func (s *SpotifyPlatform) Download(
 				return path, nil
 			}
 
-			gologging.DebugF(
+			logger.DebugF(
 				"[Spotify→YouTube] Downloader %T failed: %v",
 				p,
 				err,
@@ -242,7 +196,7 @@ func (s *SpotifyPlatform) ensureClient() error {
 		httpClient := spotifyauth.New().Client(context.Background(), token)
 		s.client = spotify.New(httpClient)
 
-		gologging.Info("Spotify client initialized successfully")
+		logger.Info("Spotify client initialized successfully")
 	})
 
 	return s.initErr
@@ -363,8 +317,6 @@ func (s *SpotifyPlatform) convertSpotifyTrack(
 	for _, artist := range simpleTrack.Artists {
 		artists = append(artists, artist.Name)
 	}
-	artistStr := strings.Join(artists, ", ")
-	_ = artistStr
 
 	thumbnail := ""
 	if len(images) > 0 {
@@ -381,6 +333,7 @@ func (s *SpotifyPlatform) convertSpotifyTrack(
 		Artwork:  thumbnail,
 		URL:      simpleTrack.ExternalURLs["spotify"],
 		Source:   PlatformSpotify,
+		Artists:  artists,
 	}
 
 	return track
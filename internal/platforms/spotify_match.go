@@ -0,0 +1,310 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package platforms
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	state "main/internal/core/models"
+	"main/internal/database"
+	"main/internal/utils"
+)
+
+const (
+	// matchDurationTolerance is how far, in seconds, a YouTube video may be
+	// from the Spotify length and still count as the same recording.
+	matchDurationTolerance = 10
+	// matchGoodScore stops searching further queries once reached.
+	matchGoodScore = 70
+	// matchKeep is how many ranked candidates are kept for /wrongsong.
+	matchKeep = 6
+)
+
+// matchPenaltyWords mark another version of a song. They cost a candidate
+// points unless the Spotify title has them too.
+var matchPenaltyWords = []string{
+	"live",
+	"cover",
+	"remix",
+	"sped up",
+	"slowed",
+	"reverb",
+	"nightcore",
+	"karaoke",
+	"instrumental",
+	"8d",
+	"acoustic",
+	"mashup",
+}
+
+// spotifyMatchCache holds the ranked YouTube candidates of a Spotify track,
+// keyed by the Spotify track ID. The first one is what gets downloaded; a
+// /wrongsong pick is stored in the database and put first again once the
+// cache entry expires.
+var spotifyMatchCache = utils.NewCache[string, []*state.Track](24 * time.Hour)
+
+// SpotifyMatches returns the YouTube candidates for a Spotify track, best
+// first.
+func SpotifyMatches(track *state.Track) ([]*state.Track, error) {
+	if cached, ok := spotifyMatchCache.Get(track.ID); ok && len(cached) > 0 {
+		return cached, nil
+	}
+
+	yt := &YouTubePlatform{}
+	seen := make(map[string]bool)
+
+	type scored struct {
+		track *state.Track
+		score float64
+	}
+	var candidates []scored
+	best := math.Inf(-1)
+
+	for i, q := range spotifyQueries(track) {
+		logger.DebugF("[Spotify→YouTube] Search attempt %d: %q", i+1, q)
+
+		results, err := yt.VideoSearch(q)
+		if err != nil || len(results) == 0 {
+			logger.DebugF("[Spotify→YouTube] No result for %q (err=%v)", q, err)
+			continue
+		}
+
+		for _, r := range results {
+			if seen[r.ID] {
+				continue
+			}
+			seen[r.ID] = true
+
+			s := scoreMatch(track, r)
+			candidates = append(candidates, scored{r, s})
+			best = max(best, s)
+		}
+		if best >= matchGoodScore {
+			break
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("failed to find track on YouTube")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > matchKeep {
+		candidates = candidates[:matchKeep]
+	}
+
+	matches := make([]*state.Track, len(candidates))
+	for i, c := range candidates {
+		matches[i] = c.track
+	}
+	if pick, _ := database.GetSpotifyMatch(track.ID); pick != "" {
+		matches = pinMatch(yt, matches, pick)
+	}
+
+	logger.DebugF(
+		"[Spotify→YouTube] %q matched %s (score %.0f)",
+		track.Title,
+		matches[0].URL,
+		candidates[0].score,
+	)
+
+	spotifyMatchCache.Set(track.ID, matches)
+	return matches, nil
+}
+
+// SetSpotifyMatch makes a candidate of SpotifyMatches the one used for a
+// Spotify track and stores the pick. It reports false when the candidate
+// is unknown.
+func SetSpotifyMatch(spotifyID, youtubeID string, userID int64) bool {
+	matches, ok := spotifyMatchCache.Get(spotifyID)
+	if !ok || !slices.ContainsFunc(matches, func(m *state.Track) bool {
+		return m.ID == youtubeID
+	}) {
+		return false
+	}
+
+	spotifyMatchCache.Set(spotifyID, pinMatch(nil, matches, youtubeID))
+	database.SaveSpotifyMatch(&database.SpotifyMatch{
+		SpotifyID: spotifyID,
+		YouTubeID: youtubeID,
+		SetBy:     userID,
+		SetAt:     time.Now(),
+	})
+	return true
+}
+
+// pinMatch moves the video youtubeID to the front of matches. A video the
+// search no longer returns is looked up with yt; it is dropped when yt is
+// nil or the lookup fails.
+func pinMatch(yt *YouTubePlatform, matches []*state.Track, youtubeID string) []*state.Track {
+	i := slices.IndexFunc(matches, func(m *state.Track) bool {
+		return m.ID == youtubeID
+	})
+	if i < 0 {
+		if yt == nil {
+			return matches
+		}
+		found, err := yt.GetTracks("https://www.youtube.com/watch?v="+youtubeID, false)
+		if err != nil || len(found) == 0 {
+			logger.DebugF("[Spotify→YouTube] Stored pick %s unavailable: %v", youtubeID, err)
+			return matches
+		}
+		matches = append([]*state.Track{found[0]}, matches...)
+		return matches[:min(len(matches), matchKeep)]
+	}
+
+	pinned := make([]*state.Track, 0, len(matches))
+	pinned = append(pinned, matches[i])
+	pinned = append(pinned, matches[:i]...)
+	return append(pinned, matches[i+1:]...)
+}
+
+func spotifyQueries(track *state.Track) []string {
+	clean := cleanTitle(track.Title)
+	trimmed := trimTitleLen(clean, 25, 40)
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0]
+	}
+
+	var queries []string
+	add := func(q string) {
+		q = strings.TrimSpace(q)
+		if q != "" && !slices.Contains(queries, q) {
+			queries = append(queries, q)
+		}
+	}
+
+	if artist != "" {
+		add(clean + " " + artist)
+	}
+	add(clean)
+	if trimmed != clean {
+		add(trimmed + " " + artist)
+	}
+	add(trimTitleLen(track.Title, 25, 40))
+	return queries
+}
+
+// scoreMatch rates how likely a YouTube video is the same recording as a
+// Spotify track. Higher is better; a wrong version goes below zero.
+func scoreMatch(src, cand *state.Track) float64 {
+	score := 0.0
+
+	// Length is the strongest signal: other versions rarely match it
+	if src.Duration > 0 && cand.Duration > 0 {
+		diff := src.Duration - cand.Duration
+		if diff < 0 {
+			diff = -diff
+		}
+		switch {
+		case diff <= 2:
+			score += 35
+		case diff <= matchDurationTolerance:
+			score += 35 - float64(diff)*2
+		case diff <= 3*matchDurationTolerance:
+			score += 5
+		default:
+			score -= 30
+		}
+	}
+
+	srcTitle := normalizeMatchText(src.Title)
+	candTitle := normalizeMatchText(cand.Title)
+	channel := normalizeMatchText(cand.Channel)
+
+	// Share of the Spotify title's words found in the video title
+	if words := strings.Fields(normalizeMatchText(cleanTitle(src.Title))); len(words) > 0 {
+		found := 0
+		for _, w := range words {
+			if hasWord(candTitle, w) {
+				found++
+			}
+		}
+		score += 30 * float64(found) / float64(len(words))
+	}
+
+	// Artist as the uploader beats the artist named in the title. Names
+	// are compared whole, ignoring spaces ("TaylorSwiftVEVO"), so a short
+	// name does not match every channel containing it.
+	owner := strings.TrimSuffix(strings.TrimSuffix(channel, " topic"), "vevo")
+	owner = strings.ReplaceAll(owner, " ", "")
+	artistScore := 0.0
+	for _, artist := range src.Artists {
+		a := normalizeMatchText(artist)
+		if a == "" {
+			continue
+		}
+		switch {
+		case owner != "" && owner == strings.ReplaceAll(a, " ", ""):
+			artistScore = 20
+		case hasWord(candTitle, a):
+			artistScore = max(artistScore, 10)
+		}
+	}
+	score += artistScore
+
+	// Auto-generated topic channels carry the studio audio
+	switch {
+	case strings.HasSuffix(strings.ToLower(cand.Channel), " - topic"):
+		score += 15
+	case strings.Contains(candTitle, "official audio"):
+		score += 10
+	case strings.HasSuffix(channel, "vevo"),
+		strings.Contains(candTitle, "official video"),
+		strings.Contains(candTitle, "official music video"):
+		score += 5
+	}
+
+	for _, w := range matchPenaltyWords {
+		if hasWord(candTitle, w) && !hasWord(srcTitle, w) {
+			score -= 25
+		}
+	}
+
+	return score
+}
+
+// normalizeMatchText lowercases s and turns everything but letters and
+// digits into single spaces.
+func normalizeMatchText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// hasWord reports whether the normalized text contains the normalized
+// phrase as whole words.
+func hasWord(text, phrase string) bool {
+	return phrase != "" && strings.Contains(" "+text+" ", " "+phrase+" ")
+}
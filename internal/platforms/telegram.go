@@ -31,7 +31,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/core"
@@ -127,7 +126,7 @@ func (t *TelegramPlatform) GetTracks(
 	if isVideo {
 		err := os.MkdirAll("cache", os.ModePerm)
 		if err != nil {
-			gologging.Error("Failed to create cache folder: " + err.Error())
+			logger.Error("Failed to create cache folder: " + err.Error())
 			return []*state.Track{track}, nil
 		}
 
@@ -216,12 +216,14 @@ func (yp *YouTubePlatform) VideoSearch(
 					}
 					thumb := v.Thumbnails[len(v.Thumbnails)-1].URL
 					t := &state.Track{
-						ID:       v.ID,
-						Title:    v.Title,
-						Duration: v.Duration,
-						Artwork:  thumb,
-						URL:      v.URL,
-						Source:   PlatformYouTube,
+						ID:        v.ID,
+						Title:     v.Title,
+						Duration:  v.Duration,
+						Artwork:   thumb,
+						URL:       v.URL,
+						Source:    PlatformYouTube,
+						Channel:   v.Channel.Title,
+						ChannelID: v.Channel.ID,
 					}
 					tracks = append(tracks, t)
 					youtubeCache.Set("track:"+t.ID, []*state.Track{t})
@@ -406,6 +408,9 @@ func parseSearchResults(node interface{}, tracks *[]*state.Track) {
 			title := safeString(dig(vid, "title", "runs", 0, "text"))
 			thumb := safeString(dig(vid, "thumbnail", "thumbnails", 0, "url"))
 			durationText := safeString(dig(vid, "lengthText", "simpleText"))
+			channel := safeString(dig(vid, "ownerText", "runs", 0, "text"))
+			channelID := safeString(dig(vid, "ownerText", "runs", 0,
+				"navigationEndpoint", "browseEndpoint", "browseId"))
 
 			if durationText == "" {
 				return
@@ -413,12 +418,14 @@ func parseSearchResults(node interface{}, tracks *[]*state.Track) {
 
 			duration := parseDuration(durationText)
 			t := &state.Track{
-				URL:      "https://www.youtube.com/watch?v=" + id,
-				Title:    title,
-				ID:       id,
-				Artwork:  thumb,
-				Duration: duration,
-				Source:   PlatformYouTube,
+				URL:       "https://www.youtube.com/watch?v=" + id,
+				Title:     title,
+				ID:        id,
+				Artwork:   thumb,
+				Duration:  duration,
+				Source:    PlatformYouTube,
+				Channel:   channel,
+				ChannelID: channelID,
 			}
 			*tracks = append(*tracks, t)
 			youtubeCache.Set("track:"+t.ID, []*state.Track{t})
@@ -26,7 +26,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 	"resty.dev/v3"
 
@@ -108,7 +107,7 @@ func (y *YoutubifyPlatform) Download(
 			errors.Is(err, context.DeadlineExceeded) {
 			return "", err
 		}
-		gologging.Error(
+		logger.Error(
 			"Failed to download song using YoutubifyPlatform: " + sanitizeAPIError(
 				err,
 				config.YoutubifyApiKey,
@@ -31,9 +31,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
 
 	"main/internal/cookies"
@@ -94,17 +94,17 @@ func (y *YtDlpDownloader) GetTracks(
 ) ([]*state.Track, error) {
 	query = strings.TrimSpace(query)
 
-	gologging.InfoF("YtDlp: Extracting metadata for %s", query)
+	logger.InfoF("YtDlp: Extracting metadata for %s", query)
 
 	info, err := y.extractMetadata(query)
 	if err != nil {
-		gologging.ErrorF("YtDlp: Failed to extract metadata: %v", err)
+		logger.ErrorF("YtDlp: Failed to extract metadata: %v", err)
 		return nil, fmt.Errorf("failed to extract metadata: %w", err)
 	}
 
 	// Check if it's a live stream
 	if info.IsLive {
-		gologging.Info("YtDlp: Detected live stream, returning error")
+		logger.Info("YtDlp: Detected live stream, returning error")
 		return nil, errors.New(
 			"live streams are not supported by yt-dlp downloader",
 		)
@@ -114,7 +114,7 @@ func (y *YtDlpDownloader) GetTracks(
 
 	// Handle playlists
 	if len(info.Entries) > 0 {
-		gologging.InfoF(
+		logger.InfoF(
 			"YtDlp: Found playlist with %d entries",
 			len(info.Entries),
 		)
@@ -131,7 +131,7 @@ func (y *YtDlpDownloader) GetTracks(
 	}
 
 	if len(tracks) > 0 {
-		gologging.InfoF(
+		logger.InfoF(
 			"YtDlp: Successfully extracted %d track(s)",
 			len(tracks),
 		)
@@ -153,11 +153,11 @@ func (y *YtDlpDownloader) Download(
 ) (string, error) {
 	// Cache check
 	if path, err := checkDownloadedFile(track.ID); err == nil {
-		gologging.InfoF("YtDlp: Using cached file for %s", track.ID)
+		logger.InfoF("YtDlp: Using cached file for %s", track.ID)
 		return path, nil
 	}
 
-	gologging.InfoF("YtDlp: Downloading %s", track.Title)
+	logger.InfoF("YtDlp: Downloading %s", track.Title)
 
 	if err := ensureDownloadsDir(); err != nil {
 		return "", fmt.Errorf("failed to create downloads directory: %w", err)
@@ -178,8 +178,13 @@ func (y *YtDlpDownloader) Download(
 	// Format selection
 	if y.isYouTubeURL(track.URL) {
 		if track.Video {
+			height := 720
+			if track.MaxHeight > 0 && track.MaxHeight < height {
+				height = track.MaxHeight
+			}
+			h := strconv.Itoa(height)
 			args = append(args,
-				"-f", "bestvideo[height<=720]+bestaudio/best[height<=720]",
+				"-f", "bestvideo[height<="+h+"]+bestaudio/best[height<="+h+"]",
 				"--merge-output-format", "mp4",
 			)
 		} else {
@@ -216,7 +221,7 @@ func (y *YtDlpDownloader) Download(
 		outStr := strings.TrimSpace(stdout.String())
 		errStr := strings.TrimSpace(stderr.String())
 
-		gologging.ErrorF(
+		logger.ErrorF(
 			"YtDlp: Download failed for %s: %v\nSTDOUT:\n%s\nSTDERR:\n%s",
 			track.URL, err, outStr, errStr,
 		)
@@ -225,7 +230,7 @@ func (y *YtDlpDownloader) Download(
 		if files, globErr := filepath.Glob(pattern); globErr == nil {
 			for _, f := range files {
 				_ = os.Remove(f)
-				gologging.InfoF("YtDlp: Removed partial file %s", f)
+				logger.InfoF("YtDlp: Removed partial file %s", f)
 			}
 		}
 
@@ -247,7 +252,7 @@ func (y *YtDlpDownloader) Download(
 		return "", fmt.Errorf("downloaded file not found: %w", err)
 	}
 
-	gologging.InfoF("YtDlp: Successfully downloaded %s", finalPath)
+	logger.InfoF("YtDlp: Successfully downloaded %s", finalPath)
 	return finalPath, nil
 }
 
@@ -278,7 +283,7 @@ func (y *YtDlpDownloader) extractMetadata(urlStr string) (*ytdlpInfo, error) {
 
 	if err := cmd.Run(); err != nil {
 		errStr := stderr.String()
-		gologging.ErrorF(
+		logger.ErrorF(
 			"YtDlp: Metadata extraction failed: %v\n%s",
 			err,
 			errStr,
@@ -297,7 +302,7 @@ func (y *YtDlpDownloader) extractMetadata(urlStr string) (*ytdlpInfo, error) {
 		for _, line := range lines {
 			var entry ytdlpInfo
 			if err := json.Unmarshal([]byte(line), &entry); err != nil {
-				gologging.ErrorF("YtDlp: Failed to parse entry JSON: %v", err)
+				logger.ErrorF("YtDlp: Failed to parse entry JSON: %v", err)
 				continue
 			}
 			info.Entries = append(info.Entries, entry)
@@ -313,7 +318,7 @@ func (y *YtDlpDownloader) extractMetadata(urlStr string) (*ytdlpInfo, error) {
 	// Single video/audio
 	var info ytdlpInfo
 	if err := json.Unmarshal([]byte(output), &info); err != nil {
-		gologging.ErrorF("YtDlp: Failed to parse JSON: %v", err)
+		logger.ErrorF("YtDlp: Failed to parse JSON: %v", err)
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
@@ -0,0 +1,91 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package radio
+
+import (
+	"io"
+	"strings"
+
+	"main/internal/utils"
+)
+
+// icyMetaInt is the number of audio bytes between two ICY metadata blocks.
+const icyMetaInt = 16000
+
+type chunkWriter interface {
+	Write(p []byte) error
+}
+
+type plainWriter struct {
+	w io.Writer
+}
+
+func (pw plainWriter) Write(p []byte) error {
+	_, err := pw.w.Write(p)
+	return err
+}
+
+// icyWriter interleaves SHOUTcast/Icecast metadata blocks with the audio,
+// as requested by clients sending "Icy-MetaData: 1".
+type icyWriter struct {
+	w         io.Writer
+	remaining int
+	title     func() string
+	lastTitle string
+	sent      bool
+}
+
+func (iw *icyWriter) Write(p []byte) error {
+	for len(p) > 0 {
+		n := min(len(p), iw.remaining)
+		if _, err := iw.w.Write(p[:n]); err != nil {
+			return err
+		}
+		p = p[n:]
+		iw.remaining -= n
+
+		if iw.remaining == 0 {
+			if _, err := iw.w.Write(iw.metadata()); err != nil {
+				return err
+			}
+			iw.remaining = icyMetaInt
+		}
+	}
+	return nil
+}
+
+func (iw *icyWriter) metadata() []byte {
+	title := iw.title()
+	if iw.sent && title == iw.lastTitle {
+		return []byte{0}
+	}
+	iw.sent = true
+	iw.lastTitle = title
+
+	meta := "StreamTitle='" +
+		strings.ReplaceAll(utils.ShortTitle(title, 200), "'", "’") + "';"
+
+	blocks := (len(meta) + 15) / 16
+	buf := make([]byte, 1+blocks*16)
+	buf[0] = byte(blocks)
+	copy(buf[1:], meta)
+	return buf
+}
@@ -0,0 +1,160 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package radio
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"main/internal/config"
+	"main/internal/core"
+	"main/internal/database"
+	"main/internal/server"
+)
+
+var logger = config.GetLogger("radio")
+
+// Init registers the stream route on the shared HTTP server.
+func Init() {
+	server.HandleFunc("GET /rooms/{chatID}/stream", streamHandler)
+	logger.Info("Web radio enabled at /rooms/{chatID}/stream")
+}
+
+// Token returns the access token of a room's stream, creating one on first use.
+func Token(chatID int64) (string, error) {
+	token, err := database.GetRadioToken(chatID)
+	if err != nil || token != "" {
+		return token, err
+	}
+	return ResetToken(chatID)
+}
+
+// ResetToken replaces the access token of a room's stream, invalidating
+// every link shared before.
+func ResetToken(chatID int64) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(buf)
+	if err := database.SetRadioToken(chatID, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// StreamURL builds the public stream link of a room for the given format.
+func StreamURL(chatID int64, token, format string) string {
+	base := config.PublicURL
+	if base == "" {
+		base = "http://localhost:" + strconv.Itoa(config.HTTPPort)
+	}
+
+	url := base + "/rooms/" + strconv.FormatInt(chatID, 10) +
+		"/stream?token=" + token
+	if format != "" && format != formatMP3 {
+		url += "&format=" + format
+	}
+	return url
+}
+
+func validToken(chatID int64, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	expected, err := database.GetRadioToken(chatID)
+	if err != nil || expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(r.PathValue("chatID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid chat id", http.StatusBadRequest)
+		return
+	}
+
+	if !validToken(chatID, r.URL.Query().Get("token")) {
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = formatMP3
+	}
+	f, ok := formats[name]
+	if !ok {
+		http.Error(w, "unsupported format", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := core.GetRoom(chatID, nil); !ok {
+		http.Error(w, "room is not active", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	st, l := subscribe(chatID, f)
+	defer st.remove(l)
+
+	w.Header().Set("Content-Type", f.contentType)
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.Header().Set("Connection", "close")
+	w.Header().Set("icy-name", "Room "+strconv.FormatInt(chatID, 10))
+
+	var out chunkWriter = plainWriter{w}
+	if f.name == formatMP3 && r.Header.Get("Icy-MetaData") == "1" {
+		w.Header().Set("icy-metaint", strconv.Itoa(icyMetaInt))
+		out = &icyWriter{w: w, remaining: icyMetaInt, title: st.Title}
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	logger.DebugF("Listener connected to room %d (%s)", chatID, f.name)
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.DebugF("Listener left room %d (%s)", chatID, f.name)
+			return
+		case chunk, ok := <-l.ch:
+			if !ok {
+				return
+			}
+			if err := out.Write(chunk); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,522 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package radio
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/core"
+)
+
+const (
+	formatMP3  = "mp3"
+	formatOpus = "opus"
+
+	sampleRate    = 48000
+	channels      = 2
+	frameDuration = 20 * time.Millisecond
+	frameSize     = sampleRate * channels * 2 / 50 // 20ms of s16le PCM
+
+	syncInterval  = time.Second
+	idleTimeout   = 30 * time.Second
+	maxDrift      = 4 // seconds between the room and the radio before re-syncing
+	listenerQueue = 64
+	sourceBuffer  = 50 // decoded frames buffered ahead, one second
+)
+
+type format struct {
+	name        string
+	contentType string
+	args        []string
+}
+
+var formats = map[string]format{
+	formatMP3: {
+		name:        formatMP3,
+		contentType: "audio/mpeg",
+		args:        []string{"-c:a", "libmp3lame", "-b:a", "128k", "-f", "mp3"},
+	},
+	formatOpus: {
+		name:        formatOpus,
+		contentType: "audio/ogg",
+		args:        []string{"-c:a", "libopus", "-b:a", "96k", "-f", "ogg"},
+	},
+}
+
+var oggCapture = []byte("OggS")
+
+type stationKey struct {
+	chatID int64
+	format string
+}
+
+var (
+	stations   = make(map[stationKey]*station)
+	stationsMu sync.Mutex
+)
+
+type listener struct {
+	ch     chan []byte
+	synced bool
+}
+
+// station encodes the audio of one room into one format and fans it out to
+// every connected listener. A single encoder runs for the station's whole
+// life and is fed with PCM: the room's current track is decoded into it and
+// silence fills the gaps (pauses, track changes), so listeners receive one
+// continuous stream across queue transitions.
+type station struct {
+	key    stationKey
+	format format
+
+	mu        sync.Mutex
+	listeners map[*listener]struct{}
+	idleSince time.Time
+	title     string
+	header    []byte // ogg header pages, replayed to late listeners
+	headerOK  bool
+
+	encoder     *exec.Cmd
+	stdin       io.WriteCloser
+	encoderDone chan struct{}
+	src         *source
+}
+
+// source decodes the track currently fed into the station's encoder.
+type source struct {
+	cmd     *exec.Cmd
+	trackID string
+	path    string
+	speed   float64
+	pos     int
+	started time.Time
+	frames  chan []byte
+	stop    chan struct{}
+}
+
+type snapshot struct {
+	playing bool
+	trackID string
+	path    string
+	title   string
+	pos     int
+	speed   float64
+}
+
+func subscribe(chatID int64, f format) (*station, *listener) {
+	stationsMu.Lock()
+	defer stationsMu.Unlock()
+
+	key := stationKey{chatID: chatID, format: f.name}
+	st, ok := stations[key]
+	if !ok {
+		st = &station{
+			key:         key,
+			format:      f,
+			listeners:   make(map[*listener]struct{}),
+			idleSince:   time.Now(),
+			encoderDone: make(chan struct{}),
+		}
+		stations[key] = st
+		go st.run()
+	}
+	return st, st.add()
+}
+
+func (s *station) add() *listener {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := &listener{ch: make(chan []byte, listenerQueue), synced: true}
+	if s.format.name == formatOpus && s.headerOK {
+		l.ch <- s.header
+		l.synced = false
+	}
+	s.listeners[l] = struct{}{}
+	return l
+}
+
+func (s *station) remove(l *listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.listeners[l]; !ok {
+		return
+	}
+	delete(s.listeners, l)
+	close(l.ch)
+	if len(s.listeners) == 0 {
+		s.idleSince = time.Now()
+	}
+}
+
+// Title returns the title of the track currently on air.
+func (s *station) Title() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.title
+}
+
+func (s *station) run() {
+	defer s.shutdown()
+
+	if err := s.startEncoder(); err != nil {
+		logger.ErrorF(
+			"Failed to start %s encoder chat_id=%d: %v",
+			s.format.name, s.key.chatID, err,
+		)
+		return
+	}
+
+	silence := make([]byte, frameSize)
+	next := time.Now()
+	var lastSync time.Time
+
+	for {
+		select {
+		case <-s.encoderDone:
+			logger.WarnF(
+				"%s encoder chat_id=%d exited",
+				s.format.name, s.key.chatID,
+			)
+			return
+		default:
+		}
+
+		if time.Since(lastSync) >= syncInterval {
+			if s.idle() {
+				logger.DebugF(
+					"Stopping idle %s station chat_id=%d",
+					s.format.name, s.key.chatID,
+				)
+				return
+			}
+			s.sync()
+			lastSync = time.Now()
+		}
+
+		frame := silence
+		if s.src != nil {
+			select {
+			case f, ok := <-s.src.frames:
+				if ok {
+					frame = f
+				}
+			default:
+			}
+		}
+
+		if _, err := s.stdin.Write(frame); err != nil {
+			logger.ErrorF(
+				"Failed to feed %s encoder chat_id=%d: %v",
+				s.format.name, s.key.chatID, err,
+			)
+			return
+		}
+
+		next = next.Add(frameDuration)
+		if d := time.Until(next); d > 0 {
+			time.Sleep(d)
+		} else if d < -time.Second {
+			next = time.Now()
+		}
+	}
+}
+
+// idle reports whether the station has had no listeners for idleTimeout and,
+// if so, unregisters it so that new listeners get a fresh station.
+func (s *station) idle() bool {
+	stationsMu.Lock()
+	defer stationsMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.listeners) > 0 || time.Since(s.idleSince) < idleTimeout {
+		return false
+	}
+	delete(stations, s.key)
+	return true
+}
+
+func (s *station) shutdown() {
+	stationsMu.Lock()
+	if stations[s.key] == s {
+		delete(stations, s.key)
+	}
+	stationsMu.Unlock()
+
+	s.stopSource()
+
+	if s.encoder != nil {
+		s.stdin.Close()
+		s.encoder.Process.Kill()
+		<-s.encoderDone
+		s.encoder.Wait()
+	}
+
+	s.mu.Lock()
+	for l := range s.listeners {
+		delete(s.listeners, l)
+		close(l.ch)
+	}
+	s.mu.Unlock()
+}
+
+func (s *station) startEncoder() error {
+	args := []string{
+		"-v", "error",
+		"-f", "s16le",
+		"-ar", strconv.Itoa(sampleRate),
+		"-ac", strconv.Itoa(channels),
+		"-i", "pipe:0",
+	}
+	args = append(args, s.format.args...)
+	args = append(args, "pipe:1")
+
+	cmd := exec.Command("ffmpeg", args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	s.encoder = cmd
+	s.stdin = stdin
+
+	go func() {
+		defer close(s.encoderDone)
+		buf := make([]byte, 8192)
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				chunk := make([]byte, n)
+				copy(chunk, buf[:n])
+				s.broadcast(chunk)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *station) broadcast(chunk []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.format.name == formatOpus && !s.headerOK {
+		s.header = append(s.header, chunk...)
+		// OpusHead and OpusTags are the first two pages of the stream.
+		if i := nthIndex(s.header, oggCapture, 3); i >= 0 {
+			s.header = s.header[:i]
+			s.headerOK = true
+		}
+	}
+
+	for l := range s.listeners {
+		data := chunk
+		if !l.synced {
+			i := bytes.Index(chunk, oggCapture)
+			if i < 0 {
+				continue
+			}
+			data = chunk[i:]
+			l.synced = true
+		}
+
+		select {
+		case l.ch <- data:
+		default:
+			// Slow listener, drop it instead of stalling everyone else.
+			delete(s.listeners, l)
+			close(l.ch)
+			if len(s.listeners) == 0 {
+				s.idleSince = time.Now()
+			}
+		}
+	}
+}
+
+// sync makes the decoded source follow the room: it switches tracks on queue
+// transitions and re-seeks after pause, seek or speed changes.
+func (s *station) sync() {
+	snap := roomSnapshot(s.key.chatID)
+
+	s.mu.Lock()
+	s.title = snap.title
+	s.mu.Unlock()
+
+	if !snap.playing {
+		s.stopSource()
+		return
+	}
+
+	if src := s.src; src != nil &&
+		src.trackID == snap.trackID &&
+		src.path == snap.path &&
+		src.speed == snap.speed &&
+		abs(src.expectedPosition()-snap.pos) <= maxDrift {
+		return
+	}
+
+	s.stopSource()
+	if err := s.startSource(snap); err != nil {
+		logger.ErrorF(
+			"Failed to decode %s chat_id=%d: %v",
+			snap.path, s.key.chatID, err,
+		)
+	}
+}
+
+func (s *station) startSource(snap snapshot) error {
+	args := []string{"-v", "error"}
+	if strings.HasPrefix(snap.path, "http://") ||
+		strings.HasPrefix(snap.path, "https://") {
+		args = append(args,
+			"-reconnect", "1",
+			"-reconnect_streamed", "1",
+			"-reconnect_delay_max", "5",
+		)
+	}
+	if snap.pos > 0 {
+		args = append(args, "-ss", strconv.Itoa(snap.pos))
+	}
+	args = append(args, "-i", snap.path, "-vn")
+	if snap.speed != 1.0 {
+		args = append(args,
+			"-filter:a", "atempo="+strconv.FormatFloat(snap.speed, 'f', 2, 64),
+		)
+	}
+	args = append(args,
+		"-f", "s16le",
+		"-ac", strconv.Itoa(channels),
+		"-ar", strconv.Itoa(sampleRate),
+		"pipe:1",
+	)
+
+	cmd := exec.Command("ffmpeg", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	src := &source{
+		cmd:     cmd,
+		trackID: snap.trackID,
+		path:    snap.path,
+		speed:   snap.speed,
+		pos:     snap.pos,
+		started: time.Now(),
+		frames:  make(chan []byte, sourceBuffer),
+		stop:    make(chan struct{}),
+	}
+	s.src = src
+
+	go func() {
+		defer close(src.frames)
+		defer cmd.Wait()
+		for {
+			buf := make([]byte, frameSize)
+			if _, err := io.ReadFull(stdout, buf); err != nil {
+				return
+			}
+			select {
+			case src.frames <- buf:
+			case <-src.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *station) stopSource() {
+	if s.src == nil {
+		return
+	}
+	close(s.src.stop)
+	s.src.cmd.Process.Kill()
+	s.src = nil
+}
+
+func (src *source) expectedPosition() int {
+	return src.pos + int(time.Since(src.started).Seconds()*src.speed)
+}
+
+func roomSnapshot(chatID int64) snapshot {
+	r, ok := core.GetRoom(chatID, nil)
+	if !ok || !r.IsActiveChat() || r.IsPaused() || r.IsMuted() {
+		return snapshot{}
+	}
+
+	t := r.Track()
+	path := r.FilePath()
+	if t == nil || path == "" {
+		return snapshot{}
+	}
+
+	return snapshot{
+		playing: true,
+		trackID: t.ID,
+		path:    path,
+		title:   t.Title,
+		pos:     r.Position(),
+		speed:   r.Speed(),
+	}
+}
+
+func nthIndex(b, sep []byte, n int) int {
+	offset := 0
+	for i := 0; i < n; i++ {
+		j := bytes.Index(b[offset:], sep)
+		if j < 0 {
+			return -1
+		}
+		if i == n-1 {
+			return offset + j
+		}
+		offset += j + len(sep)
+	}
+	return -1
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
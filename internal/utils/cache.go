@@ -22,6 +22,7 @@ package utils
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -38,6 +39,9 @@ type Cache[K comparable, V any] struct {
 	mu         sync.RWMutex
 	items      map[K]CacheItem[V]
 	defaultTTL int64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
 }
 
 func NewCache[K comparable, V any](defaultTTL time.Duration) *Cache[K, V] {
@@ -70,15 +74,28 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 			delete(c.items, key)
 		}
 		c.mu.Unlock()
+		c.misses.Add(1)
 		var zero V
 		return zero, false
 	}
 	c.mu.Unlock()
+	c.hits.Add(1)
 	return item.Value, true
 }
 
+// Stats returns the number of lookups that hit and missed the cache.
+func (c *Cache[K, V]) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
 func (c *Cache[K, V]) Delete(key K) {
 	c.mu.Lock()
 	delete(c.items, key)
 	c.mu.Unlock()
 }
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	clear(c.items)
+	c.mu.Unlock()
+}
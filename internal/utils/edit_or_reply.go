@@ -21,10 +21,13 @@
 package utils
 
 import (
-	"github.com/Laky-64/gologging"
 	"github.com/amarnathcjd/gogram/telegram"
+
+	"main/internal/config"
 )
 
+var logger = config.GetLogger(config.DefaultLogger)
+
 func EOR(
 	msg *telegram.NewMessage,
 	text string,
@@ -37,7 +40,7 @@ func EOR(
 	}
 
 	if err != nil {
-		gologging.Error("[EOR] - " + err.Error())
+		logger.Error("[EOR] - " + err.Error())
 	}
 	return m, err
 }
@@ -25,8 +25,6 @@ import (
 	"encoding/json"
 	"os/exec"
 	"time"
-
-	"github.com/Laky-64/gologging"
 )
 
 const ffprobeTimeout = 7 * time.Second
@@ -54,13 +52,13 @@ func GetVideoDimensions(filePath string) (int, int) {
 
 	out, err := cmd.Output()
 	if ctx.Err() == context.DeadlineExceeded {
-		gologging.Error(
+		logger.Error(
 			"[getVideoDimensions] ffprobe timed out for " + filePath,
 		)
 		return 0, 0
 	}
 	if err != nil {
-		gologging.Error(
+		logger.Error(
 			"[getVideoDimensions] ffprobe failed for " + filePath + " : " + err.Error(),
 		)
 		return 0, 0
@@ -68,7 +66,7 @@ func GetVideoDimensions(filePath string) (int, int) {
 
 	var probe ffprobeOutput
 	if err := json.Unmarshal(out, &probe); err != nil {
-		gologging.Error(
+		logger.Error(
 			"[getVideoDimensions] failed to parse ffprobe JSON for " + filePath + " : " + err.Error(),
 		)
 		return 0, 0
@@ -86,7 +84,7 @@ func GetVideoDimensions(filePath string) (int, int) {
 		}
 	}
 
-	gologging.Error(
+	logger.Error(
 		"[getVideoDimensions] no valid video stream found for " + filePath,
 	)
 	return 0, 0
@@ -0,0 +1,178 @@
+/*
+  - This file is part of YukkiMusic.
+    *
+
+  - YukkiMusic — A Telegram bot that streams music into group voice chats with seamless playback and control.
+  - Copyright (C) 2025 TheTeamVivek
+    *
+  - This program is free software: you can redistribute it and/or modify
+  - it under the terms of the GNU General Public License as published by
+  - the Free Software Foundation, either version 3 of the License, or
+  - (at your option) any later version.
+    *
+  - This program is distributed in the hope that it will be useful,
+  - but WITHOUT ANY WARRANTY; without even the implied warranty of
+  - MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+  - GNU General Public License for more details.
+    *
+  - You should have received a copy of the GNU General Public License
+  - along with this program. If not, see <https://www.gnu.org/licenses/>.
+*/
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit allows Burst events at once, refilled evenly over Per. The zero
+// Limit allows everything.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+func (l Limit) IsZero() bool {
+	return l.Burst <= 0 || l.Per <= 0
+}
+
+// RateKey is one bucket checked by RateLimiter.Allow.
+type RateKey struct {
+	Key   string
+	Limit Limit
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	per    time.Duration
+	warned bool
+}
+
+// RateLimiter is a set of token buckets keyed by string. Buckets that have
+// been idle long enough to refill completely are dropped, since a fresh
+// bucket behaves the same.
+type RateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	sweepEvery time.Duration
+	lastSweep  time.Time
+}
+
+func NewRateLimiter(sweepEvery time.Duration) *RateLimiter {
+	return &RateLimiter{
+		buckets:    make(map[string]*bucket),
+		sweepEvery: sweepEvery,
+		lastSweep:  time.Now(),
+	}
+}
+
+// Allow takes one token from every bucket, or from none of them if any is
+// empty. When denied it returns the wait until the first empty bucket has a
+// token again and whether this is that bucket's first denial since it last
+// allowed an event, so callers can warn once instead of on every attempt.
+// The returned key is the bucket that denied.
+func (rl *RateLimiter) Allow(keys ...RateKey) (ok bool, wait time.Duration, warn bool, key string) {
+	now := time.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) >= rl.sweepEvery {
+		rl.sweep(now)
+	}
+
+	for _, k := range keys {
+		if k.Limit.IsZero() {
+			continue
+		}
+		b := rl.refill(k, now)
+		if b.tokens < 1 {
+			rate := float64(k.Limit.Burst) / k.Limit.Per.Seconds()
+			wait = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+			warn = !b.warned
+			b.warned = true
+			return false, wait, warn, k.Key
+		}
+	}
+
+	for _, k := range keys {
+		if k.Limit.IsZero() {
+			continue
+		}
+		b := rl.buckets[k.Key]
+		b.tokens--
+		b.warned = false
+	}
+	return true, 0, false, ""
+}
+
+// Cooldown allows one event per d for the key. It returns zero if the
+// event is allowed, or the time left otherwise.
+func (rl *RateLimiter) Cooldown(key string, d time.Duration) time.Duration {
+	ok, wait, _, _ := rl.Allow(RateKey{Key: key, Limit: Limit{Burst: 1, Per: d}})
+	if ok {
+		return 0
+	}
+	return wait
+}
+
+// Wait returns how long until the key's bucket has a token again, without
+// taking one. It is zero when an event would be allowed now.
+func (rl *RateLimiter) Wait(k RateKey) time.Duration {
+	if k.Limit.IsZero() {
+		return 0
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[k.Key]
+	if !ok {
+		return 0
+	}
+
+	burst := float64(k.Limit.Burst)
+	rate := burst / k.Limit.Per.Seconds()
+	tokens := b.tokens + time.Since(b.last).Seconds()*rate
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / rate * float64(time.Second))
+}
+
+// Len returns the number of live buckets.
+func (rl *RateLimiter) Len() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return len(rl.buckets)
+}
+
+func (rl *RateLimiter) refill(k RateKey, now time.Time) *bucket {
+	burst := float64(k.Limit.Burst)
+
+	b, ok := rl.buckets[k.Key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now, per: k.Limit.Per}
+		rl.buckets[k.Key] = b
+		return b
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens += elapsed * burst / k.Limit.Per.Seconds()
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	b.per = k.Limit.Per
+	return b
+}
+
+func (rl *RateLimiter) sweep(now time.Time) {
+	for key, b := range rl.buckets {
+		if now.Sub(b.last) >= b.per {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastSweep = now
+}
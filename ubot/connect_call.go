@@ -9,6 +9,10 @@ import (
 	"main/ntgcalls"
 )
 
+// ringTimeout is how long an outgoing private call rings before it is
+// given up.
+const ringTimeout = 45 * time.Second
+
 func (ctx *Context) connectCall(
 	chatId int64,
 	mediaDescription ntgcalls.MediaDescription,
@@ -43,6 +47,14 @@ func (ctx *Context) connectCall(
 			ctx.p2pConfigsMutex.Unlock()
 		}()
 
+		// Once the call exists, a failure must hang it up again or the
+		// next Play would only swap the sources of a dead call
+		failP2P := func(err error) error {
+			_ = ctx.binding.Stop(chatId)
+			_ = ctx.discardCall(chatId)
+			return signalError(err)
+		}
+
 		// Get or create P2P config
 		ctx.p2pConfigsMutex.Lock()
 		p2pConfig := ctx.p2pConfigs[chatId]
@@ -70,7 +82,7 @@ func (ctx *Context) connectCall(
 			mediaDescription,
 		)
 		if err != nil {
-			return signalError(err)
+			return failP2P(err)
 		}
 
 		ctx.p2pConfigsMutex.Lock()
@@ -84,7 +96,7 @@ func (ctx *Context) connectCall(
 
 		newGAorB, err := ctx.binding.InitExchange(chatId, dhConfig, gaOrB)
 		if err != nil {
-			return signalError(err)
+			return failP2P(err)
 		}
 
 		ctx.p2pConfigsMutex.Lock()
@@ -102,7 +114,7 @@ func (ctx *Context) connectCall(
 
 		userId, err := ctx.app.GetSendableUser(chatId)
 		if err != nil {
-			return signalError(err)
+			return failP2P(err)
 		}
 
 		ctx.inputCallsMutex.RLock()
@@ -114,8 +126,10 @@ func (ctx *Context) connectCall(
 		gaOrBHash := p2pConfig.GAorB
 		ctx.p2pConfigsMutex.RUnlock()
 
+		waitTimeout := 10 * time.Second
 		if isOutgoing {
-			_, err = ctx.app.PhoneRequestCall(
+			var requested *tg.PhonePhoneCall
+			requested, err = ctx.app.PhoneRequestCall(
 				&tg.PhoneRequestCallParams{
 					Protocol: protocol,
 					UserID:   userId,
@@ -126,8 +140,20 @@ func (ctx *Context) connectCall(
 				},
 			)
 			if err != nil {
-				return signalError(err)
+				return failP2P(err)
+			}
+			// Remember the call right away so it can be hung up even if
+			// it is declined before any update arrives
+			if waiting, ok := requested.PhoneCall.(*tg.PhoneCallWaiting); ok {
+				ctx.inputCallsMutex.Lock()
+				ctx.inputCalls[chatId] = &tg.InputPhoneCall{
+					ID:         waiting.ID,
+					AccessHash: waiting.AccessHash,
+				}
+				ctx.inputCallsMutex.Unlock()
 			}
+			// The other side has to pick up first
+			waitTimeout = ringTimeout
 		} else {
 			_, err = ctx.app.PhoneAcceptCall(
 				inputCall,
@@ -135,19 +161,24 @@ func (ctx *Context) connectCall(
 				protocol,
 			)
 			if err != nil {
-				return signalError(err)
+				return failP2P(err)
 			}
 		}
 
 		select {
 		case err = <-p2pConfig.WaitData:
 			if err != nil {
-				return signalError(err)
+				return failP2P(err)
 			}
-		case <-time.After(10 * time.Second):
-			return signalError(fmt.Errorf("timed out waiting for an answer"))
+		case <-time.After(waitTimeout):
+			return failP2P(fmt.Errorf("%w: timed out waiting for an answer", ErrCallDiscarded))
 		}
 
+		// Only known once the call was accepted for outgoing calls
+		ctx.inputCallsMutex.RLock()
+		inputCall = ctx.inputCalls[chatId]
+		ctx.inputCallsMutex.RUnlock()
+
 		ctx.p2pConfigsMutex.RLock()
 		gaOrB = p2pConfig.GAorB
 		fingerprint := p2pConfig.KeyFingerprint
@@ -155,7 +186,7 @@ func (ctx *Context) connectCall(
 
 		res, err := ctx.binding.ExchangeKeys(chatId, gaOrB, fingerprint)
 		if err != nil {
-			return signalError(err)
+			return failP2P(err)
 		}
 
 		ctx.p2pConfigsMutex.RLock()
@@ -170,7 +201,7 @@ func (ctx *Context) connectCall(
 				protocol,
 			)
 			if err != nil {
-				return signalError(err)
+				return failP2P(err)
 			}
 			ctx.p2pConfigsMutex.Lock()
 			p2pConfig.PhoneCall = confirmRes.PhoneCall.(*tg.PhoneCallObj)
@@ -188,7 +219,7 @@ func (ctx *Context) connectCall(
 			phoneCall.P2PAllowed,
 		)
 		if err != nil {
-			return signalError(err)
+			return failP2P(err)
 		}
 
 	} else {
@@ -260,5 +291,10 @@ func (ctx *Context) connectCall(
 		}
 	}
 
-	return <-waitChan
+	err := <-waitChan
+	if err != nil && chatId >= 0 {
+		_ = ctx.binding.Stop(chatId)
+		_ = ctx.discardCall(chatId)
+	}
+	return err
 }
@@ -3,12 +3,14 @@ package ubot
 import (
 	"sync"
 
-	"github.com/Laky-64/gologging"
 	tg "github.com/amarnathcjd/gogram/telegram"
 
+	"main/internal/config"
 	"main/ntgcalls"
 )
 
+var logger = config.GetLogger(config.DefaultLogger)
+
 type Context struct {
 	binding *ntgcalls.Client
 	app     *tg.Client
@@ -44,10 +46,12 @@ type Context struct {
 	waitConnectMutex sync.RWMutex
 	waitConnect      map[int64]chan error
 
-	callbacksMutex        sync.RWMutex
-	incomingCallCallbacks []func(client *Context, chatId int64)
-	streamEndCallbacks    []ntgcalls.StreamEndCallback
-	frameCallbacks        []ntgcalls.FrameCallback
+	callbacksMutex         sync.RWMutex
+	incomingCallCallbacks  []func(client *Context, chatId int64)
+	discardedCallCallbacks []func(client *Context, chatId int64)
+	participantsCallbacks  []func(client *Context, chatId int64)
+	streamEndCallbacks     []ntgcalls.StreamEndCallback
+	frameCallbacks         []ntgcalls.FrameCallback
 }
 
 func NewContext(app *tg.Client) *Context {
@@ -71,7 +75,7 @@ func NewContext(app *tg.Client) *Context {
 			var err error
 			me, err = app.GetMe()
 			if err != nil {
-				gologging.Fatal(err)
+				logger.Fatal(err)
 			}
 		}
 
@@ -90,6 +94,26 @@ func (ctx *Context) OnIncomingCall(
 	ctx.incomingCallCallbacks = append(ctx.incomingCallCallbacks, callback)
 }
 
+// OnCallDiscarded is called when the other side ends a private call,
+// either by declining it or by hanging up.
+func (ctx *Context) OnCallDiscarded(
+	callback func(client *Context, chatId int64),
+) {
+	ctx.callbacksMutex.Lock()
+	defer ctx.callbacksMutex.Unlock()
+	ctx.discardedCallCallbacks = append(ctx.discardedCallCallbacks, callback)
+}
+
+// OnParticipantsChange is called after participants of a group call joined,
+// left or changed
+func (ctx *Context) OnParticipantsChange(
+	callback func(client *Context, chatId int64),
+) {
+	ctx.callbacksMutex.Lock()
+	defer ctx.callbacksMutex.Unlock()
+	ctx.participantsCallbacks = append(ctx.participantsCallbacks, callback)
+}
+
 func (ctx *Context) OnStreamEnd(callback ntgcalls.StreamEndCallback) {
 	ctx.callbacksMutex.Lock()
 	defer ctx.callbacksMutex.Unlock()
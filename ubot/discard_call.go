@@ -0,0 +1,28 @@
+package ubot
+
+import (
+	"errors"
+
+	tg "github.com/amarnathcjd/gogram/telegram"
+)
+
+// ErrCallDiscarded is returned by Play when the other side of a private
+// call declined it, did not answer or hung up while it was set up.
+var ErrCallDiscarded = errors.New("call discarded")
+
+// discardCall hangs up the private call with chatId, if there is one.
+func (ctx *Context) discardCall(chatId int64) error {
+	ctx.inputCallsMutex.Lock()
+	inputCall := ctx.inputCalls[chatId]
+	delete(ctx.inputCalls, chatId)
+	ctx.inputCallsMutex.Unlock()
+
+	if inputCall == nil {
+		return nil
+	}
+	_, err := ctx.app.PhoneDiscardCall(&tg.PhoneDiscardCallParams{
+		Peer:   inputCall,
+		Reason: &tg.PhoneCallDiscardReasonHangup{},
+	})
+	return err
+}
@@ -1,7 +1,6 @@
 package ubot
 
 import (
-	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -107,9 +106,16 @@ func (ctx *Context) handleUpdates() {
 					reasonMessage = fmt.Sprintf("the user %d is busy", userId)
 				case *tg.PhoneCallDiscardReasonHangup:
 					reasonMessage = fmt.Sprintf("call declined by %d", userId)
+				case *tg.PhoneCallDiscardReasonMissed:
+					reasonMessage = fmt.Sprintf("the user %d did not answer", userId)
+				default:
+					reasonMessage = fmt.Sprintf("call with %d ended", userId)
 				}
 				if p2pConfig != nil {
-					p2pConfig.WaitData <- errors.New(reasonMessage)
+					select {
+					case p2pConfig.WaitData <- fmt.Errorf("%w: %s", ErrCallDiscarded, reasonMessage):
+					default:
+					}
 				}
 				ctx.inputCallsMutex.Lock()
 				delete(ctx.inputCalls, userId)
@@ -117,6 +123,19 @@ func (ctx *Context) handleUpdates() {
 
 				ctx.binding.Stop(userId)
 
+				// userId is only known when the call was still ours, not
+				// when we hung it up ourselves
+				if userId != 0 {
+					ctx.callbacksMutex.RLock()
+					callbacks := make([]func(client *Context, chatId int64), len(ctx.discardedCallCallbacks))
+					copy(callbacks, ctx.discardedCallCallbacks)
+					ctx.callbacksMutex.RUnlock()
+
+					for _, callback := range callbacks {
+						go callback(ctx, userId)
+					}
+				}
+
 			case *tg.PhoneCallRequested:
 				if p2pConfig == nil {
 					p2pConfigs, err := ctx.getP2PConfigs(call.GAHash)
@@ -247,6 +266,15 @@ func (ctx *Context) handleUpdates() {
 			ctx.callSourcesMutex.Unlock()
 			ctx.participantsMutex.Unlock()
 
+			ctx.callbacksMutex.RLock()
+			participantsCallbacks := make([]func(client *Context, chatId int64), len(ctx.participantsCallbacks))
+			copy(participantsCallbacks, ctx.participantsCallbacks)
+			ctx.callbacksMutex.RUnlock()
+
+			for _, callback := range participantsCallbacks {
+				go callback(ctx, chatId)
+			}
+
 			for _, update := range updates {
 				if update.cameraEndpoint != "" {
 					_, _ = ctx.binding.AddIncomingVideo(
@@ -458,6 +486,10 @@ func (ctx *Context) handleUpdates() {
 		inputGroupCall := ctx.inputGroupCalls[chatId]
 		ctx.inputGroupCallsMutex.RUnlock()
 
+		// Private calls have no participant status to update
+		if inputGroupCall == nil {
+			return
+		}
 		if err := ctx.setCallStatus(inputGroupCall, state); err != nil {
 			fmt.Println(err)
 		}
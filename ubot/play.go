@@ -13,11 +13,16 @@ func (ctx *Context) Play(
 		return err
 	}
 	if ctx.binding.Calls()[parsedChatId] != nil {
-		return ctx.binding.SetStreamSources(
+		err = ctx.binding.SetStreamSources(
 			parsedChatId,
 			ntgcalls.CaptureStream,
 			mediaDescription,
 		)
+		if err != nil || parsedChatId >= 0 {
+			return err
+		}
+		// The screen share follows the track, join or leave it as needed
+		return ctx.joinPresentation(parsedChatId, mediaDescription.Screen != nil)
 	}
 	err = ctx.connectCall(parsedChatId, mediaDescription, "")
 	if err != nil {
@@ -22,3 +22,20 @@ func (ctx *Context) Record(
 		mediaDescription,
 	)
 }
+
+// StopRecord detaches the Record sinks, which closes their input, and keeps
+// the call itself running
+func (ctx *Context) StopRecord(chatId any) error {
+	parsedChatId, err := ctx.parseChatId(chatId)
+	if err != nil {
+		return err
+	}
+	if ctx.binding.Calls()[parsedChatId] == nil {
+		return nil
+	}
+	return ctx.binding.SetStreamSources(
+		parsedChatId,
+		ntgcalls.PlaybackStream,
+		ntgcalls.MediaDescription{},
+	)
+}
@@ -24,7 +24,13 @@ func (ctx *Context) Stop(chatId any) error {
 	inputGroupCall, ok := ctx.inputGroupCalls[parsedChatId]
 	ctx.inputGroupCallsMutex.RUnlock()
 
-	if !ok { return stopErr }
+	if !ok {
+		// A private call has no group call to leave, hang it up instead
+		if discardErr := ctx.discardCall(parsedChatId); stopErr == nil {
+			stopErr = discardErr
+		}
+		return stopErr
+	}
 	_, leaveErr := ctx.app.PhoneLeaveGroupCall(inputGroupCall, 0)
 	if stopErr != nil {
 		return stopErr